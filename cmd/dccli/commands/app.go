@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/cfg"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+)
+
+// NewApp returns the dccli root command with its global flags and every
+// command group
+func NewApp(version string) *cli.Command {
+	app := &cli.Command{
+		Name:        "dccli",
+		Version:     version,
+		Usage:       "Discord CLI Tool - Manage Discord bots and guilds from the command line",
+		Description: "A powerful CLI for Discord API operations including guild management, messaging, roles, and more.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "Output format (table|json|yaml|ndjson|csv[=*]|tsv[=*]|custom-columns=HEADER:.path,...|jsonpath=TEMPLATE|go-template=TEMPLATE)",
+				Value:       "table",
+				Sources:     cli.EnvVars("DCLI_OUTPUT"),
+				DefaultText: "table",
+			},
+			&cli.StringFlag{
+				Name:  "template-file",
+				Usage: "Go template file to render output with, overrides --output",
+			},
+			&cli.StringSliceFlag{
+				Name:  "columns",
+				Usage: "Show these fields of the JSON output as table, csv or tsv columns (e.g. id,name,position)",
+			},
+			&cli.StringFlag{
+				Name:    "bot",
+				Aliases: []string{"b"},
+				Usage:   "Bot name to use (overrides current from config)",
+				Sources: cli.EnvVars("DCLI_BOT"),
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Usage:   "Config file path (default: ~/.dccli/config.yaml if it exists, else $XDG_CONFIG_HOME/dccli/config.yaml)",
+				Sources: cli.EnvVars(cfg.PathEnv),
+			},
+			&cli.StringFlag{
+				Name:    "context",
+				Usage:   "Context to use (overrides current-context from config)",
+				Sources: cli.EnvVars("DCLI_CONTEXT"),
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "Bot token (overrides config entirely)",
+				Sources: cli.EnvVars("DCLI_TOKEN"),
+			},
+			&cli.IntFlag{
+				Name:  utils.PassphraseFDFlag,
+				Usage: "Read the vault passphrase from this file descriptor (otherwise DCLI_VAULT_PASSPHRASE or a prompt)",
+			},
+			&cli.StringFlag{
+				Name:    "api-base",
+				Usage:   "REST API base URL for Discord-compatible servers (e.g. https://host/api/v9)",
+				Sources: cli.EnvVars("DCLI_API_BASE"),
+			},
+			&cli.StringFlag{
+				Name:    "gateway-url",
+				Usage:   "Gateway websocket URL (overrides the one returned by the API)",
+				Sources: cli.EnvVars("DCLI_GATEWAY_URL"),
+			},
+			&cli.StringFlag{
+				Name:    "reason",
+				Usage:   "Audit log reason attached to every create/edit/delete request",
+				Sources: cli.EnvVars("DCLI_REASON"),
+			},
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Usage:   "Print create/edit/delete API requests instead of sending them",
+				Sources: cli.EnvVars("DCLI_DRY_RUN"),
+			},
+			&cli.BoolFlag{
+				Name:  "output-schema",
+				Usage: "Print the JSON Schema of the command's JSON output instead of running it",
+			},
+			&cli.BoolFlag{
+				Name:    "quiet",
+				Aliases: []string{"q"},
+				Usage:   "Suppress status messages",
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			cfg.SetPath(c.String("config"))
+
+			// --columns is shorthand for -o custom-columns=ID:.id,NAME:.name,
+			// or picks the columns of csv and tsv output
+			if c.IsSet("columns") {
+				kind := dprint.FormatCustomColumns
+				if format := dprint.OutputFormat(c.String("output")); format.Kind() == dprint.FormatCSV || format.Kind() == dprint.FormatTSV {
+					kind = format.Kind()
+				}
+				format, err := dprint.ColumnsFormat(kind, c.StringSlice("columns"))
+				if err != nil {
+					return ctx, utils.ValidationErrorf("invalid --columns: %v", err)
+				}
+				if err := c.Set("output", format.String()); err != nil {
+					return ctx, err
+				}
+			}
+			if path := c.String("template-file"); path != "" {
+				text, err := os.ReadFile(path)
+				if err != nil {
+					return ctx, utils.ValidationErrorf("failed to read template file: %w", err)
+				}
+				if err := c.Set("output", string(dprint.FormatGoTemplate)+"="+string(text)); err != nil {
+					return ctx, err
+				}
+			}
+			// Unknown format names fall back to a table with a warning, but a
+			// template that does not parse would leave scripts reading a table
+			if strings.Contains(c.String("output"), "=") {
+				if _, err := dprint.ParseFormat(c.String("output")); err != nil {
+					return ctx, utils.ValidationErrorf("invalid --output: %v", err)
+				}
+			}
+
+			// Errors follow --output; the context's default format is only
+			// known once a command loads it
+			if format, err := dprint.ParseFormat(c.String("output")); err == nil {
+				utils.SetErrorFormat(format)
+			}
+
+			// Report an unknown --context up front instead of as missing flags
+			if c.IsSet("context") {
+				if _, err := utils.ActiveContext(c); err != nil {
+					return ctx, err
+				}
+			}
+			return ctx, nil
+		},
+		Commands: []*cli.Command{
+			ApplicationsCommands(),
+			GuildsCommand(),
+			ChannelsRootCommand(),
+			MessagesRootCommand(),
+			RolesRootCommand(),
+			MembersRootCommand(),
+			WebhooksRootCommand(),
+			UsersRootCommand(),
+			EmojiRootCommand(),
+			StickersRootCommand(),
+			EventsRootCommand(),
+			AutoModRootCommand(),
+			VoiceRootCommand(),
+			InvitesRootCommand(),
+			AuditLogRootCommand(),
+			ArchiveRootCommand(),
+			SnowflakeRootCommand(),
+			ConfigCommands(),
+			CompletionCommand(),
+		},
+	}
+
+	utils.ApplyContextDefaults(app)
+	ApplyOutputSchema(app)
+	return app
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/urfave/cli/v3"
@@ -50,18 +49,8 @@ func ListAppCommandsCommand() *cli.Command {
 			}
 
 			output := cliCtx.GetOutputManager()
-
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, cmd := range commands {
-					data = append(data, []string{cmd.ID, cmd.Name, strconv.Itoa(cmd.OptionsCount)})
-				}
-				header := []string{"ID", "Name", "Options"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(commands); err != nil {
-					return err
-				}
+			if err := output.Print(commands); err != nil {
+				return err
 			}
 
 			return nil
@@ -101,10 +90,9 @@ func RemoveAppCmdCommand() *cli.Command {
 				fmt.Println("Command deleted successfully!")
 			} else {
 				// Structured output for JSON/YAML
-				result := map[string]interface{}{
-					"success":    true,
-					"command_id": c.String("command"),
-					"guild_id":   c.String("guild"),
+				result := deleted{
+					ID:      c.String("command"),
+					GuildID: c.String("guild"),
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -151,17 +139,9 @@ func DescribeAppCmdCommand() *cli.Command {
 					return err
 				}
 
-				data := [][]string{}
-				for _, o := range cmd.Options {
-					var required string
-					if o.Required {
-						required = "YES"
-					}
-					data = append(data, []string{o.Name, required, o.Description})
+				if err := output.Print(cmd.Options); err != nil {
+					return err
 				}
-
-				header := []string{"Name", "Required", "Description"}
-				dprint.Table(header, data)
 			} else {
 				// Output structured data for JSON/YAML
 				if err := output.Print(cmd); err != nil {
@@ -247,15 +227,7 @@ func CreateAppCmdCommand() *cli.Command {
 					fmt.Printf("Scope: Global\n")
 				}
 			} else {
-				result := map[string]interface{}{
-					"success":     true,
-					"id":          createdCmd.ID,
-					"name":        createdCmd.Name,
-					"description": createdCmd.Description,
-					"type":        createdCmd.Type,
-					"guild_id":    guildID,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(createdCmd); err != nil {
 					return err
 				}
 			}
@@ -329,13 +301,7 @@ func EditAppCmdCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", updatedCmd.Name)
 				fmt.Printf("Description: %s\n", updatedCmd.Description)
 			} else {
-				result := map[string]interface{}{
-					"success":     true,
-					"id":          updatedCmd.ID,
-					"name":        updatedCmd.Name,
-					"description": updatedCmd.Description,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(updatedCmd); err != nil {
 					return err
 				}
 			}
@@ -383,23 +349,29 @@ func DeleteAllAppCmdCommand() *cli.Command {
 				return utils.DiscordErrorf("failed to get commands: %w", err)
 			}
 
+			output := cliCtx.GetOutputManager()
+
 			if len(commands) == 0 {
-				fmt.Println("No commands to delete.")
-				return nil
+				if output.GetFormat() == dprint.FormatTable {
+					fmt.Println("No commands to delete.")
+					return nil
+				}
+				result := bulkDeleted{IDs: []string{}, GuildID: guildID}
+				return output.Print(result)
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
+			if !c.Bool("force") && !c.Bool("dry-run") {
 				scope := "global"
 				if guildID != "" {
 					scope = fmt.Sprintf("guild %s", guildID)
 				}
-				fmt.Printf("You are about to delete %d %s command(s).\n", len(commands), scope)
-				fmt.Print("Are you sure? (yes/no): ")
+				fmt.Fprintf(os.Stderr, "You are about to delete %d %s command(s).\n", len(commands), scope)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				var response string
 				fmt.Scanln(&response)
 				if response != "yes" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -409,15 +381,18 @@ func DeleteAllAppCmdCommand() *cli.Command {
 				return utils.DiscordErrorf("failed to delete commands: %w", err)
 			}
 
-			output := cliCtx.GetOutputManager()
+			ids := make([]string, len(commands))
+			for i, cmd := range commands {
+				ids[i] = cmd.ID
+			}
 
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted %d command(s).\n", len(commands))
 			} else {
-				result := map[string]interface{}{
-					"success":  true,
-					"deleted":  len(commands),
-					"guild_id": guildID,
+				result := bulkDeleted{
+					Deleted: len(commands),
+					IDs:     ids,
+					GuildID: guildID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -480,13 +455,7 @@ func BulkOverwriteAppCmdCommand() *cli.Command {
 					fmt.Printf("  - %s (ID: %s)\n", cmd.Name, cmd.ID)
 				}
 			} else {
-				result := map[string]interface{}{
-					"success":  true,
-					"count":    len(createdCmds),
-					"guild_id": guildID,
-					"commands": createdCmds,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(createdCmds); err != nil {
 					return err
 				}
 			}
@@ -547,21 +516,9 @@ func GetCommandPermissionsCommand() *cli.Command {
 				if len(perms.Permissions) == 0 {
 					fmt.Println("  No permissions set")
 				} else {
-					data := [][]string{}
-					for _, p := range perms.Permissions {
-						typeStr := "Unknown"
-						switch p.Type {
-						case discordgo.ApplicationCommandPermissionTypeRole:
-							typeStr = "Role"
-						case discordgo.ApplicationCommandPermissionTypeUser:
-							typeStr = "User"
-						case discordgo.ApplicationCommandPermissionTypeChannel:
-							typeStr = "Channel"
-						}
-						data = append(data, []string{p.ID, typeStr, strconv.FormatBool(p.Permission)})
+					if err := output.Print(rowsOf(perms.Permissions, newCommandPermissionRow)); err != nil {
+						return err
 					}
-					header := []string{"ID", "Type", "Permission"}
-					dprint.Table(header, data)
 				}
 			} else {
 				if err := output.Print(perms); err != nil {
@@ -635,13 +592,7 @@ func SetCommandPermissionsCommand() *cli.Command {
 				fmt.Printf("Command ID: %s\n", perms.ID)
 				fmt.Printf("Total permissions: %d\n", len(perms.Permissions))
 			} else {
-				result := map[string]interface{}{
-					"success":     true,
-					"command_id":  perms.ID,
-					"guild_id":    perms.GuildID,
-					"permissions": perms.Permissions,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(perms); err != nil {
 					return err
 				}
 			}
@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/archive"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+)
+
+func ArchiveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "archive",
+		Usage: "Local message archive",
+		Commands: []*cli.Command{
+			ArchiveSyncCommand(),
+			ArchiveSearchCommand(),
+		},
+	}
+}
+
+// archiveDirFlag is the archive directory, shared by the archive commands
+func archiveDirFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "dir",
+		Usage: "Archive directory",
+		Value: "dccli-archive",
+	}
+}
+
+// ArchiveSyncCommand mirrors channels into the local archive
+func ArchiveSyncCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "sync",
+		Usage: "Fetch new messages, edits and deletions of channels into the archive",
+		Flags: []cli.Flag{
+			archiveDirFlag(),
+			&cli.StringSliceFlag{
+				Name:  "channels",
+				Usage: "Channels to sync (comma-separated IDs or names)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "guilds",
+				Usage: "Guilds whose text channels are all synced (comma-separated IDs or names)",
+			},
+			&cli.DurationFlag{
+				Name:  "recheck",
+				Usage: "Look for edits and deletions among the messages sent this long before the last sync (0 turns it off)",
+				Value: 24 * time.Hour,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			cliCtx, err := utils.NewCLIContext(c)
+			if err != nil {
+				return err
+			}
+			defer cliCtx.Close()
+
+			a, err := archive.Open(c.String("dir"))
+			if err != nil {
+				return utils.NewErrorWithCause("failed to open archive", utils.ExitError, err)
+			}
+			defer a.Close()
+
+			channels, err := archiveChannels(cliCtx, c, a)
+			if err != nil {
+				return err
+			}
+			if len(channels) == 0 {
+				return utils.ValidationError("nothing to sync: pass --channels or --guilds (later runs sync the archived channels by default)")
+			}
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			var results []*archive.SyncResult
+			failed := 0
+			for _, channel := range channels {
+				cliCtx.Statusf("Syncing #%s (%s)\n", channel.Name, channel.ID)
+				result, err := a.Sync(ctx, cliCtx.Client, channel, c.Duration("recheck"))
+				if errors.Is(err, context.Canceled) {
+					return utils.NewError("interrupted, the next sync carries on from the last checkpoint", utils.ExitError)
+				}
+				if err != nil {
+					result.Error = utils.DiscordErrorf("%w", err).Error()
+					failed++
+				}
+				results = append(results, result)
+			}
+
+			output := cliCtx.GetOutputManager()
+			if err := output.Print(results); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return utils.DiscordErrorf("%d of %d channels failed to sync", failed, len(channels))
+			}
+			return nil
+		},
+	}
+}
+
+// ArchiveSearchCommand searches the local archive
+func ArchiveSearchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Search the archived messages, newest first (works offline)",
+		ArgsUsage: "[query]",
+		Flags: []cli.Flag{
+			archiveDirFlag(),
+			&cli.StringFlag{
+				Name:  "author",
+				Usage: "Only messages by this user (ID, username or global name)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "channel",
+				Usage: "Only messages in these channels (ID or name, repeatable)",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only messages sent after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "Only messages sent before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+			},
+			&cli.BoolFlag{
+				Name:  "has-attachment",
+				Usage: "Only messages with attachments",
+			},
+			&cli.StringFlag{
+				Name:  "regex",
+				Usage: "Only messages whose text matches this regular expression",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Maximum number of results (0 for all)",
+				Value: 25,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			output := utils.NewOutputManager(c)
+
+			var err error
+			query := archive.SearchQuery{
+				Text:          strings.Join(c.Args().Slice(), " "),
+				Author:        c.String("author"),
+				HasAttachment: c.Bool("has-attachment"),
+				Limit:         int(c.Int("limit")),
+			}
+			if query.Since, err = utils.ParseTime(c.String("since")); err != nil {
+				return utils.ValidationErrorf("invalid --since: %w", err)
+			}
+			if query.Until, err = utils.ParseTime(c.String("until")); err != nil {
+				return utils.ValidationErrorf("invalid --until: %w", err)
+			}
+			if pattern := c.String("regex"); pattern != "" {
+				if query.Regex, err = regexp.Compile(pattern); err != nil {
+					return utils.ValidationErrorf("invalid --regex: %w", err)
+				}
+			}
+
+			if _, err := os.Stat(c.String("dir")); errors.Is(err, os.ErrNotExist) {
+				return utils.NotFoundErrorf("archive %s does not exist, run 'dccli archive sync' first", c.String("dir"))
+			}
+			a, err := archive.Open(c.String("dir"))
+			if err != nil {
+				return utils.NewErrorWithCause("failed to open archive", utils.ExitError, err)
+			}
+			defer a.Close()
+			if len(a.ChannelIDs()) == 0 {
+				return utils.NotFoundErrorf("archive %s is empty, run 'dccli archive sync' first", c.String("dir"))
+			}
+			for _, value := range c.StringSlice("channel") {
+				ids, err := a.FindChannels(value)
+				if err != nil {
+					return utils.NotFoundErrorf("%w", err)
+				}
+				query.ChannelIDs = append(query.ChannelIDs, ids...)
+			}
+
+			results, err := a.Search(query)
+			if err != nil {
+				return utils.NewErrorWithCause("failed to search archive", utils.ExitError, err)
+			}
+
+			if output.GetFormat() == dprint.FormatTable && len(results) == 0 {
+				fmt.Println("No messages found")
+				return nil
+			}
+			return output.Print(results)
+		},
+	}
+}
+
+// archiveChannels returns the channels named by --channels and the text
+// channels of --guilds, or every channel in the archive when neither is set
+func archiveChannels(cliCtx *utils.CLIContext, c *cli.Command, a *archive.Archive) ([]*discordgo.Channel, error) {
+	var channels []*discordgo.Channel
+	seen := make(map[string]bool)
+	add := func(channel *discordgo.Channel) {
+		if !seen[channel.ID] {
+			seen[channel.ID] = true
+			channels = append(channels, channel)
+		}
+	}
+
+	channelIDs, err := cliCtx.Resolver.Channels("", c.StringSlice("channels"))
+	if err != nil {
+		return nil, err
+	}
+	if len(channelIDs) == 0 && len(c.StringSlice("guilds")) == 0 {
+		channelIDs = a.ChannelIDs()
+	}
+	for _, id := range channelIDs {
+		channel, err := cliCtx.Client.GetChannel(id)
+		if err != nil {
+			return nil, utils.DiscordErrorf("failed to get channel %s: %w", id, err)
+		}
+		add(channel)
+	}
+
+	for _, value := range c.StringSlice("guilds") {
+		guildID, err := cliCtx.Resolver.Guild(value)
+		if err != nil {
+			return nil, err
+		}
+		guildChannels, err := cliCtx.Client.GetGuildChannels(guildID)
+		if err != nil {
+			return nil, utils.DiscordErrorf("failed to get channels: %w", err)
+		}
+		for _, channel := range guildChannels {
+			if channel.Type == discordgo.ChannelTypeGuildText || channel.Type == discordgo.ChannelTypeGuildNews {
+				add(channel)
+			}
+		}
+	}
+	return channels, nil
+}
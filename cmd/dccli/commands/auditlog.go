@@ -0,0 +1,400 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+func AuditLogCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "audit-log",
+		Usage: "Guild audit log",
+		Commands: []*cli.Command{
+			AuditLogListCommand(),
+			AuditLogExportCommand(),
+			AuditLogActionsCommand(),
+		},
+	}
+}
+
+// auditLogFilterFlags are shared by list and export
+func auditLogFilterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "guild",
+			Usage:    "Guild ID",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "user",
+			Usage: "Only entries made by this user ID",
+		},
+		&cli.StringFlag{
+			Name:  "action",
+			Usage: "Only entries of this action (e.g. MEMBER_BAN_ADD, role-update or 22)",
+		},
+		&cli.StringFlag{
+			Name:  "target",
+			Usage: "Only entries targeting this ID (user, role, channel, ...)",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "Only entries created after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Usage: "Only entries created before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+		},
+		&cli.StringFlag{
+			Name:  "before",
+			Usage: "Only entries older than this entry ID or time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+		},
+	}
+}
+
+// AuditLogListCommand lists audit log entries
+func AuditLogListCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List guild audit log entries, newest first",
+		Flags: append(auditLogFilterFlags(),
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Maximum number of entries (0 pages through the whole history)",
+				Value: 50,
+			},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			cliCtx, err := utils.NewCLIContext(c)
+			if err != nil {
+				return err
+			}
+			defer cliCtx.Close()
+
+			entries, err := fetchAuditLog(cliCtx, c, int(c.Int("limit")))
+			if err != nil {
+				return err
+			}
+
+			output := cliCtx.GetOutputManager()
+
+			if output.GetFormat() == dprint.FormatTable && len(entries) == 0 {
+				fmt.Println("No audit log entries found")
+				return nil
+			}
+			return output.Print(entries)
+
+		},
+	}
+}
+
+// AuditLogExportCommand exports audit log entries to JSON, NDJSON, CSV or TSV
+func AuditLogExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export the guild audit log as JSON, NDJSON, CSV or TSV",
+		Flags: append(auditLogFilterFlags(),
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Maximum number of entries (0 exports the whole history)",
+				Value: 0,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Export format (json|ndjson|csv|tsv)",
+				Value: "ndjson",
+			},
+			&cli.StringFlag{
+				Name:  "file",
+				Usage: "Write to this file instead of stdout",
+			},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			format := dprint.OutputFormat(c.String("format"))
+			switch format {
+			case dprint.FormatJSON, dprint.FormatNDJSON, dprint.FormatCSV, dprint.FormatTSV:
+			default:
+				return utils.ValidationErrorf("invalid export format: %s (valid: json, ndjson, csv, tsv)", format)
+			}
+
+			cliCtx, err := utils.NewCLIContext(c)
+			if err != nil {
+				return err
+			}
+			defer cliCtx.Close()
+
+			entries, err := fetchAuditLog(cliCtx, c, int(c.Int("limit")))
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if path := c.String("file"); path != "" {
+				file, err := os.Create(path)
+				if err != nil {
+					return utils.NewErrorWithCause("failed to create export file", utils.ExitError, err)
+				}
+				defer file.Close()
+				w = file
+			}
+
+			output := dprint.NewOutputManager(dprint.WithFormat(format), dprint.WithWriter(w))
+			if err := output.Print(entries); err != nil {
+				return err
+			}
+
+			if c.String("file") != "" {
+				cliCtx.Statusf("Exported %d audit log entries to %s\n", len(entries), c.String("file"))
+			}
+			return nil
+		},
+	}
+}
+
+// actionInfo is an audit log action name and its type number
+type actionInfo struct {
+	Name string `json:"name" yaml:"name" table:"Name"`
+	Type int    `json:"type" yaml:"type" table:"Type"`
+}
+
+// AuditLogActionsCommand lists the action names accepted by --action
+func AuditLogActionsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "actions",
+		Usage: "List audit log action names for --action",
+		Action: func(ctx context.Context, c *cli.Command) error {
+			var actions []actionInfo
+			for _, name := range discord.AuditLogActionNames() {
+				action, _ := discord.ParseAuditLogAction(name)
+				actions = append(actions, actionInfo{Name: name, Type: int(action)})
+			}
+
+			return utils.NewOutputManager(c).Print(actions)
+		},
+	}
+}
+
+// auditLogEntryOutput is an audit log entry with IDs resolved to names
+type auditLogEntryOutput struct {
+	ID         string                     `json:"id" yaml:"id"`
+	Time       time.Time                  `json:"time" yaml:"time" table:"Time"`
+	Action     string                     `json:"action" yaml:"action" table:"Action"`
+	ActionType int                        `json:"action_type" yaml:"action_type"`
+	UserID     string                     `json:"user_id,omitempty" yaml:"user_id,omitempty"`
+	User       string                     `json:"user,omitempty" yaml:"user,omitempty" table:"User"`
+	TargetID   string                     `json:"target_id,omitempty" yaml:"target_id,omitempty"`
+	Target     string                     `json:"target,omitempty" yaml:"target,omitempty" table:"Target"`
+	Reason     string                     `json:"reason,omitempty" yaml:"reason,omitempty" table:"Reason"`
+	Changes    []auditLogChangeOutput     `json:"changes,omitempty" yaml:"changes,omitempty" table:"Changes"`
+	Options    *discordgo.AuditLogOptions `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// auditLogChangeOutput is a single changed field of an audit log entry
+type auditLogChangeOutput struct {
+	Key string      `json:"key" yaml:"key"`
+	Old interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// String renders the change as "key: old -> new"
+func (c auditLogChangeOutput) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("%s: %s", c.Key, formatAuditLogValue(c.New))
+	case c.New == nil:
+		return fmt.Sprintf("%s: %s -> (removed)", c.Key, formatAuditLogValue(c.Old))
+	default:
+		return fmt.Sprintf("%s: %s -> %s", c.Key, formatAuditLogValue(c.Old), formatAuditLogValue(c.New))
+	}
+}
+
+// fetchAuditLog reads the filter flags, pages through the audit log and
+// resolves user, role and channel IDs to names
+func fetchAuditLog(cliCtx *utils.CLIContext, c *cli.Command, limit int) ([]auditLogEntryOutput, error) {
+	guildID := c.String("guild")
+	filter := discord.AuditLogFilter{
+		UserID: c.String("user"),
+		Limit:  limit,
+	}
+
+	if action := c.String("action"); action != "" {
+		actionType, err := discord.ParseAuditLogAction(action)
+		if err != nil {
+			return nil, utils.ValidationErrorf("%w (see 'dccli audit-log actions')", err)
+		}
+		filter.ActionType = actionType
+	}
+
+	var err error
+	if filter.Since, err = utils.ParseTime(c.String("since")); err != nil {
+		return nil, utils.ValidationErrorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = utils.ParseTime(c.String("until")); err != nil {
+		return nil, utils.ValidationErrorf("invalid --until: %w", err)
+	}
+	if filter.Before, err = utils.SnowflakeBefore(c.String("before")); err != nil {
+		return nil, utils.ValidationErrorf("invalid --before: %w", err)
+	}
+
+	// The API cannot filter by target, so a limit has to be applied afterwards
+	target := c.String("target")
+	if target != "" {
+		filter.Limit = 0
+	}
+
+	log, err := cliCtx.Client.GetGuildAuditLog(guildID, filter)
+	if err != nil {
+		return nil, utils.DiscordErrorf("failed to get audit log: %w", err)
+	}
+
+	names := newAuditLogNames(cliCtx, guildID, log)
+
+	entries := []auditLogEntryOutput{}
+	for _, entry := range log.AuditLogEntries {
+		if target != "" && entry.TargetID != target {
+			continue
+		}
+		if limit > 0 && len(entries) >= limit {
+			break
+		}
+		entries = append(entries, names.entry(entry))
+	}
+	return entries, nil
+}
+
+// auditLogNames resolves the IDs found in audit log entries
+type auditLogNames struct {
+	users    map[string]string
+	roles    map[string]string
+	channels map[string]string
+	webhooks map[string]string
+}
+
+func newAuditLogNames(cliCtx *utils.CLIContext, guildID string, log *discordgo.GuildAuditLog) *auditLogNames {
+	names := &auditLogNames{
+		users:    make(map[string]string),
+		roles:    make(map[string]string),
+		channels: make(map[string]string),
+		webhooks: make(map[string]string),
+	}
+	for _, user := range log.Users {
+		names.users[user.ID] = user.Username
+	}
+	for _, webhook := range log.Webhooks {
+		names.webhooks[webhook.ID] = webhook.Name
+	}
+
+	// Names are a convenience; entries are still useful without them
+	if roles, err := cliCtx.Client.GetGuildRoles(guildID); err == nil {
+		for _, role := range roles {
+			names.roles[role.ID] = role.Name
+		}
+	}
+	if channels, err := cliCtx.Client.GetGuildChannels(guildID); err == nil {
+		for _, channel := range channels {
+			names.channels[channel.ID] = "#" + channel.Name
+		}
+	}
+	return names
+}
+
+func (n *auditLogNames) entry(entry *discordgo.AuditLogEntry) auditLogEntryOutput {
+	out := auditLogEntryOutput{
+		ID:       entry.ID,
+		UserID:   entry.UserID,
+		User:     n.users[entry.UserID],
+		TargetID: entry.TargetID,
+		Reason:   entry.Reason,
+		Options:  entry.Options,
+	}
+	if created, err := snowflake.Time(entry.ID); err == nil {
+		out.Time = created
+	}
+	if entry.ActionType != nil {
+		out.Action = discord.AuditLogActionName(*entry.ActionType)
+		out.ActionType = int(*entry.ActionType)
+		out.Target = n.target(*entry.ActionType, entry.TargetID)
+	}
+	if out.Target == "" {
+		out.Target = entry.TargetID
+	}
+
+	for _, change := range entry.Changes {
+		key := ""
+		if change.Key != nil {
+			key = string(*change.Key)
+		}
+		out.Changes = append(out.Changes, auditLogChangeOutput{
+			Key: key,
+			Old: change.OldValue,
+			New: change.NewValue,
+		})
+	}
+	return out
+}
+
+// target names the entry target based on what kind of object the action touches
+func (n *auditLogNames) target(action discordgo.AuditLogAction, id string) string {
+	var name string
+	switch {
+	case action >= 10 && action < 20, action >= 110 && action < 120:
+		name = n.channels[id]
+	case action >= 20 && action < 30, action >= 72 && action < 80, action == 145:
+		name = n.users[id]
+	case action >= 30 && action < 40:
+		name = n.roles[id]
+	case action >= 50 && action < 60:
+		name = n.webhooks[id]
+	}
+	if name == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", name, id)
+}
+
+// formatAuditLogValue renders a change value for the table. Role lists from
+// $add/$remove changes are shown by name.
+func formatAuditLogValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}:
+		var items []string
+		for _, item := range value {
+			if obj, ok := item.(map[string]interface{}); ok {
+				if name, ok := obj["name"].(string); ok {
+					items = append(items, name)
+					continue
+				}
+			}
+			items = append(items, formatAuditLogValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	}
+}
@@ -56,27 +56,8 @@ func AutoModListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, rule := range rules {
-					enabled := "No"
-					if rule.Enabled != nil && *rule.Enabled {
-						enabled = "Yes"
-					}
-					data = append(data, []string{
-						rule.ID,
-						rule.Name,
-						autoModRuleTypeToString(rule.EventType),
-						enabled,
-						fmt.Sprintf("%d", len(rule.Actions)),
-					})
-				}
-				header := []string{"ID", "Name", "Type", "Enabled", "Actions"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(rules); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(rules, newAutoModRuleRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -255,8 +236,14 @@ func AutoModCreateCommand() *cli.Command {
 			}
 
 			enabled := true
-			exemptRoles := c.StringSlice("exempt-roles")
-			exemptChannels := c.StringSlice("exempt-channels")
+			exemptRoles, err := cliCtx.Resolver.Roles(guildID, c.StringSlice("exempt-roles"))
+			if err != nil {
+				return err
+			}
+			exemptChannels, err := cliCtx.Resolver.Channels(guildID, c.StringSlice("exempt-channels"))
+			if err != nil {
+				return err
+			}
 
 			params := &discordgo.AutoModerationRule{
 				Name:            name,
@@ -282,11 +269,7 @@ func AutoModCreateCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", rule.Name)
 				fmt.Printf("Type: %s\n", autoModTriggerTypeToString(rule.TriggerType))
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"rule":    rule,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(rule); err != nil {
 					return err
 				}
 			}
@@ -382,6 +365,10 @@ func AutoModEditCommand() *cli.Command {
 
 			// Update exempt roles if provided
 			if exemptRoles := c.StringSlice("exempt-roles"); len(exemptRoles) > 0 {
+				exemptRoles, err = cliCtx.Resolver.Roles(guildID, exemptRoles)
+				if err != nil {
+					return err
+				}
 				params.ExemptRoles = &exemptRoles
 			} else {
 				params.ExemptRoles = existingRule.ExemptRoles
@@ -389,6 +376,10 @@ func AutoModEditCommand() *cli.Command {
 
 			// Update exempt channels if provided
 			if exemptChannels := c.StringSlice("exempt-channels"); len(exemptChannels) > 0 {
+				exemptChannels, err = cliCtx.Resolver.Channels(guildID, exemptChannels)
+				if err != nil {
+					return err
+				}
 				params.ExemptChannels = &exemptChannels
 			} else {
 				params.ExemptChannels = existingRule.ExemptChannels
@@ -409,11 +400,7 @@ func AutoModEditCommand() *cli.Command {
 					fmt.Printf("Enabled: %v\n", *rule.Enabled)
 				}
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"rule":    rule,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(rule); err != nil {
 					return err
 				}
 			}
@@ -460,13 +447,13 @@ func AutoModDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete auto-mod rule: %s (ID: %s)\n", rule.Name, ruleID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete auto-mod rule: %s (ID: %s)\n", rule.Name, ruleID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -480,10 +467,10 @@ func AutoModDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted auto-mod rule: %s\n", rule.Name)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"rule_id": ruleID,
-					"name":    rule.Name,
+				result := deleted{
+					ID:      ruleID,
+					Name:    rule.Name,
+					GuildID: guildID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -2,12 +2,18 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/urfave/cli/v3"
 
 	"github.com/FlameInTheDark/dccli/pkg/cfg"
+	"github.com/FlameInTheDark/dccli/pkg/discord"
 	"github.com/FlameInTheDark/dccli/pkg/dprint"
 	"github.com/FlameInTheDark/dccli/pkg/utils"
 )
@@ -16,13 +22,14 @@ func BotCommand() *cli.Command {
 	return &cli.Command{
 		Name:        "bot",
 		Usage:       "Bot configuration management",
-		Description: "Manage bot configurations (add, remove, set, list, edit)",
+		Description: "Manage bot configurations (add, remove, set, list, edit, verify)",
 		Commands: []*cli.Command{
 			botAddCommand(),
 			botRemoveCommand(),
 			botSetCommand(),
 			botListCommand(),
 			botEditCommand(),
+			botVerifyCommand(),
 		},
 	}
 }
@@ -37,33 +44,35 @@ func botSetCommand() *cli.Command {
 				return utils.ValidationError("specify bot config name")
 			}
 
-			config, err := cfg.LoadConfig()
-			if err != nil {
-				return utils.ConfigErrorf("failed to load config: %w", err)
-			}
+			botName := c.Args().Get(0)
 
-			bot, err := config.GetBotByName(c.Args().Get(0))
+			err := updateConfig(func(config *cfg.Config) error {
+				if _, err := config.GetBotByName(botName); err != nil {
+					return utils.NotFoundErrorf("bot '%s' not found", botName)
+				}
+
+				config.CurrentBot = botName
+				// A context bound to another bot would keep overriding the choice
+				if current, err := config.GetContextByName(config.CurrentContext); err == nil && current.Bot != botName {
+					config.CurrentContext = ""
+				}
+				return nil
+			})
 			if err != nil {
-				return utils.NotFoundErrorf("bot '%s' not found", c.Args().Get(0))
+				return err
 			}
 
-			config.CurrentBot = bot.Name
-			cfg.SaveConfig(config)
-
 			outputFormat := c.String("output")
 			if outputFormat == "" {
 				outputFormat = "table"
 			}
 
 			if outputFormat == "table" {
-				fmt.Printf("Bot '%s' set as current\n", bot.Name)
+				fmt.Printf("Bot '%s' set as current\n", botName)
 			} else {
 				format, _ := dprint.ParseFormat(outputFormat)
 				output := dprint.NewOutputManager(dprint.WithFormat(format))
-				result := map[string]interface{}{
-					"success":     true,
-					"current_bot": bot.Name,
-				}
+				result := botResult{Bot: botName}
 				if err := output.Print(result); err != nil {
 					return err
 				}
@@ -79,36 +88,89 @@ func botAddCommand() *cli.Command {
 		Name:      "add",
 		Usage:     "Add bot to config",
 		ArgsUsage: "[bot name] [token]",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "api-base",
+				Usage: "REST API base URL for a Discord-compatible server",
+			},
+			&cli.StringFlag{
+				Name:  "gateway-url",
+				Usage: "Gateway websocket URL override",
+			},
+			&cli.StringFlag{
+				Name:  "default-reason",
+				Usage: "Audit log reason used when --reason is not given",
+			},
+			&cli.BoolFlag{
+				Name:  "encrypt",
+				Usage: "Store the token encrypted in the config vault (created on first use)",
+			},
+		}, tokenSourceFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
-			if c.NArg() < 2 {
+			source, err := tokenSource(c)
+			if err != nil {
+				return err
+			}
+			if source != nil {
+				if c.NArg() != 1 {
+					return utils.ValidationError("specify bot config name and either a token or a token source, not both")
+				}
+				if c.Bool("encrypt") {
+					return utils.ValidationError("--encrypt needs a literal token")
+				}
+			} else if c.NArg() < 2 {
 				return utils.ValidationError("specify bot config name and token")
 			}
 
-			config, err := cfg.LoadConfig()
-			if err != nil {
-				switch err {
-				case cfg.ErrNotConfigured:
-					config = &cfg.Config{
-						CurrentBot: c.Args().Get(0),
-					}
-				default:
+			// The passphrase prompt and the key derivation run before the
+			// config is locked
+			var sealed *utils.SealedToken
+			if c.Bool("encrypt") {
+				config, err := cfg.LoadConfig()
+				if errors.Is(err, cfg.ErrNotConfigured) {
+					config, err = &cfg.Config{}, nil
+				}
+				if err != nil {
 					return utils.ConfigErrorf("failed to load config: %w", err)
 				}
+				if sealed, err = utils.SealBotToken(c, config, c.Args().Get(1)); err != nil {
+					return err
+				}
 			}
 
-			for _, bot := range config.Bots {
-				if bot.Name == c.Args().Get(0) {
-					return utils.ValidationErrorf("bot '%s' already exists", c.Args().Get(0))
+			err = updateConfig(func(config *cfg.Config) error {
+				for _, bot := range config.Bots {
+					if bot.Name == c.Args().Get(0) {
+						return utils.ValidationErrorf("bot '%s' already exists", c.Args().Get(0))
+					}
 				}
-			}
 
-			config.Bots = append(config.Bots, cfg.BotConfig{
-				Name: c.Args().Get(0),
-				Bot: cfg.Bot{
+				bot := cfg.Bot{
 					Token: c.Args().Get(1),
-				},
+				}
+				if source != nil {
+					bot = *source
+				}
+				config.Bots = append(config.Bots, cfg.BotConfig{
+					Name:          c.Args().Get(0),
+					Bot:           bot,
+					APIBase:       c.String("api-base"),
+					GatewayURL:    c.String("gateway-url"),
+					DefaultReason: c.String("default-reason"),
+				})
+				if config.CurrentBot == "" {
+					config.CurrentBot = c.Args().Get(0)
+				}
+				if sealed != nil {
+					if err := sealed.Apply(config, &config.Bots[len(config.Bots)-1]); err != nil {
+						return err
+					}
+				}
+				return nil
 			})
-			cfg.SaveConfig(config)
+			if err != nil {
+				return err
+			}
 
 			outputFormat := c.String("output")
 			if outputFormat == "" {
@@ -120,10 +182,7 @@ func botAddCommand() *cli.Command {
 			} else {
 				format, _ := dprint.ParseFormat(outputFormat)
 				output := dprint.NewOutputManager(dprint.WithFormat(format))
-				result := map[string]interface{}{
-					"success":  true,
-					"bot_name": c.Args().Get(0),
-				}
+				result := botResult{Bot: c.Args().Get(0)}
 				if err := output.Print(result); err != nil {
 					return err
 				}
@@ -134,6 +193,15 @@ func botAddCommand() *cli.Command {
 	}
 }
 
+// BotInfo is a configured bot as listed by config bot list
+type BotInfo struct {
+	Name        string `json:"name" yaml:"name" table:"Name"`
+	Token       string `json:"token,omitempty" yaml:"token,omitempty" table:"Token"`
+	Encrypted   bool   `json:"encrypted" yaml:"encrypted" table:"Encrypted"`
+	TokenSource string `json:"token_source,omitempty" yaml:"token_source,omitempty" table:"Token Source"`
+	Current     bool   `json:"current" yaml:"current" table:"Current"`
+}
+
 func botListCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "list",
@@ -161,48 +229,55 @@ func botListCommand() *cli.Command {
 				format = dprint.FormatTable
 			}
 
-			if format == dprint.FormatTable {
-				data := [][]string{}
-				for _, bot := range config.Bots {
-					var token, selected string
-					if c.Bool("tokens") {
-						token = bot.Bot.Token
-					} else {
-						if len(bot.Bot.Token) > 5 {
-							token = bot.Bot.Token[:5] + "*****"
-						}
-					}
-					if bot.Name == config.CurrentBot {
-						selected = "*"
-					}
-					data = append(data, []string{selected, bot.Name, token})
-				}
+			// Labels are taken before --tokens fills in resolved tokens
+			sources := make([]string, len(config.Bots))
+			for i, bot := range config.Bots {
+				sources[i] = tokenSourceLabel(bot.Bot)
+			}
 
-				header := []string{"*", "Name", "Token"}
-				dprint.Table(header, data)
-			} else {
-				type BotInfo struct {
-					Name    string `json:"name" yaml:"name"`
-					Token   string `json:"token,omitempty" yaml:"token,omitempty"`
-					Current bool   `json:"current" yaml:"current"`
+			// Encrypted tokens are only shown when asked for, which needs the vault
+			if c.Bool("tokens") && slices.ContainsFunc(config.Bots, func(bot cfg.BotConfig) bool { return bot.Encrypted() }) {
+				key, err := utils.UnlockVault(c, config)
+				if err != nil {
+					return err
 				}
-
-				var bots []BotInfo
-				for _, bot := range config.Bots {
-					info := BotInfo{
-						Name:    bot.Name,
-						Current: bot.Name == config.CurrentBot,
+				for i := range config.Bots {
+					if err := config.Bots[i].DecryptToken(key); err != nil {
+						return utils.ConfigErrorf("%w", err)
+					}
+				}
+			}
+			if c.Bool("tokens") {
+				for i := range config.Bots {
+					bot := &config.Bots[i]
+					if bot.Bot.Token != "" || !bot.Bot.HasTokenSource() {
+						continue
 					}
-					if c.Bool("tokens") {
-						info.Token = bot.Bot.Token
+					token, err := bot.Bot.ResolveToken()
+					if err != nil {
+						return utils.ConfigErrorf("failed to get token of bot '%s': %w", bot.Name, err)
 					}
-					bots = append(bots, info)
+					bot.Bot.Token = token
 				}
+			}
 
-				output := dprint.NewOutputManager(dprint.WithFormat(format))
-				if err := output.Print(bots); err != nil {
-					return err
+			var bots []BotInfo
+			for i, bot := range config.Bots {
+				info := BotInfo{
+					Name:        bot.Name,
+					Encrypted:   bot.Encrypted(),
+					TokenSource: sources[i],
+					Current:     bot.Name == config.CurrentBot,
+				}
+				if c.Bool("tokens") {
+					info.Token = bot.Bot.Token
 				}
+				bots = append(bots, info)
+			}
+
+			output := dprint.NewOutputManager(dprint.WithFormat(format))
+			if err := output.Print(bots); err != nil {
+				return err
 			}
 
 			return nil
@@ -246,28 +321,49 @@ func botRemoveCommand() *cli.Command {
 			}
 
 			if !c.Bool("force") {
-				fmt.Printf("You are about to remove bot: %s\n", botName)
-				fmt.Print("Are you sure? (yes/no): ")
+				fmt.Fprintf(os.Stderr, "You are about to remove bot: %s\n", botName)
+				if bound := botContexts(config, botName); len(bound) > 0 {
+					fmt.Fprintf(os.Stderr, "These contexts use it and will be removed too: %s\n", strings.Join(bound, ", "))
+				}
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				var response string
 				_, err := fmt.Scanln(&response)
 				if err == nil && response != "yes" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
 
-			config.Bots = append(config.Bots[:botIndex], config.Bots[botIndex+1:]...)
+			// The config is read again under the lock, it may have changed
+			// while the prompt was open
+			var removedContexts []string
+			err = updateConfig(func(config *cfg.Config) error {
+				botIndex := slices.IndexFunc(config.Bots, func(bot cfg.BotConfig) bool { return bot.Name == botName })
+				if botIndex == -1 {
+					return utils.NotFoundErrorf("bot '%s' not found", botName)
+				}
+
+				config.Bots = append(config.Bots[:botIndex], config.Bots[botIndex+1:]...)
 
-			if config.CurrentBot == botName {
-				if len(config.Bots) > 0 {
-					config.CurrentBot = config.Bots[0].Name
-				} else {
-					config.CurrentBot = ""
+				if config.CurrentBot == botName {
+					if len(config.Bots) > 0 {
+						config.CurrentBot = config.Bots[0].Name
+					} else {
+						config.CurrentBot = ""
+					}
+				}
+				// A context without its bot cannot be used, so it goes too
+				removedContexts = botContexts(config, botName)
+				if slices.Contains(removedContexts, config.CurrentContext) {
+					config.CurrentContext = ""
 				}
+				config.Contexts = slices.DeleteFunc(config.Contexts, func(ctx cfg.Context) bool { return ctx.Bot == botName })
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 
-			cfg.SaveConfig(config)
-
 			outputFormat := c.String("output")
 			if outputFormat == "" {
 				outputFormat = "table"
@@ -275,13 +371,13 @@ func botRemoveCommand() *cli.Command {
 
 			if outputFormat == "table" {
 				fmt.Printf("Bot '%s' removed successfully\n", botName)
+				for _, name := range removedContexts {
+					fmt.Printf("Context '%s' removed\n", name)
+				}
 			} else {
 				format, _ := dprint.ParseFormat(outputFormat)
 				output := dprint.NewOutputManager(dprint.WithFormat(format))
-				result := map[string]interface{}{
-					"success":  true,
-					"bot_name": botName,
-				}
+				result := botResult{Bot: botName, RemovedContexts: removedContexts}
 				if err := output.Print(result); err != nil {
 					return err
 				}
@@ -292,12 +388,23 @@ func botRemoveCommand() *cli.Command {
 	}
 }
 
+// botContexts returns the names of the contexts that use the bot
+func botContexts(config *cfg.Config, botName string) []string {
+	var names []string
+	for _, ctx := range config.Contexts {
+		if ctx.Bot == botName {
+			names = append(names, ctx.Name)
+		}
+	}
+	return names
+}
+
 func botEditCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "edit",
 		Usage:     "Edit a bot's configuration",
 		ArgsUsage: "[bot-name]",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:  "token",
 				Usage: "New bot token",
@@ -306,7 +413,23 @@ func botEditCommand() *cli.Command {
 				Name:  "name",
 				Usage: "New bot name",
 			},
-		},
+			&cli.StringFlag{
+				Name:  "api-base",
+				Usage: "REST API base URL for a Discord-compatible server (empty string resets)",
+			},
+			&cli.StringFlag{
+				Name:  "gateway-url",
+				Usage: "Gateway websocket URL override (empty string resets)",
+			},
+			&cli.StringFlag{
+				Name:  "default-reason",
+				Usage: "Audit log reason used when --reason is not given (empty string resets)",
+			},
+			&cli.BoolFlag{
+				Name:  "encrypt",
+				Usage: "Move the token into the config vault",
+			},
+		}, tokenSourceFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			if c.NArg() < 1 {
 				return utils.ValidationError("bot name is required")
@@ -316,45 +439,107 @@ func botEditCommand() *cli.Command {
 			newToken := c.String("token")
 			newName := c.String("name")
 
-			if newToken == "" && newName == "" {
-				return utils.ValidationError("at least one of --token or --name must be specified")
+			source, err := tokenSource(c)
+			if err != nil {
+				return err
+			}
+			if source != nil && (newToken != "" || c.Bool("encrypt")) {
+				return utils.ValidationError("a token source cannot be combined with --token or --encrypt")
 			}
 
-			config, err := cfg.LoadConfig()
-			if err != nil {
-				return utils.ConfigErrorf("failed to load config: %w", err)
+			if newToken == "" && newName == "" && source == nil && !c.IsSet("api-base") && !c.IsSet("gateway-url") && !c.IsSet("default-reason") && !c.Bool("encrypt") {
+				return utils.ValidationError("at least one of --token, --token-command, --token-file, --token-env, --name, --api-base, --gateway-url, --default-reason or --encrypt must be specified")
 			}
 
-			botIndex := -1
-			for i, bot := range config.Bots {
-				if bot.Name == botName {
-					botIndex = i
-					break
+			// A token to encrypt is sealed before the config is locked, so
+			// the passphrase prompt and the key derivation do not hold it
+			var sealed *utils.SealedToken
+			if c.Bool("encrypt") || newToken != "" {
+				config, err := cfg.LoadConfig()
+				if err != nil {
+					return utils.ConfigErrorf("failed to load config: %w", err)
+				}
+				bot, err := config.GetBotByName(botName)
+				if err != nil {
+					return utils.NotFoundErrorf("bot '%s' not found", botName)
+				}
+				if c.Bool("encrypt") && bot.Encrypted() && newToken == "" {
+					return utils.ValidationErrorf("token of bot '%s' is already encrypted", botName)
+				}
+				// A new token for an encrypted bot stays encrypted
+				if c.Bool("encrypt") || bot.Encrypted() {
+					token := newToken
+					if token == "" {
+						token = bot.Bot.Token
+					}
+					if token == "" {
+						return utils.ValidationErrorf("bot '%s' has no literal token to encrypt, pass --token", botName)
+					}
+					if sealed, err = utils.SealBotToken(c, config, token); err != nil {
+						return err
+					}
 				}
 			}
 
-			if botIndex == -1 {
-				return utils.NotFoundErrorf("bot '%s' not found", botName)
-			}
+			err = updateConfig(func(config *cfg.Config) error {
+				botIndex := -1
+				for i, bot := range config.Bots {
+					if bot.Name == botName {
+						botIndex = i
+						break
+					}
+				}
 
-			if newToken != "" {
-				config.Bots[botIndex].Bot.Token = newToken
-			}
-			if newName != "" {
-				if newName != botName {
-					for _, bot := range config.Bots {
-						if bot.Name == newName {
-							return utils.ValidationErrorf("bot with name '%s' already exists", newName)
-						}
+				if botIndex == -1 {
+					return utils.NotFoundErrorf("bot '%s' not found", botName)
+				}
+
+				bot := &config.Bots[botIndex]
+				if sealed == nil && bot.Encrypted() && newToken != "" {
+					return utils.ConfigError("the bot was encrypted while the command ran, run it again")
+				}
+				if sealed != nil {
+					if err := sealed.Apply(config, bot); err != nil {
+						return err
 					}
+				} else if newToken != "" {
+					bot.Bot = cfg.Bot{Token: newToken}
+				} else if source != nil {
+					bot.Bot = *source
 				}
-				if config.CurrentBot == botName {
-					config.CurrentBot = newName
+				if c.IsSet("api-base") {
+					config.Bots[botIndex].APIBase = c.String("api-base")
+				}
+				if c.IsSet("gateway-url") {
+					config.Bots[botIndex].GatewayURL = c.String("gateway-url")
+				}
+				if c.IsSet("default-reason") {
+					config.Bots[botIndex].DefaultReason = c.String("default-reason")
+				}
+				if newName != "" {
+					if newName != botName {
+						for _, bot := range config.Bots {
+							if bot.Name == newName {
+								return utils.ValidationErrorf("bot with name '%s' already exists", newName)
+							}
+						}
+					}
+					if config.CurrentBot == botName {
+						config.CurrentBot = newName
+					}
+					for i := range config.Contexts {
+						if config.Contexts[i].Bot == botName {
+							config.Contexts[i].Bot = newName
+						}
+					}
+					config.Bots[botIndex].Name = newName
 				}
-				config.Bots[botIndex].Name = newName
-			}
 
-			cfg.SaveConfig(config)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
 
 			outputFormat := c.String("output")
 			if outputFormat == "" {
@@ -366,12 +551,9 @@ func botEditCommand() *cli.Command {
 			} else {
 				format, _ := dprint.ParseFormat(outputFormat)
 				output := dprint.NewOutputManager(dprint.WithFormat(format))
-				result := map[string]interface{}{
-					"success":  true,
-					"bot_name": botName,
-				}
-				if newName != "" {
-					result["new_name"] = newName
+				result := botResult{
+					Bot:     botName,
+					NewName: newName,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -383,6 +565,159 @@ func botEditCommand() *cli.Command {
 	}
 }
 
+// BotVerification is the result of checking a bot token against the API.
+// Valid is only set once the user, application and guild checks all passed.
+type BotVerification struct {
+	Name              string   `json:"name" yaml:"name" table:"Name"`
+	Valid             bool     `json:"valid" yaml:"valid" table:"Valid"`
+	TokenType         string   `json:"token_type,omitempty" yaml:"token_type,omitempty" table:"Type"`
+	UserID            string   `json:"user_id,omitempty" yaml:"user_id,omitempty" table:"User ID"`
+	Username          string   `json:"username,omitempty" yaml:"username,omitempty" table:"Username"`
+	ApplicationID     string   `json:"application_id,omitempty" yaml:"application_id,omitempty" table:"Application ID"`
+	PrivilegedIntents []string `json:"privileged_intents" yaml:"privileged_intents" table:"Privileged Intents"`
+	Guilds            int      `json:"guilds" yaml:"guilds" table:"Guilds"`
+	Error             string   `json:"error,omitempty" yaml:"error,omitempty" table:"Error"`
+}
+
+func botVerifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "verify",
+		Usage:       "Check bot tokens against the Discord API",
+		ArgsUsage:   "[bot name...]",
+		Description: "Calls the API with the token of each named bot (all bots by default, or only --token when given) and reports whether it is valid, the bot user, application ID, enabled privileged intents and guild count. Fails with a validation error if any token is invalid.",
+		Action: func(ctx context.Context, c *cli.Command) error {
+			output := utils.NewOutputManager(c)
+			results, err := verifyBots(c)
+			if err != nil {
+				return err
+			}
+
+			if err := output.Print(results); err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, result := range results {
+				if !result.Valid {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return utils.ValidationErrorf("%d of %d bot token(s) failed verification", failed, len(results))
+			}
+
+			return nil
+		},
+	}
+}
+
+// verifyBots checks the bots named in the arguments, all configured bots
+// when there are none, or only the --token override
+func verifyBots(c *cli.Command) ([]BotVerification, error) {
+	if token := c.String("token"); token != "" {
+		bot := &cfg.BotConfig{Name: "override", Bot: cfg.Bot{Token: token}}
+		return []BotVerification{verifyBot(c, bot)}, nil
+	}
+
+	config, err := cfg.LoadConfig()
+	if err != nil {
+		return nil, utils.ConfigErrorf("failed to load config: %w", err)
+	}
+
+	names := c.Args().Slice()
+	if len(names) == 0 {
+		for _, bot := range config.Bots {
+			names = append(names, bot.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, utils.ConfigError("no bots configured")
+	}
+
+	resolver := utils.NewTokenResolver(c, config)
+	var results []BotVerification
+	for _, name := range names {
+		bot, err := config.GetBotByName(name)
+		if err != nil {
+			return nil, utils.NotFoundErrorf("bot '%s' not found", name)
+		}
+		// A token that cannot be read is reported with the bot so the
+		// others are still checked
+		resolved, err := resolver.Resolve(bot)
+		if err != nil {
+			results = append(results, BotVerification{Name: name, PrivilegedIntents: []string{}, Error: err.Error()})
+			continue
+		}
+		results = append(results, verifyBot(c, resolved))
+	}
+	return results, nil
+}
+
+// verifyBot calls the API with the token of bot. Failures are recorded in
+// the result instead of being returned.
+func verifyBot(c *cli.Command, bot *cfg.BotConfig) BotVerification {
+	result := BotVerification{Name: bot.Name, PrivilegedIntents: []string{}}
+
+	opts := utils.ClientOptions(c, bot)
+	client, err := utils.NewClient(bot.Bot.Token, opts...)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer client.Close()
+
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		// Discord rejects user tokens sent as bot tokens, so ask again
+		// without the prefix to tell them apart from invalid ones
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusUnauthorized && isUserToken(bot.Bot.Token, opts) {
+			result.TokenType = "user"
+			result.Error = "token belongs to a user account, not a bot"
+			return result
+		}
+		result.Error = err.Error()
+		return result
+	}
+	result.UserID = user.ID
+	result.Username = user.Username
+	if !user.Bot {
+		result.TokenType = "user"
+		result.Error = "token belongs to a user account, not a bot"
+		return result
+	}
+	result.TokenType = "bot"
+
+	app, err := client.GetCurrentApplication()
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to get application: %v", err)
+		return result
+	}
+	result.ApplicationID = app.ID
+	result.PrivilegedIntents = discord.PrivilegedIntents(app.Flags)
+
+	for _, err := range discord.IterGuilds(client, "", "") {
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to list guilds: %v", err)
+			return result
+		}
+		result.Guilds++
+	}
+	result.Valid = true
+	return result
+}
+
+// isUserToken reports whether the API accepts token as a user token
+func isUserToken(token string, opts []discord.ClientOption) bool {
+	client, err := utils.NewClient(token, append(opts, discord.WithUserToken())...)
+	if err != nil {
+		return false
+	}
+	defer client.Close()
+	user, err := client.GetCurrentUser()
+	return err == nil && !user.Bot
+}
+
 func ConfigValidateCommand() *cli.Command {
 	return &cli.Command{
 		Name:        "validate",
@@ -394,28 +729,21 @@ func ConfigValidateCommand() *cli.Command {
 				return utils.ConfigErrorf("failed to validate config: %w", err)
 			}
 
-			outputFormat := c.String("output")
-			if outputFormat == "" {
-				outputFormat = "table"
-			}
-
-			if outputFormat == "table" {
+			output := utils.NewOutputManager(c)
+			if output.GetFormat() == dprint.FormatTable {
 				fmt.Print(cfg.FormatValidationResult(result))
 			} else {
-				format, _ := dprint.ParseFormat(outputFormat)
-				output := dprint.NewOutputManager(dprint.WithFormat(format))
-
 				errorStrings := make([]string, len(result.Errors))
 				for i, err := range result.Errors {
 					errorStrings[i] = err.Error()
 				}
 
-				resultMap := map[string]interface{}{
-					"valid":    result.Valid,
-					"errors":   errorStrings,
-					"warnings": result.Warnings,
+				validation := configValidation{
+					Valid:    result.Valid,
+					Errors:   errorStrings,
+					Warnings: result.Warnings,
 				}
-				if err := output.Print(resultMap); err != nil {
+				if err := output.Print(validation); err != nil {
 					return err
 				}
 			}
@@ -427,4 +755,71 @@ func ConfigValidateCommand() *cli.Command {
 			return nil
 		},
 	}
+}
+
+// tokenSourceFlags let a bot read its token at runtime instead of storing it
+func tokenSourceFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "token-command",
+			Usage: "Shell command that prints the token (e.g. \"pass show discord/prod\")",
+		},
+		&cli.StringFlag{
+			Name:  "token-file",
+			Usage: "File holding the token (e.g. a mounted secret)",
+		},
+		&cli.StringFlag{
+			Name:  "token-env",
+			Usage: "Environment variable holding the token",
+		},
+	}
+}
+
+// tokenSourceLabel describes where a bot's token is read from, "" when it
+// is stored in the config
+func tokenSourceLabel(bot cfg.Bot) string {
+	switch {
+	case bot.EncryptedToken != "" || bot.Token != "":
+		return ""
+	case bot.TokenEnv != "":
+		return "env:" + bot.TokenEnv
+	case bot.TokenFile != "":
+		return "file:" + bot.TokenFile
+	case bot.TokenCommand != "":
+		return "command:" + bot.TokenCommand
+	}
+	return ""
+}
+
+// tokenSource returns the token source given with tokenSourceFlags, or nil
+func tokenSource(c *cli.Command) (*cfg.Bot, error) {
+	bot := &cfg.Bot{
+		TokenCommand: c.String("token-command"),
+		TokenFile:    c.String("token-file"),
+		TokenEnv:     c.String("token-env"),
+	}
+	count := 0
+	for _, value := range []string{bot.TokenCommand, bot.TokenFile, bot.TokenEnv} {
+		if value != "" {
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return nil, nil
+	case 1:
+		return bot, nil
+	default:
+		return nil, utils.ValidationError("only one of --token-command, --token-file or --token-env can be given")
+	}
+}
+
+// updateConfig runs cfg.UpdateConfig. Errors returned by fn are passed
+// through, failures to read or write the config become config errors.
+func updateConfig(fn func(config *cfg.Config) error) error {
+	err := cfg.UpdateConfig(fn)
+	if err != nil && !utils.IsCLIError(err) {
+		return utils.ConfigErrorf("failed to update config: %w", err)
+	}
+	return err
 }
\ No newline at end of file
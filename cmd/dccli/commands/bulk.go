@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+)
+
+// bulkFlags are shared by commands that act on many targets
+func bulkFlags() []cli.Flag {
+	return append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "targets-file",
+			Usage: "Read more targets from a file, one per line (- for stdin)",
+		},
+	}, bulkRunFlags()...)
+}
+
+// bulkRunFlags control how runBulk works through the targets, for commands
+// that find their targets themselves
+func bulkRunFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "concurrency",
+			Usage: "Number of requests in flight at once",
+			Value: 4,
+		},
+		&cli.BoolFlag{
+			Name:  "resume",
+			Usage: "Skip the targets an interrupted or failed run with the same arguments already finished",
+		},
+		&cli.StringFlag{
+			Name:  "checkpoint",
+			Usage: "Checkpoint file recording finished targets (default: derived from the arguments, in the user cache directory)",
+		},
+	}
+}
+
+// bulkOp describes the operation of a bulk command
+type bulkOp struct {
+	// Name identifies the operation and its fixed arguments, e.g.
+	// "roles assign <guild> <role>". Together with the targets it picks the
+	// default checkpoint file.
+	Name string
+	// Label is shown in front of the progress bar, e.g. "Assigning role"
+	Label string
+	// BatchSize is the number of targets per call, 1 unless the endpoint
+	// takes several
+	BatchSize int
+	// Batchable picks the targets that can go in a batch; the others are
+	// passed alone. Nil means all of them.
+	Batchable func(target string) bool
+	// Do performs the operation on a batch of targets
+	Do func(ctx context.Context, batch []string) error
+	// Summary is the table output after the run, e.g. "Assigned role 123 to
+	// 4 of 5 users"
+	Summary func(succeeded, total int) string
+}
+
+// bulkTargets returns args followed by the lines of --targets-file, without
+// duplicates, blank lines or # comments
+func bulkTargets(c *cli.Command, args []string) ([]string, error) {
+	values := append([]string{}, args...)
+	if path := c.String("targets-file"); path != "" {
+		var r io.Reader = os.Stdin
+		if path != "-" {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, utils.ValidationErrorf("failed to read targets file: %w", err)
+			}
+			defer file.Close()
+			r = file
+		}
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			values = append(values, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, utils.ValidationErrorf("failed to read targets file: %w", err)
+		}
+	}
+
+	var targets []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || strings.HasPrefix(value, "#") || seen[value] {
+			continue
+		}
+		seen[value] = true
+		targets = append(targets, value)
+	}
+	return targets, nil
+}
+
+// runBulk runs op over the targets with a progress bar on stderr, prints the
+// report and fails when any target failed. Ctrl+C stops after the requests
+// in flight; the checkpoint lets --resume carry on from there.
+func runBulk(ctx context.Context, c *cli.Command, cliCtx *utils.CLIContext, targets []string, op bulkOp) error {
+	checkpoint, err := checkpointPath(c, op.Name, targets)
+	if err != nil {
+		return err
+	}
+
+	var progressOut io.Writer
+	if !cliCtx.Quiet && len(targets) > 1 && term.IsTerminal(int(os.Stderr.Fd())) {
+		progressOut = os.Stderr
+	}
+	progress := dprint.NewProgressBar(progressOut, op.Label)
+
+	executor := &discord.BulkExecutor{
+		Concurrency: int(c.Int("concurrency")),
+		BatchSize:   op.BatchSize,
+		Batchable:   op.Batchable,
+		Checkpoint:  checkpoint,
+		Resume:      c.Bool("resume"),
+		Progress:    progress.Update,
+	}
+	if c.Bool("dry-run") {
+		// Nothing is sent, so there is nothing to resume
+		executor.Checkpoint = ""
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	report, err := executor.Run(ctx, targets, op.Do)
+	progress.Finish()
+	if err != nil && !errors.Is(err, discord.ErrInterrupted) {
+		return err
+	}
+
+	output := cliCtx.GetOutputManager()
+	if output.GetFormat() == dprint.FormatTable {
+		for _, result := range report.Results {
+			if result.Status == discord.BulkFailed {
+				fmt.Fprintf(os.Stderr, "Failed %s: %s\n", result.Target, result.Error)
+			}
+		}
+		fmt.Println(op.Summary(report.Succeeded+report.Skipped, report.Total))
+	} else if err := output.Print(report); err != nil {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, discord.ErrInterrupted):
+		return utils.NewError(fmt.Sprintf("interrupted after %d of %d targets, run again with --resume to continue", report.Succeeded+report.Failed+report.Skipped, report.Total), utils.ExitError)
+	case report.Failed == 1 && report.Total == 1:
+		// Keep the exit code and details of a single failure
+		return report.Results[0].Err
+	case report.Failed > 0:
+		return utils.DiscordErrorf("%d of %d targets failed, run again with --resume to retry them", report.Failed, report.Total)
+	}
+	return nil
+}
+
+// checkpointPath returns --checkpoint, or a file in the user cache directory
+// named after the operation and its targets, so running the same command
+// again finds it
+func checkpointPath(c *cli.Command, name string, targets []string) (string, error) {
+	if path := c.String("checkpoint"); path != "" {
+		return path, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", utils.ConfigErrorf("failed to find cache directory for checkpoints: %w", err)
+	}
+	dir := filepath.Join(cacheDir, "dccli", "checkpoints")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", utils.ConfigErrorf("failed to create checkpoint directory: %w", err)
+	}
+	hash := sha256.Sum256([]byte(name + "\n" + strings.Join(targets, "\n")))
+	return filepath.Join(dir, hex.EncodeToString(hash[:8])+".checkpoint"), nil
+}
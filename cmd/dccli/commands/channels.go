@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/urfave/cli/v3"
 
+	"github.com/FlameInTheDark/dccli/pkg/discord"
 	"github.com/FlameInTheDark/dccli/pkg/dprint"
 	"github.com/FlameInTheDark/dccli/pkg/utils"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
 )
 
 func channelTypeString(channelType discordgo.ChannelType) string {
@@ -80,18 +83,8 @@ func ChannelsListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, ch := range channels {
-					chType := channelTypeString(ch.Type)
-					data = append(data, []string{ch.ID, ch.Name, chType})
-				}
-				header := []string{"ID", "Name", "Type"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(channels); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(channels, newChannelRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -114,7 +107,10 @@ func ChannelsGetCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("channel ID is required")
 			}
-			channelID := c.Args().First()
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			channel, err := cliCtx.Client.GetChannel(channelID)
 			if err != nil {
@@ -217,11 +213,7 @@ func ChannelsCreateCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", channel.Name)
 				fmt.Printf("Type: %s\n", channelTypeString(channel.Type))
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"channel": channel,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(channel); err != nil {
 					return err
 				}
 			}
@@ -277,7 +269,10 @@ func ChannelsEditCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("channel ID is required")
 			}
-			channelID := c.Args().First()
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			data := &discordgo.ChannelEdit{}
 
@@ -316,11 +311,7 @@ func ChannelsEditCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", channel.ID)
 				fmt.Printf("Name: %s\n", channel.Name)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"channel": channel,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(channel); err != nil {
 					return err
 				}
 			}
@@ -352,7 +343,10 @@ func ChannelsDeleteCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("channel ID is required")
 			}
-			channelID := c.Args().First()
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Get channel info for confirmation
 			channel, err := cliCtx.Client.GetChannel(channelID)
@@ -361,13 +355,13 @@ func ChannelsDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete channel: %s (ID: %s)\n", channel.Name, channelID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete channel: %s (ID: %s)\n", channel.Name, channelID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -381,10 +375,10 @@ func ChannelsDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted channel: %s\n", channel.Name)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"channel_id": channelID,
-					"name":       channel.Name,
+				result := deleted{
+					ID:      channelID,
+					Name:    channel.Name,
+					GuildID: channel.GuildID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -418,7 +412,7 @@ func ChannelMessagesListCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "list",
 		Usage: "List messages in the channel",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "channel",
 				Usage:    "Channel ID",
@@ -431,13 +425,13 @@ func ChannelMessagesListCommand() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "before",
-				Usage: "Get messages before this message ID",
+				Usage: "Get messages before this message ID or time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
 			},
 			&cli.StringFlag{
 				Name:  "after",
-				Usage: "Get messages after this message ID",
+				Usage: "Get messages after this message ID or time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
 			},
-		},
+		}, paginationFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -445,43 +439,34 @@ func ChannelMessagesListCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
+			before, after, err := snowflakeBounds(c)
+			if err != nil {
+				return err
+			}
+
 			channelID := c.String("channel")
+			output := cliCtx.GetOutputManager()
+
+			if paginateAll(c) {
+				messages := discord.IterChannelMessages(cliCtx.Client, channelID, before, after)
+				if err := streamPages(c, output, tableRows(messages, newMessageRow)); err != nil {
+					return utils.DiscordErrorf("failed to list messages: %w", err)
+				}
+				return nil
+			}
+
 			limit := c.Int("limit")
 			if limit > 100 {
 				limit = 100
 			}
 
-			messages, err := cliCtx.Client.GetChannelMessages(channelID, limit, c.String("before"), c.String("after"), "")
+			messages, err := cliCtx.Client.GetChannelMessages(channelID, limit, before, after, "")
 			if err != nil {
 				return utils.DiscordErrorf("failed to list messages: %w", err)
 			}
 
-			output := cliCtx.GetOutputManager()
-
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, m := range messages {
-					author := m.Author.Username
-					content := m.Content
-					if len(content) > 50 {
-						content = content[:47] + "..."
-					}
-					if content == "" {
-						content = "[embed/attachment]"
-					}
-					data = append(data, []string{
-						m.ID,
-						author,
-						content,
-						m.Timestamp.Format("2006-01-02 15:04"),
-					})
-				}
-				header := []string{"ID", "Author", "Content", "Time"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(messages); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(messages, newMessageRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -630,7 +615,7 @@ func ChannelMessagesSendCommand() *cli.Command {
 					// Try to reconstruct JSON if it was split by shell
 					if c.Args().Len() > 0 {
 						if _, ok := utils.ReconstructJSON(embedJSON, c.Args().Slice(), &msg.Embed); ok {
-							fmt.Println("Warning: JSON argument appeared to be split by shell. Successfully reconstructed.")
+							fmt.Fprintln(os.Stderr, "Warning: JSON argument appeared to be split by shell. Successfully reconstructed.")
 						} else {
 							return utils.ValidationErrorf("failed to parse embed JSON: %w (Hint: check shell quoting or use --embed-file)", err)
 						}
@@ -660,12 +645,7 @@ func ChannelMessagesSendCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", message.ID)
 				fmt.Printf("Channel: %s\n", channelID)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"message_id": message.ID,
-					"channel_id": channelID,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(message); err != nil {
 					return err
 				}
 			}
@@ -746,11 +726,7 @@ func ChannelMessagesEditCommand() *cli.Command {
 				fmt.Printf("Message edited successfully!\n")
 				fmt.Printf("ID: %s\n", message.ID)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"message_id": message.ID,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(message); err != nil {
 					return err
 				}
 			}
@@ -791,13 +767,13 @@ func ChannelMessagesDeleteCommand() *cli.Command {
 			channelID := c.String("channel")
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete message: %s\n", messageID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete message: %s\n", messageID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -811,9 +787,9 @@ func ChannelMessagesDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted message: %s\n", messageID)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"message_id": messageID,
+				result := deleted{
+					ID:        messageID,
+					ChannelID: channelID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -829,9 +805,9 @@ func ChannelMessagesDeleteCommand() *cli.Command {
 func ChannelMessagesBulkDeleteCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "bulk-delete",
-		Usage:     "Bulk delete messages",
+		Usage:     "Bulk delete messages, 100 per request; messages older than 14 days are deleted one by one",
 		ArgsUsage: "[message-ids...]",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "channel",
 				Usage:    "Channel ID",
@@ -841,7 +817,7 @@ func ChannelMessagesBulkDeleteCommand() *cli.Command {
 				Name:  "force",
 				Usage: "Skip confirmation prompt",
 			},
-		},
+		}, bulkFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -849,49 +825,61 @@ func ChannelMessagesBulkDeleteCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			if c.NArg() < 1 {
-				return utils.ValidationError("at least one message ID is required")
-			}
-
 			channelID := c.String("channel")
-			messageIDs := c.Args().Slice()
-
-			if len(messageIDs) > 100 {
-				return utils.ValidationError("cannot delete more than 100 messages at once")
+			messageIDs, err := bulkTargets(c, c.Args().Slice())
+			if err != nil {
+				return err
+			}
+			if len(messageIDs) == 0 {
+				return utils.ValidationError("at least one message ID is required")
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete %d messages\n", len(messageIDs))
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				if c.String("targets-file") == "-" {
+					return utils.ValidationError("--force is required when reading targets from stdin")
+				}
+				fmt.Fprintf(os.Stderr, "You are about to delete %d messages\n", len(messageIDs))
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
 
-			if err := cliCtx.Client.BulkDeleteMessages(channelID, messageIDs); err != nil {
-				return utils.DiscordErrorf("failed to bulk delete messages: %w", err)
-			}
-
-			output := cliCtx.GetOutputManager()
-
-			if output.GetFormat() == dprint.FormatTable {
-				fmt.Printf("Successfully deleted %d messages\n", len(messageIDs))
-			} else {
-				result := map[string]interface{}{
-					"success":     true,
-					"deleted":     len(messageIDs),
-					"message_ids": messageIDs,
-				}
-				if err := output.Print(result); err != nil {
-					return err
-				}
-			}
-
-			return nil
+			// Discord refuses to bulk delete messages older than 14 days, so
+			// those are deleted one by one. An hour of margin keeps a message
+			// from passing the limit while the run is going.
+			bulkCutoff := time.Now().Add(-discord.BulkDeleteMaxAge + time.Hour)
+			return runBulk(ctx, c, cliCtx, messageIDs, bulkOp{
+				Name:      "channels messages bulk-delete " + channelID,
+				Label:     "Deleting messages",
+				BatchSize: 100,
+				Batchable: func(id string) bool {
+					created, err := snowflake.Time(id)
+					return err == nil && created.After(bulkCutoff)
+				},
+				Do: func(ctx context.Context, batch []string) error {
+					if len(batch) == 1 {
+						if err := cliCtx.Client.DeleteChannelMessage(channelID, batch[0]); err != nil {
+							return utils.DiscordErrorf("failed to delete message: %w", err)
+						}
+						return nil
+					}
+					if err := cliCtx.Client.BulkDeleteMessages(channelID, batch); err != nil {
+						return utils.DiscordErrorf("failed to bulk delete messages: %w", err)
+					}
+					return nil
+				},
+				Summary: func(succeeded, total int) string {
+					if succeeded == total {
+						return fmt.Sprintf("Successfully deleted %d messages", total)
+					}
+					return fmt.Sprintf("Deleted %d of %d messages", succeeded, total)
+				},
+			})
 		},
 	}
 }
@@ -940,26 +928,8 @@ func ChannelWebhooksListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, wh := range webhooks {
-					avatar := "No"
-					if wh.Avatar != "" {
-						avatar = "Yes"
-					}
-					data = append(data, []string{
-						wh.ID,
-						wh.Name,
-						wh.Token,
-						avatar,
-					})
-				}
-				header := []string{"ID", "Name", "Token", "Avatar"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(webhooks); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(webhooks, newWebhookRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -1066,11 +1036,7 @@ func ChannelWebhooksCreateCommand() *cli.Command {
 				fmt.Printf("Token: %s\n", webhook.Token)
 				fmt.Printf("URL: https://discord.com/api/webhooks/%s/%s\n", webhook.ID, webhook.Token)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"webhook": webhook,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(webhook); err != nil {
 					return err
 				}
 			}
@@ -1135,11 +1101,7 @@ func ChannelWebhooksEditCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", webhook.ID)
 				fmt.Printf("Name: %s\n", webhook.Name)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"webhook": webhook,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(webhook); err != nil {
 					return err
 				}
 			}
@@ -1180,13 +1142,13 @@ func ChannelWebhooksDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete webhook: %s (ID: %s)\n", webhook.Name, webhookID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete webhook: %s (ID: %s)\n", webhook.Name, webhookID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -1200,10 +1162,11 @@ func ChannelWebhooksDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted webhook: %s\n", webhook.Name)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"webhook_id": webhookID,
-					"name":       webhook.Name,
+				result := deleted{
+					ID:        webhookID,
+					Name:      webhook.Name,
+					GuildID:   webhook.GuildID,
+					ChannelID: webhook.ChannelID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -0,0 +1,389 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/cfg"
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/discord/fake"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// testEnv is a fake Discord server seeded with one guild, channel and role
+type testEnv struct {
+	srv     *fake.Server
+	guild   *discordgo.Guild
+	channel *discordgo.Channel
+	role    *discordgo.Role
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("DCCLI_CONFIG", filepath.Join(dir, "config.yaml"))
+
+	srv := fake.NewServer()
+	t.Cleanup(srv.Close)
+	guild := srv.AddGuild("Test Guild")
+	return &testEnv{
+		srv:     srv,
+		guild:   guild,
+		channel: srv.AddChannel(guild.ID, "general", discordgo.ChannelTypeGuildText),
+		role:    srv.AddRole(guild.ID, "Moderator"),
+	}
+}
+
+// run executes dccli against the fake server and returns what it printed to
+// stdout. HandleError is not involved, so the returned error is the one main
+// would turn into an exit code.
+func (e *testEnv) run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		out <- buf.String()
+	}()
+
+	argv := append([]string{"dccli"}, e.srv.Args()...)
+	err = NewApp("test").Run(context.Background(), append(argv, args...))
+	w.Close()
+	return <-out, err
+}
+
+func (e *testEnv) requests(method string) []fake.Request {
+	var requests []fake.Request
+	for _, req := range e.srv.Requests() {
+		if req.Method == method {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+func exitCode(err error) utils.ExitCode {
+	var cliErr *utils.CLIError
+	if errors.As(err, &cliErr) {
+		return cliErr.Code
+	}
+	return utils.ExitError
+}
+
+func TestRolesList(t *testing.T) {
+	e := newTestEnv(t)
+	out, err := e.run(t, "-o", "json", "roles", "list", "--guild", e.guild.ID)
+	if err != nil {
+		t.Fatalf("roles list: %v", err)
+	}
+	var envelope struct {
+		Data []*discordgo.Role `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out)
+	}
+	found := false
+	for _, role := range envelope.Data {
+		found = found || role.ID == e.role.ID
+	}
+	if !found {
+		t.Errorf("role %s missing from output:\n%s", e.role.ID, out)
+	}
+}
+
+func TestRolesListTable(t *testing.T) {
+	e := newTestEnv(t)
+	out, err := e.run(t, "roles", "list", "--guild", e.guild.ID)
+	if err != nil {
+		t.Fatalf("roles list: %v", err)
+	}
+	for _, want := range []string{"HOISTED", e.role.ID, "Moderator"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRolesGet(t *testing.T) {
+	e := newTestEnv(t)
+	out, err := e.run(t, "roles", "get", "--guild", e.guild.ID, e.role.ID)
+	if err != nil {
+		t.Fatalf("roles get: %v", err)
+	}
+	if !strings.Contains(out, "Moderator") {
+		t.Errorf("output does not mention the role:\n%s", out)
+	}
+}
+
+func TestRolesCreate(t *testing.T) {
+	e := newTestEnv(t)
+	if _, err := e.run(t, "roles", "create", "--guild", e.guild.ID, "--name", "Helper"); err != nil {
+		t.Fatalf("roles create: %v", err)
+	}
+	posts := e.requests(http.MethodPost)
+	if len(posts) != 1 || !strings.HasSuffix(posts[0].Path, "/guilds/"+e.guild.ID+"/roles") {
+		t.Fatalf("want one POST to the guild roles, got %+v", posts)
+	}
+	guild, _ := e.srv.Guild(e.guild.ID)
+	found := false
+	for _, role := range guild.Roles {
+		found = found || role.Name == "Helper"
+	}
+	if !found {
+		t.Error("role was not created on the server")
+	}
+}
+
+func TestRolesDelete(t *testing.T) {
+	e := newTestEnv(t)
+	if _, err := e.run(t, "roles", "delete", "--guild", e.guild.ID, "--force", e.role.ID); err != nil {
+		t.Fatalf("roles delete: %v", err)
+	}
+	if deletes := e.requests(http.MethodDelete); len(deletes) != 1 {
+		t.Fatalf("want one DELETE, got %+v", deletes)
+	}
+	guild, _ := e.srv.Guild(e.guild.ID)
+	for _, role := range guild.Roles {
+		if role.ID == e.role.ID {
+			t.Error("role is still on the server")
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	e := newTestEnv(t)
+	_, err := e.run(t, "--dry-run", "roles", "delete", "--guild", e.guild.ID, e.role.ID)
+	if !errors.Is(err, discord.ErrDryRun) {
+		t.Fatalf("want ErrDryRun, got %v", err)
+	}
+	if deletes := e.requests(http.MethodDelete); len(deletes) != 0 {
+		t.Errorf("dry run sent %+v", deletes)
+	}
+	guild, _ := e.srv.Guild(e.guild.ID)
+	found := false
+	for _, role := range guild.Roles {
+		found = found || role.ID == e.role.ID
+	}
+	if !found {
+		t.Error("dry run deleted the role")
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	e := newTestEnv(t)
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown channel", []string{"channels", "get", "123456789012345678"}},
+		{"unknown role", []string{"roles", "delete", "--guild", e.guild.ID, "--force", "123456789012345678"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := e.run(t, tt.args...)
+			if err == nil {
+				t.Fatal("want an error")
+			}
+			if code := exitCode(err); code != utils.ExitNotFound {
+				t.Errorf("exit code = %d, want %d (%v)", code, utils.ExitNotFound, err)
+			}
+		})
+	}
+}
+
+func TestBotRemoveContexts(t *testing.T) {
+	e := newTestEnv(t)
+	path := os.Getenv("DCCLI_CONFIG")
+	err := cfg.Save(path, &cfg.Config{
+		CurrentBot: "main",
+		Bots: []cfg.BotConfig{
+			{Name: "main", Bot: cfg.Bot{Token: "a"}},
+			{Name: "other", Bot: cfg.Bot{Token: "b"}},
+		},
+		CurrentContext: "prod",
+		Contexts: []cfg.Context{
+			{Name: "prod", Bot: "main"},
+			{Name: "staging", Bot: "main"},
+			{Name: "test", Bot: "other"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e.run(t, "config", "bot", "remove", "--force", "main"); err != nil {
+		t.Fatalf("bot remove: %v", err)
+	}
+	config, err := cfg.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "" {
+		t.Errorf("current context = %q, want none", config.CurrentContext)
+	}
+	if len(config.Contexts) != 1 || config.Contexts[0].Name != "test" {
+		t.Errorf("contexts = %+v, want only test", config.Contexts)
+	}
+	if config.CurrentBot != "other" {
+		t.Errorf("current bot = %q, want other", config.CurrentBot)
+	}
+}
+
+func TestBotVerify(t *testing.T) {
+	e := newTestEnv(t)
+	out, err := e.run(t, "-o", "json", "config", "bot", "verify")
+	if err != nil {
+		t.Fatalf("bot verify: %v\n%s", err, out)
+	}
+	var envelope struct {
+		Data []BotVerification `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out)
+	}
+	if len(envelope.Data) != 1 || !envelope.Data[0].Valid || envelope.Data[0].Guilds != 1 {
+		t.Errorf("verification = %+v, want one valid bot in one guild", envelope.Data)
+	}
+}
+
+func TestBulkDeleteOldMessages(t *testing.T) {
+	e := newTestEnv(t)
+	author := e.srv.AddUser("author")
+	recent1 := e.srv.AddMessage(e.channel.ID, author, "one")
+	recent2 := e.srv.AddMessage(e.channel.ID, author, "two")
+	old := e.srv.AddMessageAt(e.channel.ID, author, "old", time.Now().Add(-30*24*time.Hour))
+
+	_, err := e.run(t, "channels", "messages", "bulk-delete", "--channel", e.channel.ID, "--force", recent1.ID, old.ID, recent2.ID)
+	if err != nil {
+		t.Fatalf("bulk-delete: %v", err)
+	}
+	if posts := e.requests(http.MethodPost); len(posts) != 1 || !strings.HasSuffix(posts[0].Path, "/messages/bulk-delete") {
+		t.Errorf("want one bulk delete, got %+v", posts)
+	}
+	if deletes := e.requests(http.MethodDelete); len(deletes) != 1 || !strings.HasSuffix(deletes[0].Path, "/messages/"+old.ID) {
+		t.Errorf("want the old message deleted alone, got %+v", deletes)
+	}
+	if messages := e.srv.Messages(e.channel.ID); len(messages) != 0 {
+		t.Errorf("%d messages left", len(messages))
+	}
+}
+
+func TestSnowflakeDecodeContextOutput(t *testing.T) {
+	e := newTestEnv(t)
+	err := cfg.Save(os.Getenv("DCCLI_CONFIG"), &cfg.Config{
+		CurrentContext: "scripts",
+		Contexts:       []cfg.Context{{Name: "scripts", Output: "json"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := e.run(t, "snowflake", "decode", e.guild.ID)
+	if err != nil {
+		t.Fatalf("snowflake decode: %v", err)
+	}
+	var envelope struct {
+		Data []snowflake.Snowflake `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("output is not the context's json: %v\n%s", err, out)
+	}
+	if len(envelope.Data) != 1 {
+		t.Errorf("decoded %+v, want one snowflake", envelope.Data)
+	}
+}
+
+func TestContextDefaultsWithConfigFlag(t *testing.T) {
+	e := newTestEnv(t)
+	path := filepath.Join(t.TempDir(), "other.yaml")
+	err := cfg.Save(path, &cfg.Config{
+		CurrentContext: "prod",
+		Contexts:       []cfg.Context{{Name: "prod", Guild: e.guild.ID}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := e.run(t, "--config", path, "roles", "list")
+	if err != nil {
+		t.Fatalf("roles list: %v", err)
+	}
+	if !strings.Contains(out, e.role.ID) {
+		t.Errorf("roles of the context's guild missing:\n%s", out)
+	}
+}
+
+func TestContextDefaultsOptionalFlags(t *testing.T) {
+	e := newTestEnv(t)
+	path := os.Getenv("DCCLI_CONFIG")
+	err := cfg.Save(path, &cfg.Config{
+		CurrentBot:     "main",
+		Bots:           []cfg.BotConfig{{Name: "main", Bot: cfg.Bot{Token: e.srv.Token()}}},
+		CurrentContext: "prod",
+		Contexts:       []cfg.Context{{Name: "prod", Bot: "main", Guild: e.guild.ID, Channel: e.channel.ID}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// --guild and --channel are both optional here; the guild wins
+	if _, err := e.run(t, "invites", "list"); err != nil {
+		t.Fatalf("invites list: %v", err)
+	}
+	if gets := e.requests(http.MethodGet); len(gets) == 0 || !strings.HasSuffix(gets[len(gets)-1].Path, "/guilds/"+e.guild.ID+"/invites") {
+		t.Errorf("requests = %+v, want the context guild's invites", gets)
+	}
+
+	// A context's own --guild is not filled in from the current one
+	if _, err := e.run(t, "config", "context", "set", "staging"); err != nil {
+		t.Fatalf("context set: %v", err)
+	}
+	config, err := cfg.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staging, err := config.GetContextByName("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staging.Guild != "" || staging.Channel != "" {
+		t.Errorf("staging = %+v, want no guild or channel", staging)
+	}
+}
+
+func TestGuildsListPagingNotOnStdout(t *testing.T) {
+	e := newTestEnv(t)
+	out, err := e.run(t, "guilds", "list", "--after", "1")
+	if err != nil {
+		t.Fatalf("guilds list: %v", err)
+	}
+	if strings.Contains(out, "Results after") {
+		t.Errorf("paging note printed to stdout:\n%s", out)
+	}
+	if !strings.Contains(out, e.guild.ID) {
+		t.Errorf("guild missing:\n%s", out)
+	}
+}
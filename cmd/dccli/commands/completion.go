@@ -132,10 +132,10 @@ _dccli_complete() {
     prev="${COMP_WORDS[COMP_CWORD-1]}"
 
     # Main commands
-    local commands="applications guilds channels messages roles members webhooks users emoji stickers events automod voice invites config completion version help"
+    local commands="applications guilds channels messages roles members webhooks users emoji stickers events automod voice invites audit-log archive snowflake config completion version help"
 
     # Global flags
-    local global_flags="--output -o --bot -b --token -t --help -h --version -v"
+    local global_flags="--output -o --columns --template-file --output-schema --bot -b --context --token -t --help -h --version -v"
 
     case "${COMP_CWORD}" in
         1)
@@ -154,7 +154,7 @@ _dccli_complete() {
                     COMPREPLY=( $(compgen -W "list describe create edit delete typing permissions invites webhooks messages pins" -- ${cur}) )
                     ;;
                 messages)
-                    COMPREPLY=( $(compgen -W "send edit delete get react unreact reactions pin unpin crosspost reply purge" -- ${cur}) )
+                    COMPREPLY=( $(compgen -W "send edit delete get react unreact reactions pin unpin crosspost reply purge export" -- ${cur}) )
                     ;;
                 roles)
                     COMPREPLY=( $(compgen -W "list create edit delete assign unassign" -- ${cur}) )
@@ -169,7 +169,7 @@ _dccli_complete() {
                     COMPREPLY=( $(compgen -W "me get avatar dm" -- ${cur}) )
                     ;;
                 emoji)
-                    COMPREPLY=( $(compgen -W "list create delete edit" -- ${cur}) )
+                    COMPREPLY=( $(compgen -W "list create upload delete edit" -- ${cur}) )
                     ;;
                 stickers)
                     COMPREPLY=( $(compgen -W "list get create delete edit" -- ${cur}) )
@@ -186,8 +186,17 @@ _dccli_complete() {
                 invites)
                     COMPREPLY=( $(compgen -W "list get create delete" -- ${cur}) )
                     ;;
+                audit-log)
+                    COMPREPLY=( $(compgen -W "list export actions" -- ${cur}) )
+                    ;;
+                archive)
+                    COMPREPLY=( $(compgen -W "sync search" -- ${cur}) )
+                    ;;
+                snowflake)
+                    COMPREPLY=( $(compgen -W "decode" -- ${cur}) )
+                    ;;
                 config)
-                    COMPREPLY=( $(compgen -W "bot validate" -- ${cur}) )
+                    COMPREPLY=( $(compgen -W "bot context vault validate" -- ${cur}) )
                     ;;
                 completion)
                     COMPREPLY=( $(compgen -W "bash zsh fish powershell" -- ${cur}) )
@@ -220,8 +229,12 @@ _dccli() {
     typeset -A opt_args
 
     _arguments -C \
-        '(-o --output)'{-o,--output}'[Output format (table|json|yaml)]:format:(table json yaml)' \
+        '(-o --output)'{-o,--output}'[Output format (table|json|yaml|ndjson|csv|tsv|custom-columns=...|jsonpath=...|go-template=...)]:format:(table json yaml ndjson csv tsv custom-columns= jsonpath= go-template=)' \
+        '--columns[Table columns to show]:columns:' \
+        '--template-file[Go template file]:file:_files' \
+        '--output-schema[Print the JSON Schema of the output]' \
         '(-b --bot)'{-b,--bot}'[Bot name to use]:bot:' \
+        '--context[Context to use]:context:' \
         '(-t --token)'{-t,--token}'[Bot token]:token:' \
         '(-h --help)'{-h,--help}'[Show help]' \
         '(-v --version)'{-v,--version}'[Show version]' \
@@ -271,6 +284,15 @@ _dccli() {
         invites)
             _dccli_invites
             ;;
+        audit-log)
+            _dccli_audit_log
+            ;;
+        archive)
+            _dccli_archive
+            ;;
+        snowflake)
+            _dccli_snowflake
+            ;;
         config)
             _dccli_config
             ;;
@@ -296,6 +318,9 @@ _dccli_commands() {
         "automod:AutoMod rules"
         "voice:Voice operations"
         "invites:Invite management"
+        "audit-log:Guild audit log"
+        "archive:Local message archive"
+        "snowflake:Snowflake ID tools"
         "config:Configuration"
         "completion:Shell completion"
         "version:Show version"
@@ -355,7 +380,8 @@ _dccli_messages() {
         "unpin:Unpin message"
         "crosspost:Crosspost message"
         "reply:Reply to message"
-        "purge:Bulk delete messages"
+        "purge:Delete messages matching filters"
+        "export:Export channel history"
     )
     _describe -t commands 'messages subcommands' subcmds
 }
@@ -416,6 +442,7 @@ _dccli_emoji() {
     local subcmds=(
         "list:List emoji"
         "create:Create emoji"
+        "upload:Upload emoji from image files"
         "delete:Delete emoji"
         "edit:Edit emoji"
     )
@@ -475,9 +502,35 @@ _dccli_invites() {
     _describe -t commands 'invites subcommands' subcmds
 }
 
+_dccli_audit_log() {
+    local subcmds=(
+        "list:List audit log entries"
+        "export:Export audit log entries"
+        "actions:List audit log action types"
+    )
+    _describe -t commands 'audit-log subcommands' subcmds
+}
+
+_dccli_archive() {
+    local subcmds=(
+        "sync:Sync channels into the archive"
+        "search:Search archived messages"
+    )
+    _describe -t commands 'archive subcommands' subcmds
+}
+
+_dccli_snowflake() {
+    local subcmds=(
+        "decode:Show when an ID was created"
+    )
+    _describe -t commands 'snowflake subcommands' subcmds
+}
+
 _dccli_config() {
     local subcmds=(
         "bot:Bot management"
+        "context:Context management"
+        "vault:Token vault"
         "validate:Validate config"
     )
     _describe -t commands 'config subcommands' subcmds
@@ -490,10 +543,27 @@ _dccli_config_bot() {
         "set:Set current bot"
         "list:List bots"
         "edit:Edit bot"
+        "verify:Verify bot tokens"
     )
     _describe -t commands 'bot subcommands' subcmds
 }
 
+_dccli_config_context() {
+    local subcmds=(
+        "use:Set current context"
+        "list:List contexts"
+        "set:Create or update context"
+    )
+    _describe -t commands 'context subcommands' subcmds
+}
+
+_dccli_config_vault() {
+    local subcmds=(
+        "rekey:Change vault passphrase"
+    )
+    _describe -t commands 'vault subcommands' subcmds
+}
+
 _dccli_completion() {
     local subcmds=(
         "bash:Generate bash completion"
@@ -516,8 +586,12 @@ func generateFishCompletion() string {
 complete -c dccli -f
 
 # Global flags
-complete -c dccli -s o -l output -d "Output format (table|json|yaml)" -a "table json yaml"
+complete -c dccli -s o -l output -d "Output format (table|json|yaml|ndjson|csv|tsv|custom-columns=...|jsonpath=...|go-template=...)" -a "table json yaml ndjson csv tsv custom-columns= jsonpath= go-template="
+complete -c dccli -l columns -d "Table columns to show"
+complete -c dccli -l template-file -r -F -d "Go template file"
+complete -c dccli -l output-schema -d "Print the JSON Schema of the output"
 complete -c dccli -s b -l bot -d "Bot name to use"
+complete -c dccli -l context -d "Context to use"
 complete -c dccli -s t -l token -d "Bot token"
 complete -c dccli -s h -l help -d "Show help"
 complete -c dccli -s v -l version -d "Show version"
@@ -537,6 +611,9 @@ complete -c dccli -n "__fish_use_subcommand" -a "events" -d "Scheduled events"
 complete -c dccli -n "__fish_use_subcommand" -a "automod" -d "AutoMod rules"
 complete -c dccli -n "__fish_use_subcommand" -a "voice" -d "Voice operations"
 complete -c dccli -n "__fish_use_subcommand" -a "invites" -d "Invite management"
+complete -c dccli -n "__fish_use_subcommand" -a "audit-log" -d "Guild audit log"
+complete -c dccli -n "__fish_use_subcommand" -a "archive" -d "Local message archive"
+complete -c dccli -n "__fish_use_subcommand" -a "snowflake" -d "Snowflake ID tools"
 complete -c dccli -n "__fish_use_subcommand" -a "config" -d "Configuration"
 complete -c dccli -n "__fish_use_subcommand" -a "completion" -d "Shell completion"
 complete -c dccli -n "__fish_use_subcommand" -a "version" -d "Show version"
@@ -580,7 +657,8 @@ complete -c dccli -n "__fish_seen_subcommand_from messages" -a "pin" -d "Pin mes
 complete -c dccli -n "__fish_seen_subcommand_from messages" -a "unpin" -d "Unpin message"
 complete -c dccli -n "__fish_seen_subcommand_from messages" -a "crosspost" -d "Crosspost message"
 complete -c dccli -n "__fish_seen_subcommand_from messages" -a "reply" -d "Reply to message"
-complete -c dccli -n "__fish_seen_subcommand_from messages" -a "purge" -d "Bulk delete messages"
+complete -c dccli -n "__fish_seen_subcommand_from messages" -a "purge" -d "Delete messages matching filters"
+complete -c dccli -n "__fish_seen_subcommand_from messages" -a "export" -d "Export channel history"
 
 # roles subcommands
 complete -c dccli -n "__fish_seen_subcommand_from roles" -a "list" -d "List roles"
@@ -621,6 +699,7 @@ complete -c dccli -n "__fish_seen_subcommand_from users" -a "dm" -d "Send DM"
 # emoji subcommands
 complete -c dccli -n "__fish_seen_subcommand_from emoji" -a "list" -d "List emoji"
 complete -c dccli -n "__fish_seen_subcommand_from emoji" -a "create" -d "Create emoji"
+complete -c dccli -n "__fish_seen_subcommand_from emoji" -a "upload" -d "Upload emoji from image files"
 complete -c dccli -n "__fish_seen_subcommand_from emoji" -a "delete" -d "Delete emoji"
 complete -c dccli -n "__fish_seen_subcommand_from emoji" -a "edit" -d "Edit emoji"
 
@@ -657,8 +736,22 @@ complete -c dccli -n "__fish_seen_subcommand_from invites" -a "get" -d "Get invi
 complete -c dccli -n "__fish_seen_subcommand_from invites" -a "create" -d "Create invite"
 complete -c dccli -n "__fish_seen_subcommand_from invites" -a "delete" -d "Delete invite"
 
+# audit-log subcommands
+complete -c dccli -n "__fish_seen_subcommand_from audit-log" -a "list" -d "List audit log entries"
+complete -c dccli -n "__fish_seen_subcommand_from audit-log" -a "export" -d "Export audit log entries"
+complete -c dccli -n "__fish_seen_subcommand_from audit-log" -a "actions" -d "List audit log action types"
+
+# archive subcommands
+complete -c dccli -n "__fish_seen_subcommand_from archive" -a "sync" -d "Sync channels into the archive"
+complete -c dccli -n "__fish_seen_subcommand_from archive" -a "search" -d "Search archived messages"
+
+# snowflake subcommands
+complete -c dccli -n "__fish_seen_subcommand_from snowflake" -a "decode" -d "Show when an ID was created"
+
 # config subcommands
 complete -c dccli -n "__fish_seen_subcommand_from config" -a "bot" -d "Bot management"
+complete -c dccli -n "__fish_seen_subcommand_from config" -a "context" -d "Context management"
+complete -c dccli -n "__fish_seen_subcommand_from config" -a "vault" -d "Token vault"
 complete -c dccli -n "__fish_seen_subcommand_from config" -a "validate" -d "Validate config"
 
 # config bot subcommands
@@ -667,6 +760,15 @@ complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcom
 complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from bot" -a "set" -d "Set current bot"
 complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from bot" -a "list" -d "List bots"
 complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from bot" -a "edit" -d "Edit bot"
+complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from bot" -a "verify" -d "Verify bot tokens"
+
+# config context subcommands
+complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from context" -a "use" -d "Set current context"
+complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from context" -a "list" -d "List contexts"
+complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from context" -a "set" -d "Create or update context"
+
+# config vault subcommands
+complete -c dccli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from vault" -a "rekey" -d "Change vault passphrase"
 
 # completion subcommands
 complete -c dccli -n "__fish_seen_subcommand_from completion" -a "bash" -d "Generate bash completion"
@@ -716,6 +818,9 @@ Register-ArgumentCompleter -Native -CommandName 'dccli' -ScriptBlock {
             [CompletionResult]::new('automod', 'automod', [CompletionResultType]::ParameterValue, 'AutoMod rules')
             [CompletionResult]::new('voice', 'voice', [CompletionResultType]::ParameterValue, 'Voice operations')
             [CompletionResult]::new('invites', 'invites', [CompletionResultType]::ParameterValue, 'Invite management')
+            [CompletionResult]::new('audit-log', 'audit-log', [CompletionResultType]::ParameterValue, 'Guild audit log')
+            [CompletionResult]::new('archive', 'archive', [CompletionResultType]::ParameterValue, 'Local message archive')
+            [CompletionResult]::new('snowflake', 'snowflake', [CompletionResultType]::ParameterValue, 'Snowflake ID tools')
             [CompletionResult]::new('config', 'config', [CompletionResultType]::ParameterValue, 'Configuration')
             [CompletionResult]::new('completion', 'completion', [CompletionResultType]::ParameterValue, 'Shell completion')
             [CompletionResult]::new('version', 'version', [CompletionResultType]::ParameterValue, 'Show version')
@@ -763,7 +868,8 @@ Register-ArgumentCompleter -Native -CommandName 'dccli' -ScriptBlock {
             [CompletionResult]::new('unpin', 'unpin', [CompletionResultType]::ParameterValue, 'Unpin message')
             [CompletionResult]::new('crosspost', 'crosspost', [CompletionResultType]::ParameterValue, 'Crosspost message')
             [CompletionResult]::new('reply', 'reply', [CompletionResultType]::ParameterValue, 'Reply to message')
-            [CompletionResult]::new('purge', 'purge', [CompletionResultType]::ParameterValue, 'Bulk delete messages')
+            [CompletionResult]::new('purge', 'purge', [CompletionResultType]::ParameterValue, 'Delete messages matching filters')
+            [CompletionResult]::new('export', 'export', [CompletionResultType]::ParameterValue, 'Export channel history')
             break
         }
         'roles' {
@@ -809,6 +915,7 @@ Register-ArgumentCompleter -Native -CommandName 'dccli' -ScriptBlock {
         'emoji' {
             [CompletionResult]::new('list', 'list', [CompletionResultType]::ParameterValue, 'List emoji')
             [CompletionResult]::new('create', 'create', [CompletionResultType]::ParameterValue, 'Create emoji')
+            [CompletionResult]::new('upload', 'upload', [CompletionResultType]::ParameterValue, 'Upload emoji from image files')
             [CompletionResult]::new('delete', 'delete', [CompletionResultType]::ParameterValue, 'Delete emoji')
             [CompletionResult]::new('edit', 'edit', [CompletionResultType]::ParameterValue, 'Edit emoji')
             break
@@ -851,8 +958,25 @@ Register-ArgumentCompleter -Native -CommandName 'dccli' -ScriptBlock {
             [CompletionResult]::new('delete', 'delete', [CompletionResultType]::ParameterValue, 'Delete invite')
             break
         }
+        'audit-log' {
+            [CompletionResult]::new('list', 'list', [CompletionResultType]::ParameterValue, 'List audit log entries')
+            [CompletionResult]::new('export', 'export', [CompletionResultType]::ParameterValue, 'Export audit log entries')
+            [CompletionResult]::new('actions', 'actions', [CompletionResultType]::ParameterValue, 'List audit log action types')
+            break
+        }
+        'archive' {
+            [CompletionResult]::new('sync', 'sync', [CompletionResultType]::ParameterValue, 'Sync channels into the archive')
+            [CompletionResult]::new('search', 'search', [CompletionResultType]::ParameterValue, 'Search archived messages')
+            break
+        }
+        'snowflake' {
+            [CompletionResult]::new('decode', 'decode', [CompletionResultType]::ParameterValue, 'Show when an ID was created')
+            break
+        }
         'config' {
             [CompletionResult]::new('bot', 'bot', [CompletionResultType]::ParameterValue, 'Bot management')
+            [CompletionResult]::new('context', 'context', [CompletionResultType]::ParameterValue, 'Context management')
+            [CompletionResult]::new('vault', 'vault', [CompletionResultType]::ParameterValue, 'Token vault')
             [CompletionResult]::new('validate', 'validate', [CompletionResultType]::ParameterValue, 'Validate config')
             break
         }
@@ -862,6 +986,17 @@ Register-ArgumentCompleter -Native -CommandName 'dccli' -ScriptBlock {
             [CompletionResult]::new('set', 'set', [CompletionResultType]::ParameterValue, 'Set current bot')
             [CompletionResult]::new('list', 'list', [CompletionResultType]::ParameterValue, 'List bots')
             [CompletionResult]::new('edit', 'edit', [CompletionResultType]::ParameterValue, 'Edit bot')
+            [CompletionResult]::new('verify', 'verify', [CompletionResultType]::ParameterValue, 'Verify bot tokens')
+            break
+        }
+        'config;context' {
+            [CompletionResult]::new('use', 'use', [CompletionResultType]::ParameterValue, 'Set current context')
+            [CompletionResult]::new('list', 'list', [CompletionResultType]::ParameterValue, 'List contexts')
+            [CompletionResult]::new('set', 'set', [CompletionResultType]::ParameterValue, 'Create or update context')
+            break
+        }
+        'config;vault' {
+            [CompletionResult]::new('rekey', 'rekey', [CompletionResultType]::ParameterValue, 'Change vault passphrase')
             break
         }
         'completion' {
@@ -878,8 +1013,12 @@ Register-ArgumentCompleter -Native -CommandName 'dccli' -ScriptBlock {
         $completions += @(
             [CompletionResult]::new('-o', '-o', [CompletionResultType]::ParameterName, 'Output format')
             [CompletionResult]::new('--output', '--output', [CompletionResultType]::ParameterName, 'Output format')
+            [CompletionResult]::new('--columns', '--columns', [CompletionResultType]::ParameterName, 'Table columns')
+            [CompletionResult]::new('--template-file', '--template-file', [CompletionResultType]::ParameterName, 'Go template file')
+            [CompletionResult]::new('--output-schema', '--output-schema', [CompletionResultType]::ParameterName, 'Output JSON Schema')
             [CompletionResult]::new('-b', '-b', [CompletionResultType]::ParameterName, 'Bot name')
             [CompletionResult]::new('--bot', '--bot', [CompletionResultType]::ParameterName, 'Bot name')
+            [CompletionResult]::new('--context', '--context', [CompletionResultType]::ParameterName, 'Context name')
             [CompletionResult]::new('-t', '-t', [CompletionResultType]::ParameterName, 'Bot token')
             [CompletionResult]::new('--token', '--token', [CompletionResultType]::ParameterName, 'Bot token')
             [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'Show help')
@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/cfg"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+)
+
+func ContextCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "context",
+		Usage:       "Named context management",
+		Description: "Manage contexts that bind a bot to a default guild, channel and output format",
+		Commands: []*cli.Command{
+			contextUseCommand(),
+			contextListCommand(),
+			contextSetCommand(),
+		},
+	}
+}
+
+func contextUseCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "use",
+		Usage:     "Make a context the current one",
+		ArgsUsage: "[context name]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "none",
+				Usage: "Stop using any context",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.NArg() < 1 && !c.Bool("none") {
+				return utils.ValidationError("specify context name or --none")
+			}
+
+			name := ""
+			err := updateConfig(func(config *cfg.Config) error {
+				if !c.Bool("none") {
+					entry, err := config.GetContextByName(c.Args().First())
+					if err != nil {
+						return utils.NotFoundErrorf("context '%s' not found", c.Args().First())
+					}
+					name = entry.Name
+				}
+
+				config.CurrentContext = name
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			output := utils.NewOutputManager(c)
+			if output.GetFormat() == dprint.FormatTable {
+				if name == "" {
+					fmt.Println("No context in use")
+				} else {
+					fmt.Printf("Context '%s' set as current\n", name)
+				}
+			} else {
+				result := contextResult{Context: name}
+				if err := output.Print(result); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// ContextInfo is a context as listed by config context list
+type ContextInfo struct {
+	Name    string `json:"name" yaml:"name" table:"Name"`
+	Bot     string `json:"bot" yaml:"bot" table:"Bot"`
+	Guild   string `json:"guild,omitempty" yaml:"guild,omitempty" table:"Guild"`
+	Channel string `json:"channel,omitempty" yaml:"channel,omitempty" table:"Channel"`
+	Output  string `json:"output,omitempty" yaml:"output,omitempty" table:"Output"`
+	Current bool   `json:"current" yaml:"current" table:"Current"`
+}
+
+func contextListCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "Returns a list of configured contexts",
+		Action: func(ctx context.Context, c *cli.Command) error {
+			config, err := cfg.LoadConfig()
+			if err != nil {
+				return utils.ConfigErrorf("failed to load config: %w", err)
+			}
+
+			output := utils.NewOutputManager(c)
+
+			contexts := []ContextInfo{}
+			for _, entry := range config.Contexts {
+				contexts = append(contexts, ContextInfo{
+					Name:    entry.Name,
+					Bot:     entry.Bot,
+					Guild:   entry.Guild,
+					Channel: entry.Channel,
+					Output:  entry.Output,
+					Current: entry.Name == config.CurrentContext,
+				})
+			}
+
+			if err := output.Print(contexts); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+}
+
+func contextSetCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "set",
+		Usage:       "Create a context or change its defaults",
+		ArgsUsage:   "[context name]",
+		Description: "Creates the context if it does not exist. Pass an empty value (e.g. --channel \"\") to clear a default.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "bot",
+				Usage: "Bot the context uses (defaults to the current bot for new contexts)",
+			},
+			&cli.StringFlag{
+				Name:  "guild",
+				Usage: "Default guild (ID or name) for required --guild flags",
+			},
+			&cli.StringFlag{
+				Name:  "channel",
+				Usage: "Default channel (ID or name) for required --channel flags",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Default output format (table|json|yaml|ndjson|csv|tsv)",
+			},
+			&cli.BoolFlag{
+				Name:  "use",
+				Usage: "Also make the context the current one",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.NArg() < 1 {
+				return utils.ValidationError("specify context name")
+			}
+			name := c.Args().First()
+
+			created := false
+			err := updateConfig(func(config *cfg.Config) error {
+				entry, err := config.GetContextByName(name)
+				if err != nil {
+					created = true
+					config.Contexts = append(config.Contexts, cfg.Context{Name: name, Bot: config.CurrentBot})
+					entry = &config.Contexts[len(config.Contexts)-1]
+				}
+
+				if c.IsSet("bot") {
+					entry.Bot = c.String("bot")
+				}
+				if _, err := config.GetBotByName(entry.Bot); err != nil {
+					return utils.NotFoundErrorf("bot '%s' not found", entry.Bot)
+				}
+				if c.IsSet("guild") {
+					entry.Guild = c.String("guild")
+				}
+				if c.IsSet("channel") {
+					entry.Channel = c.String("channel")
+				}
+				if c.IsSet("output") {
+					entry.Output = c.String("output")
+					if entry.Output != "" {
+						if _, err := dprint.ParseFormat(entry.Output); err != nil {
+							return utils.ValidationErrorf("invalid output format '%s'", entry.Output)
+						}
+					}
+				}
+				if c.Bool("use") {
+					config.CurrentContext = name
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			// The command's own --output sets the context's default and
+			// shadows the global one
+			output := utils.NewOutputManager(c.Root())
+			if output.GetFormat() == dprint.FormatTable {
+				if created {
+					fmt.Printf("Context '%s' created\n", name)
+				} else {
+					fmt.Printf("Context '%s' updated\n", name)
+				}
+			} else {
+				result := contextResult{
+					Context: name,
+					Created: created,
+				}
+				if err := output.Print(result); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
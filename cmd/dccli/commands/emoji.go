@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
@@ -23,6 +24,7 @@ func EmojiCommand() *cli.Command {
 			EmojiListCommand(),
 			EmojiGetCommand(),
 			EmojiCreateCommand(),
+			EmojiUploadCommand(),
 			EmojiEditCommand(),
 			EmojiDeleteCommand(),
 		},
@@ -57,30 +59,8 @@ func EmojiListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, emoji := range emojis {
-					animated := "No"
-					if emoji.Animated {
-						animated = "Yes"
-					}
-					managed := "No"
-					if emoji.Managed {
-						managed = "Yes"
-					}
-					data = append(data, []string{
-						emoji.ID,
-						emoji.Name,
-						animated,
-						managed,
-					})
-				}
-				header := []string{"ID", "Name", "Animated", "Managed"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(emojis); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(emojis, newEmojiRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -111,8 +91,11 @@ func EmojiGetCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("emoji ID is required")
 			}
-			emojiID := c.Args().First()
 			guildID := c.String("guild")
+			emojiID, err := cliCtx.Resolver.Emoji(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			emoji, err := cliCtx.Client.GetGuildEmoji(guildID, emojiID)
 			if err != nil {
@@ -196,7 +179,10 @@ func EmojiCreateCommand() *cli.Command {
 
 			// Add role restrictions if specified
 			if roles := c.StringSlice("roles"); len(roles) > 0 {
-				params.Roles = roles
+				params.Roles, err = cliCtx.Resolver.Roles(guildID, roles)
+				if err != nil {
+					return err
+				}
 			}
 
 			emoji, err := cliCtx.Client.CreateGuildEmoji(guildID, params)
@@ -212,11 +198,7 @@ func EmojiCreateCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", emoji.Name)
 				fmt.Printf("URL: https://cdn.discordapp.com/emojis/%s.%s\n", emoji.ID, getEmojiExtension(emoji))
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"emoji":   emoji,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(emoji); err != nil {
 					return err
 				}
 			}
@@ -226,6 +208,75 @@ func EmojiCreateCommand() *cli.Command {
 	}
 }
 
+// EmojiUploadCommand creates an emoji from each of many image files
+func EmojiUploadCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "upload",
+		Usage:     "Create an emoji from each image file, named after the file",
+		ArgsUsage: "[image-file...]",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "guild",
+				Usage:    "Guild ID",
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "roles",
+				Usage: "Roles that can use the emojis (comma-separated IDs)",
+			},
+		}, bulkFlags()...),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			cliCtx, err := utils.NewCLIContext(c)
+			if err != nil {
+				return err
+			}
+			defer cliCtx.Close()
+
+			guildID := c.String("guild")
+			imagePaths, err := bulkTargets(c, c.Args().Slice())
+			if err != nil {
+				return err
+			}
+			if len(imagePaths) == 0 {
+				return utils.ValidationError("at least one image file is required")
+			}
+
+			var roles []string
+			if names := c.StringSlice("roles"); len(names) > 0 {
+				roles, err = cliCtx.Resolver.Roles(guildID, names)
+				if err != nil {
+					return err
+				}
+			}
+
+			return runBulk(ctx, c, cliCtx, imagePaths, bulkOp{
+				Name:  "emoji upload " + guildID,
+				Label: "Uploading emojis",
+				Do: func(ctx context.Context, batch []string) error {
+					imagePath := batch[0]
+					imageData, err := os.ReadFile(imagePath)
+					if err != nil {
+						return utils.ValidationErrorf("failed to read image file: %w", err)
+					}
+					name := strings.TrimSuffix(filepath.Base(imagePath), filepath.Ext(imagePath))
+					params := &discordgo.EmojiParams{
+						Name:  name,
+						Image: fmt.Sprintf("data:%s;base64,%s", getMimeType(imagePath), base64.StdEncoding.EncodeToString(imageData)),
+						Roles: roles,
+					}
+					if _, err := cliCtx.Client.CreateGuildEmoji(guildID, params); err != nil {
+						return utils.DiscordErrorf("failed to create emoji %s: %w", name, err)
+					}
+					return nil
+				},
+				Summary: func(succeeded, total int) string {
+					return fmt.Sprintf("Uploaded %d of %d emojis", succeeded, total)
+				},
+			})
+		},
+	}
+}
+
 // EmojiEditCommand edits an emoji
 func EmojiEditCommand() *cli.Command {
 	return &cli.Command{
@@ -257,8 +308,11 @@ func EmojiEditCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("emoji ID is required")
 			}
-			emojiID := c.Args().First()
 			guildID := c.String("guild")
+			emojiID, err := cliCtx.Resolver.Emoji(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Get existing emoji first
 			existingEmoji, err := cliCtx.Client.GetGuildEmoji(guildID, emojiID)
@@ -277,7 +331,10 @@ func EmojiEditCommand() *cli.Command {
 
 			// Update roles if provided
 			if roles := c.StringSlice("roles"); len(roles) > 0 {
-				params.Roles = roles
+				params.Roles, err = cliCtx.Resolver.Roles(guildID, roles)
+				if err != nil {
+					return err
+				}
 			} else {
 				roleIDs := make([]string, len(existingEmoji.Roles))
 				copy(roleIDs, existingEmoji.Roles)
@@ -296,11 +353,7 @@ func EmojiEditCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", emoji.ID)
 				fmt.Printf("Name: %s\n", emoji.Name)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"emoji":   emoji,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(emoji); err != nil {
 					return err
 				}
 			}
@@ -337,8 +390,11 @@ func EmojiDeleteCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("emoji ID is required")
 			}
-			emojiID := c.Args().First()
 			guildID := c.String("guild")
+			emojiID, err := cliCtx.Resolver.Emoji(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Get emoji info for confirmation
 			emoji, err := cliCtx.Client.GetGuildEmoji(guildID, emojiID)
@@ -347,13 +403,13 @@ func EmojiDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete emoji: :%s: (ID: %s)\n", emoji.Name, emojiID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete emoji: :%s: (ID: %s)\n", emoji.Name, emojiID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -367,10 +423,10 @@ func EmojiDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted emoji: :%s:\n", emoji.Name)
 			} else {
-				result := map[string]interface{}{
-					"success":  true,
-					"emoji_id": emojiID,
-					"name":     emoji.Name,
+				result := deleted{
+					ID:      emojiID,
+					Name:    emoji.Name,
+					GuildID: guildID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
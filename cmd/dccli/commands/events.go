@@ -3,11 +3,13 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/urfave/cli/v3"
 
+	"github.com/FlameInTheDark/dccli/pkg/discord"
 	"github.com/FlameInTheDark/dccli/pkg/dprint"
 	"github.com/FlameInTheDark/dccli/pkg/utils"
 )
@@ -57,24 +59,8 @@ func EventsListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, event := range events {
-					startTime := event.ScheduledStartTime.Format("2006-01-02 15:04")
-					status := eventStatusToString(event.Status)
-					data = append(data, []string{
-						event.ID,
-						event.Name,
-						status,
-						startTime,
-					})
-				}
-				header := []string{"ID", "Name", "Status", "Start Time"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(events); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(events, newEventRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -267,11 +253,7 @@ func EventsCreateCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", event.Name)
 				fmt.Printf("URL: https://discord.com/events/%s/%s\n", guildID, event.ID)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"event":   event,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(event); err != nil {
 					return err
 				}
 			}
@@ -367,11 +349,7 @@ func EventsEditCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", event.Name)
 				fmt.Printf("Status: %s\n", eventStatusToString(event.Status))
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"event":   event,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(event); err != nil {
 					return err
 				}
 			}
@@ -418,13 +396,13 @@ func EventsDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete event: %s (ID: %s)\n", event.Name, eventID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete event: %s (ID: %s)\n", event.Name, eventID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				var response string
 				fmt.Scanln(&response)
 				if response != "yes" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -438,10 +416,10 @@ func EventsDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted event: %s\n", event.Name)
 			} else {
-				result := map[string]interface{}{
-					"success":  true,
-					"event_id": eventID,
-					"name":     event.Name,
+				result := deleted{
+					ID:      eventID,
+					Name:    event.Name,
+					GuildID: guildID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -459,7 +437,7 @@ func EventsUsersCommand() *cli.Command {
 		Name:      "users",
 		Usage:     "List users subscribed to an event",
 		ArgsUsage: "[event-id]",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "guild",
 				Usage:    "Guild ID",
@@ -470,7 +448,15 @@ func EventsUsersCommand() *cli.Command {
 				Usage: "Limit number of users (max 100)",
 				Value: 100,
 			},
-		},
+			&cli.StringFlag{
+				Name:  "before",
+				Usage: "Get users before this user ID, or whose account was created before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when they subscribed",
+			},
+			&cli.StringFlag{
+				Name:  "after",
+				Usage: "Get users after this user ID, or whose account was created after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when they subscribed",
+			},
+		}, paginationFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -478,37 +464,34 @@ func EventsUsersCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
+			before, after, err := snowflakeBounds(c)
+			if err != nil {
+				return err
+			}
+
 			if c.NArg() < 1 {
 				return utils.ValidationError("event ID is required")
 			}
 			eventID := c.Args().First()
 			guildID := c.String("guild")
 
-			users, err := cliCtx.Client.GetGuildEventUsers(guildID, eventID, int(c.Int("limit")), "", "")
+			output := cliCtx.GetOutputManager()
+
+			if paginateAll(c) {
+				users := discord.IterGuildEventUsers(cliCtx.Client, guildID, eventID, before, after)
+				if err := streamPages(c, output, tableRows(users, newEventUserRow)); err != nil {
+					return utils.DiscordErrorf("failed to list event users: %w", err)
+				}
+				return nil
+			}
+
+			users, err := cliCtx.Client.GetGuildEventUsers(guildID, eventID, int(c.Int("limit")), before, after)
 			if err != nil {
 				return utils.DiscordErrorf("failed to list event users: %w", err)
 			}
 
-			output := cliCtx.GetOutputManager()
-
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, u := range users {
-					username := u.User.Username
-					if u.User.Discriminator != "0" {
-						username += "#" + u.User.Discriminator
-					}
-					data = append(data, []string{
-						u.User.ID,
-						username,
-					})
-				}
-				header := []string{"ID", "Username"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(users); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(users, newEventUserRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -558,11 +541,7 @@ func EventsStartCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", event.ID)
 				fmt.Printf("Status: %s\n", eventStatusToString(event.Status))
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"event":   event,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(event); err != nil {
 					return err
 				}
 			}
@@ -614,11 +593,7 @@ func EventsEndCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", event.ID)
 				fmt.Printf("Status: %s\n", eventStatusToString(event.Status))
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"event":   event,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(event); err != nil {
 					return err
 				}
 			}
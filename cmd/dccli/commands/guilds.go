@@ -10,6 +10,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/urfave/cli/v3"
 
+	"github.com/FlameInTheDark/dccli/pkg/discord"
 	"github.com/FlameInTheDark/dccli/pkg/dprint"
 	"github.com/FlameInTheDark/dccli/pkg/utils"
 )
@@ -18,21 +19,21 @@ func GuildListCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "list",
 		Usage: "Returns a list of guilds",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:  "before",
-				Usage: "Before guild ID",
+				Usage: "Before this guild ID, or guilds created before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when the bot joined",
 			},
 			&cli.StringFlag{
 				Name:  "after",
-				Usage: "After guild ID",
+				Usage: "After this guild ID, or guilds created after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when the bot joined",
 			},
 			&cli.IntFlag{
 				Name:  "limit",
 				Usage: "Limit number of guilds. Max 100. Default is 10",
 				Value: 10,
 			},
-		},
+		}, paginationFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -40,32 +41,37 @@ func GuildListCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			guilds, err := cliCtx.Client.GuildList(int(c.Int("limit")), c.String("before"), c.String("after"))
+			before, after, err := snowflakeBounds(c)
 			if err != nil {
-				return utils.DiscordErrorf("failed to list guilds: %w", err)
+				return err
 			}
 
 			output := cliCtx.GetOutputManager()
 
+			if paginateAll(c) {
+				guilds := discord.IterGuilds(cliCtx.Client, before, after)
+				if err := streamPages(c, output, guilds); err != nil {
+					return utils.DiscordErrorf("failed to list guilds: %w", err)
+				}
+				return nil
+			}
+
+			guilds, err := cliCtx.Client.GuildList(int(c.Int("limit")), before, after)
+			if err != nil {
+				return utils.DiscordErrorf("failed to list guilds: %w", err)
+			}
+
 			if output.GetFormat() == dprint.FormatTable {
 				if c.String("after") != "" {
-					fmt.Println("Results after: ", c.String("after"))
+					cliCtx.Statusf("Results after: %s\n", c.String("after"))
 				}
 				if c.String("before") != "" {
-					fmt.Println("Results before: ", c.String("before"))
-				}
-
-				data := [][]string{}
-				for _, g := range guilds {
-					data = append(data, []string{g.ID, g.Name})
-				}
-				header := []string{"ID", "Name"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(guilds); err != nil {
-					return err
+					cliCtx.Statusf("Results before: %s\n", c.String("before"))
 				}
 			}
+			if err := output.Print(guilds); err != nil {
+				return err
+			}
 
 			return nil
 		},
@@ -84,7 +90,12 @@ func DescribeGuildCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			g, err := cliCtx.Client.GuildDescribe(c.Args().Get(0))
+			guildID, err := cliCtx.Resolver.Guild(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			g, err := cliCtx.Client.GuildDescribe(guildID)
 			if err != nil {
 				return utils.DiscordErrorf("failed to describe guild: %w", err)
 			}
@@ -128,7 +139,10 @@ func LeaveGuildCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("guild ID is required")
 			}
-			guildID := c.Args().First()
+			guildID, err := cliCtx.Resolver.Guild(c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Get guild info for confirmation
 			guild, err := cliCtx.Client.GuildDescribe(guildID)
@@ -137,13 +151,13 @@ func LeaveGuildCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to leave guild: %s (ID: %s)\n", guild.Name, guildID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to leave guild: %s (ID: %s)\n", guild.Name, guildID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -158,10 +172,9 @@ func LeaveGuildCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully left guild: %s\n", guild.Name)
 			} else {
-				result := map[string]interface{}{
-					"success":  true,
-					"guild_id": guildID,
-					"name":     guild.Name,
+				result := deleted{
+					ID:   guildID,
+					Name: guild.Name,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -226,7 +239,10 @@ func EditGuildCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("guild ID is required")
 			}
-			guildID := c.Args().First()
+			guildID, err := cliCtx.Resolver.Guild(c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Build guild params
 			params := &discordgo.GuildParams{}
@@ -285,11 +301,7 @@ func EditGuildCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", updatedGuild.Name)
 				fmt.Printf("Region: %s\n", updatedGuild.Region)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"guild":   updatedGuild,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(updatedGuild); err != nil {
 					return err
 				}
 			}
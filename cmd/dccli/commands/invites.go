@@ -121,36 +121,8 @@ func ListInvitesCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, invite := range invites {
-					uses := fmt.Sprintf("%d", invite.Uses)
-					maxUses := fmt.Sprintf("%d", invite.MaxUses)
-					if invite.MaxUses == 0 {
-						maxUses = "∞"
-					}
-					expires := "Never"
-					if invite.MaxAge > 0 {
-						expires = fmt.Sprintf("%ds", invite.MaxAge)
-					}
-					inviter := "-"
-					if invite.Inviter != nil {
-						inviter = invite.Inviter.Username
-					}
-					data = append(data, []string{
-						invite.Code,
-						inviter,
-						uses,
-						maxUses,
-						expires,
-					})
-				}
-				header := []string{"Code", "Inviter", "Uses", "Max Uses", "Expires"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(invites); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(invites, newInviteRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -194,7 +166,10 @@ func CreateInviteCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("channel ID is required")
 			}
-			channelID := c.Args().First()
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			maxAge := int(c.Int("max-age"))
 			maxUses := int(c.Int("max-uses"))
@@ -227,11 +202,7 @@ unique := c.Bool("unique")
 					fmt.Printf("Max uses: Unlimited\n")
 				}
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"invite":  invite,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(invite); err != nil {
 					return err
 				}
 			}
@@ -272,17 +243,17 @@ func DeleteInviteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
+			if !c.Bool("force") && !c.Bool("dry-run") {
 				channelName := "Unknown"
 				if invite.Channel != nil {
 					channelName = invite.Channel.Name
 				}
-				fmt.Printf("You are about to delete invite: %s (Channel: %s)\n", inviteCode, channelName)
-				fmt.Print("Are you sure? (yes/no): ")
+				fmt.Fprintf(os.Stderr, "You are about to delete invite: %s (Channel: %s)\n", inviteCode, channelName)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -296,10 +267,7 @@ func DeleteInviteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted invite: %s\n", inviteCode)
 			} else {
-				result := map[string]interface{}{
-					"success":     true,
-					"invite_code": inviteCode,
-				}
+				result := deleted{ID: inviteCode}
 				if err := output.Print(result); err != nil {
 					return err
 				}
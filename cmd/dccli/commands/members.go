@@ -3,10 +3,12 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/FlameInTheDark/dccli/pkg/discord"
 	"github.com/FlameInTheDark/dccli/pkg/dprint"
 	"github.com/FlameInTheDark/dccli/pkg/utils"
 )
@@ -20,6 +22,7 @@ func MembersCommand() *cli.Command {
 			MembersGetCommand(),
 			MembersBanCommand(),
 			MembersUnbanCommand(),
+			MembersBansCommand(),
 			MembersKickCommand(),
 			MembersTimeoutCommand(),
 			MembersUntimeoutCommand(),
@@ -34,7 +37,7 @@ func MembersListCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "list",
 		Usage: "List members in a guild",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "guild",
 				Usage:    "Guild ID",
@@ -47,9 +50,9 @@ func MembersListCommand() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "after",
-				Usage: "Get members after this user ID",
+				Usage: "Get members after this user ID, or whose account was created after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when they joined",
 			},
-		},
+		}, paginationFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -57,39 +60,29 @@ func MembersListCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			guildID := c.String("guild")
-
-			members, err := cliCtx.Client.GetGuildMembers(guildID, int(c.Int("limit")), c.String("after"))
+			_, after, err := snowflakeBounds(c)
 			if err != nil {
-				return utils.DiscordErrorf("failed to list members: %w", err)
+				return err
 			}
 
+			guildID := c.String("guild")
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, m := range members {
-					username := m.User.Username
-					if m.User.Discriminator != "0" {
-						username += "#" + m.User.Discriminator
-					}
-					nick := m.Nick
-					if nick == "" {
-						nick = "-"
-					}
-					data = append(data, []string{
-						m.User.ID,
-						username,
-						nick,
-						m.JoinedAt.Format("2006-01-02"),
-					})
-				}
-				header := []string{"ID", "Username", "Nickname", "Joined"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(members); err != nil {
-					return err
+			if paginateAll(c) {
+				members := discord.IterGuildMembers(cliCtx.Client, guildID, after)
+				if err := streamPages(c, output, tableRows(members, newMemberRow)); err != nil {
+					return utils.DiscordErrorf("failed to list members: %w", err)
 				}
+				return nil
+			}
+
+			members, err := cliCtx.Client.GetGuildMembers(guildID, int(c.Int("limit")), after)
+			if err != nil {
+				return utils.DiscordErrorf("failed to list members: %w", err)
+			}
+
+			if err := output.Print(rowsOf(members, newMemberRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -119,8 +112,11 @@ func MembersGetCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			member, err := cliCtx.Client.GetGuildMember(guildID, userID)
 			if err != nil {
@@ -157,18 +153,14 @@ func MembersGetCommand() *cli.Command {
 func MembersBanCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "ban",
-		Usage:     "Ban a member from the guild",
-		ArgsUsage: "[user-id]",
-		Flags: []cli.Flag{
+		Usage:     "Ban one or more members from the guild",
+		ArgsUsage: "[user-id...]",
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "guild",
 				Usage:    "Guild ID",
 				Required: true,
 			},
-			&cli.StringFlag{
-				Name:  "reason",
-				Usage: "Ban reason",
-			},
 			&cli.IntFlag{
 				Name:  "delete-days",
 				Usage: "Number of days of messages to delete (0-7)",
@@ -178,7 +170,7 @@ func MembersBanCommand() *cli.Command {
 				Name:  "force",
 				Usage: "Skip confirmation prompt",
 			},
-		},
+		}, bulkFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -186,49 +178,60 @@ func MembersBanCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			if c.NArg() < 1 {
+			guildID := c.String("guild")
+			userIDs, err := bulkTargets(c, c.Args().Slice())
+			if err != nil {
+				return err
+			}
+			if len(userIDs) == 0 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
-			guildID := c.String("guild")
+			userIDs, err = cliCtx.Resolver.Users(guildID, userIDs)
+			if err != nil {
+				return err
+			}
 			deleteDays := int(c.Int("delete-days"))
-			reason := c.String("reason")
+			reason := cliCtx.Reason
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to ban user: %s\n", userID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				if c.String("targets-file") == "-" {
+					return utils.ValidationError("--force is required when reading targets from stdin")
+				}
+				if len(userIDs) == 1 {
+					fmt.Fprintf(os.Stderr, "You are about to ban user: %s\n", userIDs[0])
+				} else {
+					fmt.Fprintf(os.Stderr, "You are about to ban %d users\n", len(userIDs))
+				}
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				var response string
 				fmt.Scanln(&response)
 				if response != "yes" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
 
-			if err := cliCtx.Client.GuildBanCreate(guildID, userID, deleteDays, reason); err != nil {
-				return utils.DiscordErrorf("failed to ban member: %w", err)
-			}
-
-			output := cliCtx.GetOutputManager()
-
-			if output.GetFormat() == dprint.FormatTable {
-				fmt.Printf("Successfully banned user: %s\n", userID)
-				if reason != "" {
-					fmt.Printf("Reason: %s\n", reason)
-				}
-			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
-					"reason":  reason,
-				}
-				if err := output.Print(result); err != nil {
-					return err
-				}
-			}
-
-			return nil
+			return runBulk(ctx, c, cliCtx, userIDs, bulkOp{
+				Name:  fmt.Sprintf("members ban %s %d", guildID, deleteDays),
+				Label: "Banning",
+				Do: func(ctx context.Context, batch []string) error {
+					if err := cliCtx.Client.GuildBanCreate(guildID, batch[0], deleteDays, reason); err != nil {
+						return utils.DiscordErrorf("failed to ban member: %w", err)
+					}
+					return nil
+				},
+				Summary: func(succeeded, total int) string {
+					summary := fmt.Sprintf("Banned %d of %d users", succeeded, total)
+					if total == 1 && succeeded == 1 {
+						summary = fmt.Sprintf("Successfully banned user: %s", userIDs[0])
+					}
+					if reason != "" {
+						summary += "\nReason: " + reason
+					}
+					return summary
+				},
+			})
 		},
 	}
 }
@@ -259,17 +262,20 @@ func MembersUnbanCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to unban user: %s\n", userID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to unban user: %s\n", userID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				var response string
 				fmt.Scanln(&response)
 				if response != "yes" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -283,9 +289,9 @@ func MembersUnbanCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully unbanned user: %s\n", userID)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
+				result := memberAction{
+					GuildID: guildID,
+					UserID:  userID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -297,6 +303,67 @@ func MembersUnbanCommand() *cli.Command {
 	}
 }
 
+func MembersBansCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bans",
+		Usage: "List banned users of a guild",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "guild",
+				Usage:    "Guild ID",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Maximum number of bans to return (1-1000)",
+				Value: 100,
+			},
+			&cli.StringFlag{
+				Name:  "before",
+				Usage: "Get bans before this user ID, or of accounts created before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when they were banned",
+			},
+			&cli.StringFlag{
+				Name:  "after",
+				Usage: "Get bans after this user ID, or of accounts created after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when they were banned",
+			},
+		}, paginationFlags()...),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			cliCtx, err := utils.NewCLIContext(c)
+			if err != nil {
+				return err
+			}
+			defer cliCtx.Close()
+
+			before, after, err := snowflakeBounds(c)
+			if err != nil {
+				return err
+			}
+
+			guildID := c.String("guild")
+			output := cliCtx.GetOutputManager()
+
+			if paginateAll(c) {
+				bans := discord.IterGuildBans(cliCtx.Client, guildID, before, after)
+				if err := streamPages(c, output, tableRows(bans, newBanRow)); err != nil {
+					return utils.DiscordErrorf("failed to list bans: %w", err)
+				}
+				return nil
+			}
+
+			bans, err := cliCtx.Client.GetGuildBans(guildID, int(c.Int("limit")), before, after)
+			if err != nil {
+				return utils.DiscordErrorf("failed to list bans: %w", err)
+			}
+
+			if err := output.Print(rowsOf(bans, newBanRow)); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+}
+
 func MembersKickCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "kick",
@@ -308,10 +375,6 @@ func MembersKickCommand() *cli.Command {
 				Usage:    "Guild ID",
 				Required: true,
 			},
-			&cli.StringFlag{
-				Name:  "reason",
-				Usage: "Kick reason",
-			},
 			&cli.BoolFlag{
 				Name:  "force",
 				Usage: "Skip confirmation prompt",
@@ -327,17 +390,20 @@ func MembersKickCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to kick user: %s\n", userID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to kick user: %s\n", userID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				var response string
 				fmt.Scanln(&response)
 				if response != "yes" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -350,14 +416,14 @@ func MembersKickCommand() *cli.Command {
 
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully kicked user: %s\n", userID)
-				if reason := c.String("reason"); reason != "" {
+				if reason := cliCtx.Reason; reason != "" {
 					fmt.Printf("Reason: %s\n", reason)
 				}
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
-					"reason":  c.String("reason"),
+				result := memberAction{
+					GuildID: guildID,
+					UserID:  userID,
+					Reason:  cliCtx.Reason,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -396,8 +462,11 @@ func MembersTimeoutCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 			durationStr := c.String("duration")
 
 			duration, err := time.ParseDuration(durationStr)
@@ -415,11 +484,10 @@ func MembersTimeoutCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully timed out user: %s for %s\n", userID, durationStr)
 			} else {
-				result := map[string]interface{}{
-					"success":  true,
-					"user_id":  userID,
-					"duration": durationStr,
-					"until":    until,
+				result := memberAction{
+					GuildID: guildID,
+					UserID:  userID,
+					Until:   &until,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -453,8 +521,11 @@ func MembersUntimeoutCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			if err := cliCtx.Client.GuildMemberTimeout(guildID, userID, nil); err != nil {
 				return utils.DiscordErrorf("failed to remove timeout: %w", err)
@@ -465,9 +536,9 @@ func MembersUntimeoutCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully removed timeout from user: %s\n", userID)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
+				result := memberAction{
+					GuildID: guildID,
+					UserID:  userID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -505,8 +576,11 @@ func MembersNickCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 			nick := c.String("nick")
 
 			// If nickname provided as argument
@@ -527,10 +601,10 @@ func MembersNickCommand() *cli.Command {
 					fmt.Printf("Successfully set nickname to '%s' for user: %s\n", nick, userID)
 				}
 			} else {
-				result := map[string]interface{}{
-					"success":  true,
-					"user_id":  userID,
-					"nickname": nick,
+				result := memberAction{
+					GuildID:  guildID,
+					UserID:   userID,
+					Nickname: nick,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -569,8 +643,11 @@ func MembersAddRoleCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 			roleID := c.String("role")
 
 			if err := cliCtx.Client.GuildMemberRoleAdd(guildID, userID, roleID); err != nil {
@@ -582,10 +659,10 @@ func MembersAddRoleCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully added role %s to user %s\n", roleID, userID)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
-					"role_id": roleID,
+				result := memberAction{
+					GuildID: guildID,
+					UserID:  userID,
+					RoleID:  roleID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -624,8 +701,11 @@ func MembersRemoveRoleCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("user ID is required")
 			}
-			userID := c.Args().First()
 			guildID := c.String("guild")
+			userID, err := cliCtx.Resolver.User(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 			roleID := c.String("role")
 
 			if err := cliCtx.Client.GuildMemberRoleRemove(guildID, userID, roleID); err != nil {
@@ -637,10 +717,10 @@ func MembersRemoveRoleCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully removed role %s from user %s\n", roleID, userID)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
-					"role_id": roleID,
+				result := memberAction{
+					GuildID: guildID,
+					UserID:  userID,
+					RoleID:  roleID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -4,17 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strconv"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/urfave/cli/v3"
 
+	"github.com/FlameInTheDark/dccli/pkg/discord"
 	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/transcript"
 	"github.com/FlameInTheDark/dccli/pkg/utils"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
 )
 
 func MessagesCommand() *cli.Command {
@@ -26,13 +37,23 @@ func MessagesCommand() *cli.Command {
 			MessagesSendCommand(),
 			MessagesEditCommand(),
 			MessagesDeleteCommand(),
+			MessagesPurgeCommand(),
 			MessagesListenCommand(),
 			MessagesReactionsCommand(),
 			MessagesValidateEmbedCommand(),
+			MessagesExportCommand(),
 		},
 	}
 }
 
+// ListenMessageOutput is a message received by messages listen
+type ListenMessageOutput struct {
+	Username    string   `json:"username"`
+	UserID      string   `json:"user_id"`
+	Content     string   `json:"content"`
+	Attachments []string `json:"attachments"`
+}
+
 func MessagesListenCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "listen",
@@ -50,7 +71,7 @@ func MessagesListenCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"f"},
-				Usage:   "Output format: text, json",
+				Usage:   "Output format: text, json, ndjson",
 			},
 			&cli.IntFlag{
 				Name:    "limit",
@@ -73,9 +94,19 @@ func MessagesListenCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
+			channelID, err = cliCtx.Resolver.Channel("", channelID)
+			if err != nil {
+				return err
+			}
+			filterUsers, err = cliCtx.Resolver.Users("", filterUsers)
+			if err != nil {
+				return err
+			}
+
 			// Override output format if specified via flag
-			if c.String("format") == "json" {
-				cliCtx.OutputFormat = dprint.FormatJSON
+			switch c.String("format") {
+			case "json", "ndjson":
+				cliCtx.OutputFormat = dprint.FormatNDJSON
 			}
 
 			// Get current bot ID for mention check
@@ -86,26 +117,29 @@ func MessagesListenCommand() *cli.Command {
 			botID := me.ID
 
 			output := cliCtx.GetOutputManager()
-
-			if !cliCtx.Quiet {
-				if limit > 0 {
-					fmt.Printf("Listening for %d messages in channel %s... Press Ctrl+C to stop.\n", limit, channelID)
-				} else {
-					fmt.Printf("Listening for messages in channel %s... Press Ctrl+C to stop.\n", channelID)
-				}
+			// Listening has no end to close a JSON array at, so JSON is
+			// written as one object per line
+			if output.GetFormat() == dprint.FormatJSON {
+				output.SetFormat(dprint.FormatNDJSON)
+			}
+			var stream *dprint.Stream
+			switch output.GetFormat().Kind() {
+			case dprint.FormatNDJSON, dprint.FormatCSV, dprint.FormatTSV:
+				stream = output.NewStream()
 			}
 
-			type ListenMessageOutput struct {
-				Username    string   `json:"username"`
-				UserID      string   `json:"user_id"`
-				Content     string   `json:"content"`
-				Attachments []string `json:"attachments,omitempty"`
+			if limit > 0 {
+				cliCtx.Statusf("Listening for %d messages in channel %s... Press Ctrl+C to stop.\n", limit, channelID)
+			} else {
+				cliCtx.Statusf("Listening for messages in channel %s... Press Ctrl+C to stop.\n", channelID)
 			}
 
 			done := make(chan struct{})
 			count := 0
+			// Handlers run concurrently, keep their output in one piece
+			var mu sync.Mutex
 
-			cliCtx.Client.Session().AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+			cliCtx.Client.AddMessageHandler(func(m *discordgo.MessageCreate) {
 				if m.ChannelID != channelID {
 					return
 				}
@@ -136,25 +170,38 @@ func MessagesListenCommand() *cli.Command {
 				}
 				}
 
-				if output.GetFormat() == dprint.FormatJSON {
-					msg := ListenMessageOutput{
-						Username: m.Author.Username,
-						UserID:   m.Author.ID,
-						Content:  m.Content,
-					}
-					for _, att := range m.Attachments {
-						msg.Attachments = append(msg.Attachments, att.URL)
-					}
+				mu.Lock()
+				defer mu.Unlock()
 
-					data, err := json.Marshal(msg)
-					if err == nil {
-						fmt.Println(string(data))
-					}
-				} else {
+				msg := ListenMessageOutput{
+					Username:    m.Author.Username,
+					UserID:      m.Author.ID,
+					Content:     m.Content,
+					Attachments: []string{},
+				}
+				for _, att := range m.Attachments {
+					msg.Attachments = append(msg.Attachments, att.URL)
+				}
+
+				switch {
+				case output.GetFormat() == dprint.FormatTable:
 					fmt.Printf("%s (%s): %s\n", m.Author.Username, m.Author.ID, m.Content)
 					for _, att := range m.Attachments {
 						fmt.Printf("Attachment: %s\n", att.URL)
 					}
+				case stream != nil:
+					// One line per message so the output can be piped
+					if err := stream.Add(msg); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+					}
+				default:
+					// Every message is a separate YAML document or template run
+					if output.GetFormat() == dprint.FormatYAML {
+						fmt.Println("---")
+					}
+					if err := output.Print(msg); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+					}
 				}
 
 				if limit > 0 {
@@ -170,6 +217,11 @@ func MessagesListenCommand() *cli.Command {
 				}
 			})
 
+			// Only event-driven commands need the gateway, so open it here
+			if err := cliCtx.Client.Connect(); err != nil {
+				return utils.DiscordErrorf("failed to connect to gateway: %w", err)
+			}
+
 			// Wait for interrupt signal or limit reached
 			sc := make(chan os.Signal, 1)
 			signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
@@ -181,6 +233,11 @@ func MessagesListenCommand() *cli.Command {
 				// Limit reached
 			}
 
+			if stream != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				return stream.Close()
+			}
 			return nil
 		},
 	}
@@ -201,7 +258,10 @@ func MessagesGetCommand() *cli.Command {
 			if c.NArg() < 2 {
 				return utils.ValidationError("channel ID and message ID are required")
 			}
-			channelID := c.Args().Get(0)
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().Get(0))
+			if err != nil {
+				return err
+			}
 			messageID := c.Args().Get(1)
 
 			message, err := cliCtx.Client.GetChannelMessage(channelID, messageID)
@@ -274,7 +334,10 @@ func MessagesSendCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("channel ID is required")
 			}
-			channelID := c.Args().First()
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
 			content := c.String("content")
 			embedJSON := c.String("embed")
 			embedFile := c.String("embed-file")
@@ -321,7 +384,7 @@ func MessagesSendCommand() *cli.Command {
 					// Try to reconstruct JSON if it was split by shell
 					if c.Args().Len() > 0 {
 						if _, ok := utils.ReconstructJSON(embedJSON, c.Args().Slice(), &msg.Embed); ok {
-							fmt.Println("Warning: JSON argument appeared to be split by shell. Successfully reconstructed.")
+							fmt.Fprintln(os.Stderr, "Warning: JSON argument appeared to be split by shell. Successfully reconstructed.")
 						} else {
 							return utils.ValidationErrorf("failed to parse embed JSON: %w (Hint: check shell quoting or use --embed-file)", err)
 						}
@@ -351,12 +414,7 @@ func MessagesSendCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", message.ID)
 				fmt.Printf("Channel: %s\n", channelID)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"message_id": message.ID,
-					"channel_id": channelID,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(message); err != nil {
 					return err
 				}
 			}
@@ -391,7 +449,10 @@ func MessagesEditCommand() *cli.Command {
 			if c.NArg() < 2 {
 				return utils.ValidationError("channel ID and message ID are required")
 			}
-			channelID := c.Args().Get(0)
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().Get(0))
+			if err != nil {
+				return err
+			}
 			messageID := c.Args().Get(1)
 
 			if c.String("content") == "" && c.String("embed-file") == "" {
@@ -431,11 +492,7 @@ func MessagesEditCommand() *cli.Command {
 				fmt.Printf("Message edited successfully!\n")
 				fmt.Printf("ID: %s\n", message.ID)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"message_id": message.ID,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(message); err != nil {
 					return err
 				}
 			}
@@ -467,17 +524,20 @@ func MessagesDeleteCommand() *cli.Command {
 			if c.NArg() < 2 {
 				return utils.ValidationError("channel ID and message ID are required")
 			}
-			channelID := c.Args().Get(0)
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().Get(0))
+			if err != nil {
+				return err
+			}
 			messageID := c.Args().Get(1)
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete message: %s\n", messageID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete message: %s\n", messageID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				var response string
 				fmt.Scanln(&response)
 				if response != "yes" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -491,9 +551,9 @@ func MessagesDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted message: %s\n", messageID)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"message_id": messageID,
+				result := deleted{
+					ID:        messageID,
+					ChannelID: channelID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -505,6 +565,233 @@ func MessagesDeleteCommand() *cli.Command {
 	}
 }
 
+// MessagesPurgeCommand deletes the messages of a channel that match filters
+func MessagesPurgeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "purge",
+		Usage:     "Delete the messages of a channel that match filters, newest first",
+		ArgsUsage: "<channel-id>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "Only messages by this user (ID, mention or name)",
+			},
+			&cli.StringFlag{
+				Name:  "contains",
+				Usage: "Only messages containing this text (case-insensitive)",
+			},
+			&cli.StringFlag{
+				Name:  "regex",
+				Usage: "Only messages whose content matches this regular expression",
+			},
+			&cli.BoolFlag{
+				Name:  "bots",
+				Usage: "Only messages sent by bots",
+			},
+			&cli.BoolFlag{
+				Name:  "has-attachments",
+				Usage: "Only messages with attachments",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only messages sent after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "Only messages sent before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+			},
+			&cli.IntFlag{
+				Name:  "max",
+				Usage: "Delete at most this many messages, the newest matching ones (0 for no limit)",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Skip confirmation prompt",
+			},
+		}, bulkRunFlags()...),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			cliCtx, err := utils.NewCLIContext(c)
+			if err != nil {
+				return err
+			}
+			defer cliCtx.Close()
+
+			if c.NArg() < 1 {
+				return utils.ValidationError("channel ID is required")
+			}
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
+
+			filter := purgeFilter{
+				contains:       strings.ToLower(c.String("contains")),
+				bots:           c.Bool("bots"),
+				hasAttachments: c.Bool("has-attachments"),
+			}
+			if filter.since, err = utils.ParseTime(c.String("since")); err != nil {
+				return utils.ValidationErrorf("invalid --since: %w", err)
+			}
+			if filter.until, err = utils.ParseTime(c.String("until")); err != nil {
+				return utils.ValidationErrorf("invalid --until: %w", err)
+			}
+			if pattern := c.String("regex"); pattern != "" {
+				if filter.regex, err = regexp.Compile(pattern); err != nil {
+					return utils.ValidationErrorf("invalid --regex: %w", err)
+				}
+			}
+			if from := c.String("from"); from != "" {
+				guildID, err := cliCtx.Resolver.ChannelGuild(channelID)
+				if err != nil {
+					return err
+				}
+				if filter.from, err = cliCtx.Resolver.User(guildID, from); err != nil {
+					return err
+				}
+			}
+			limit := int(c.Int("max"))
+			if filter.empty() && limit == 0 {
+				return utils.ValidationError("no filters given, pass --max to purge the newest messages regardless of content")
+			}
+
+			// Walk back from until, newest first, so --max keeps the newest
+			before := ""
+			if !filter.until.IsZero() {
+				before = snowflake.FromTime(filter.until)
+			}
+			// Leave an hour of margin so a message does not pass the bulk
+			// delete limit while the purge runs
+			bulkCutoff := time.Now().Add(-discord.BulkDeleteMaxAge + time.Hour)
+			var recent, old []string
+			authors := map[string]int{}
+			scanned := 0
+			for message, err := range discord.IterChannelMessages(cliCtx.Client, channelID, before, "") {
+				if err != nil {
+					return utils.DiscordErrorf("failed to get messages: %w", err)
+				}
+				if !filter.since.IsZero() && message.Timestamp.Before(filter.since) {
+					break
+				}
+				scanned++
+				if !filter.match(message) {
+					continue
+				}
+				if message.Timestamp.After(bulkCutoff) {
+					recent = append(recent, message.ID)
+				} else {
+					old = append(old, message.ID)
+				}
+				if message.Author != nil {
+					authors[message.Author.Username]++
+				}
+				if limit > 0 && len(recent)+len(old) >= limit {
+					break
+				}
+			}
+
+			output := cliCtx.GetOutputManager()
+			targets := append(recent, old...)
+			if len(targets) == 0 && output.GetFormat() == dprint.FormatTable {
+				fmt.Printf("No messages matched among the %d scanned\n", scanned)
+				return nil
+			}
+
+			if len(targets) > 0 {
+				printPurgePreview(cliCtx, scanned, len(recent), len(old), authors)
+			}
+
+			// Confirmation prompt
+			if len(targets) > 0 && !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete %d messages\n", len(targets))
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
+				var response string
+				fmt.Scanln(&response)
+				if response != "yes" {
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
+					return nil
+				}
+			}
+
+			bulk := make(map[string]bool, len(recent))
+			for _, id := range recent {
+				bulk[id] = true
+			}
+			return runBulk(ctx, c, cliCtx, targets, bulkOp{
+				Name:      "messages purge " + channelID,
+				Label:     "Deleting messages",
+				BatchSize: 100,
+				Batchable: func(id string) bool { return bulk[id] },
+				Do: func(ctx context.Context, batch []string) error {
+					if len(batch) == 1 {
+						if err := cliCtx.Client.DeleteChannelMessage(channelID, batch[0]); err != nil {
+							return utils.DiscordErrorf("failed to delete message: %w", err)
+						}
+						return nil
+					}
+					if err := cliCtx.Client.BulkDeleteMessages(channelID, batch); err != nil {
+						return utils.DiscordErrorf("failed to bulk delete messages: %w", err)
+					}
+					return nil
+				},
+				Summary: func(succeeded, total int) string {
+					if succeeded == total {
+						return fmt.Sprintf("Successfully deleted %d messages", total)
+					}
+					return fmt.Sprintf("Deleted %d of %d messages", succeeded, total)
+				},
+			})
+		},
+	}
+}
+
+// printPurgePreview prints how many messages a purge deletes, how, and whose
+func printPurgePreview(cliCtx *utils.CLIContext, scanned, recent, old int, authors map[string]int) {
+	cliCtx.Statusf("Matched %d of %d scanned messages:\n", recent+old, scanned)
+	cliCtx.Statusf("  %6d sent within 14 days, bulk deleted in %d requests\n", recent, (recent+99)/100)
+	cliCtx.Statusf("  %6d older, deleted one by one\n", old)
+	names := slices.Collect(maps.Keys(authors))
+	sort.Slice(names, func(i, j int) bool {
+		if authors[names[i]] != authors[names[j]] {
+			return authors[names[i]] > authors[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		cliCtx.Statusf("  %6d by %s\n", authors[name], name)
+	}
+}
+
+// purgeFilter selects the messages messages purge deletes. Every set field
+// has to match.
+type purgeFilter struct {
+	from           string
+	contains       string // Lower case
+	regex          *regexp.Regexp
+	bots           bool
+	hasAttachments bool
+	since          time.Time
+	until          time.Time
+}
+
+// empty reports whether the filter matches every message
+func (f purgeFilter) empty() bool {
+	return f == purgeFilter{}
+}
+
+// match reports whether a message passes the filter. The time range is
+// checked while walking the channel.
+func (f purgeFilter) match(m *discordgo.Message) bool {
+	switch {
+	case f.from != "" && (m.Author == nil || m.Author.ID != f.from),
+		f.bots && (m.Author == nil || !m.Author.Bot),
+		f.contains != "" && !strings.Contains(strings.ToLower(m.Content), f.contains),
+		f.regex != nil && !f.regex.MatchString(m.Content),
+		f.hasAttachments && len(m.Attachments) == 0:
+		return false
+	}
+	return true
+}
+
 // MessagesReactionsCommand manages message reactions
 func MessagesReactionsCommand() *cli.Command {
 	return &cli.Command{
@@ -553,26 +840,12 @@ func ReactionsListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				if len(message.Reactions) == 0 {
-					fmt.Println("No reactions on this message")
-					return nil
-				}
-
-				data := [][]string{}
-				for _, r := range message.Reactions {
-					data = append(data, []string{
-						r.Emoji.Name,
-						r.Emoji.ID,
-						strconv.Itoa(r.Count),
-					})
-				}
-				header := []string{"Emoji", "Emoji ID", "Count"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(message.Reactions); err != nil {
-					return err
-				}
+			if output.GetFormat() == dprint.FormatTable && len(message.Reactions) == 0 {
+				fmt.Println("No reactions on this message")
+				return nil
+			}
+			if err := output.Print(rowsOf(message.Reactions, newReactionRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -608,7 +881,10 @@ func ReactionsAddCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("emoji is required")
 			}
-			emoji := c.Args().First()
+			emoji, err := cliCtx.Resolver.Reaction("", c.Args().First())
+			if err != nil {
+				return err
+			}
 			channelID := c.String("channel")
 			messageID := c.String("message")
 
@@ -621,10 +897,10 @@ func ReactionsAddCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully added reaction: %s\n", emoji)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"emoji":      emoji,
-					"message_id": messageID,
+				result := reactionAction{
+					ChannelID: channelID,
+					MessageID: messageID,
+					Emoji:     emoji,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -668,7 +944,10 @@ func ReactionsRemoveCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("emoji is required")
 			}
-			emoji := c.Args().First()
+			emoji, err := cliCtx.Resolver.Reaction("", c.Args().First())
+			if err != nil {
+				return err
+			}
 			channelID := c.String("channel")
 			messageID := c.String("message")
 			userID := c.String("user")
@@ -686,11 +965,11 @@ func ReactionsRemoveCommand() *cli.Command {
 					fmt.Printf("Successfully removed reaction: %s\n", emoji)
 				}
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"emoji":      emoji,
-					"message_id": messageID,
-					"user_id":    userID,
+				result := reactionAction{
+					ChannelID: channelID,
+					MessageID: messageID,
+					Emoji:     emoji,
+					UserID:    userID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -742,7 +1021,7 @@ func MessagesValidateEmbedCommand() *cli.Command {
 				// Try to reconstruct JSON if it was split by shell
 				if embedJSON != "" && c.Args().Len() > 0 {
 					if _, ok := utils.ReconstructJSON(embedJSON, c.Args().Slice(), &embed); ok {
-						fmt.Println("Warning: JSON argument appeared to be split by shell. Successfully reconstructed.")
+						fmt.Fprintln(os.Stderr, "Warning: JSON argument appeared to be split by shell. Successfully reconstructed.")
 						// Use the reconstructed embed for validation output
 					} else {
 						return utils.ValidationErrorf("invalid embed JSON: %w (Hint: check shell quoting or use --embed-file)", err)
@@ -752,13 +1031,212 @@ func MessagesValidateEmbedCommand() *cli.Command {
 				}
 			}
 
-			fmt.Println("Embed JSON is valid.")
-			
-			// Pretty print the parsed embed
-			output, _ := json.MarshalIndent(embed, "", "  ")
-			fmt.Println(string(output))
+			output := utils.NewOutputManager(c)
+			if output.GetFormat() == dprint.FormatTable {
+				fmt.Println("Embed JSON is valid.")
+
+				// Pretty print the parsed embed
+				pretty, _ := json.MarshalIndent(embed, "", "  ")
+				fmt.Println(string(pretty))
+			} else {
+				if err := output.Print(embed); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// MessagesExportCommand exports the history of a channel as an archive or a
+// transcript
+func MessagesExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Export channel history as NDJSON, a Markdown transcript or a single-file HTML transcript",
+		ArgsUsage: "<channel-id>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Export format (ndjson|markdown|html)",
+				Value: transcript.FormatNDJSON,
+			},
+			&cli.StringFlag{
+				Name:  "file",
+				Usage: "Write to this file instead of stdout",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only messages sent after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "Only messages sent before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h)",
+			},
+			&cli.BoolFlag{
+				Name:  "download-attachments",
+				Usage: "Save attachments in a directory next to --file and link the transcript to them",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			cliCtx, err := utils.NewCLIContext(c)
+			if err != nil {
+				return err
+			}
+			defer cliCtx.Close()
+
+			if c.NArg() < 1 {
+				return utils.ValidationError("channel ID is required")
+			}
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
+			since, err := utils.ParseTime(c.String("since"))
+			if err != nil {
+				return utils.ValidationErrorf("invalid --since: %w", err)
+			}
+			until, err := utils.ParseTime(c.String("until"))
+			if err != nil {
+				return utils.ValidationErrorf("invalid --until: %w", err)
+			}
+			path := c.String("file")
+			if c.Bool("download-attachments") && path == "" {
+				return utils.ValidationError("--download-attachments requires --file")
+			}
+
+			info, err := exportChannelInfo(cliCtx, channelID)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if path != "" {
+				file, err := os.Create(path)
+				if err != nil {
+					return utils.NewErrorWithCause("failed to create export file", utils.ExitError, err)
+				}
+				defer file.Close()
+				w = file
+			}
+
+			export, err := transcript.NewWriter(w, c.String("format"), info)
+			if err != nil {
+				return utils.ValidationErrorf("%w", err)
+			}
+
+			var files *attachmentDownloader
+			if c.Bool("download-attachments") {
+				files = &attachmentDownloader{dir: strings.TrimSuffix(path, filepath.Ext(path)) + "_files"}
+			}
 
+			for message, err := range discord.IterChannelHistory(cliCtx.Client, channelID, since, until) {
+				if err != nil {
+					return utils.DiscordErrorf("failed to get messages: %w", err)
+				}
+				if files != nil {
+					for _, attachment := range message.Attachments {
+						local, err := files.download(ctx, attachment)
+						if err != nil {
+							return err
+						}
+						export.Files[attachment.ID] = local
+					}
+				}
+				if err := export.WriteMessage(message); err != nil {
+					return err
+				}
+			}
+			if err := export.Close(); err != nil {
+				return err
+			}
+
+			if path != "" {
+				cliCtx.Statusf("Exported %d messages to %s\n", export.Count(), path)
+				if files != nil && files.count > 0 {
+					cliCtx.Statusf("Saved %d attachments to %s\n", files.count, files.dir)
+				}
+			}
 			return nil
 		},
 	}
+}
+
+// exportChannelInfo looks up the names shown in a transcript: the channel,
+// its guild, and the roles and channels mentions refer to
+func exportChannelInfo(cliCtx *utils.CLIContext, channelID string) (transcript.Channel, error) {
+	channel, err := cliCtx.Client.GetChannel(channelID)
+	if err != nil {
+		return transcript.Channel{}, utils.DiscordErrorf("failed to get channel: %w", err)
+	}
+	info := transcript.Channel{
+		ID:       channel.ID,
+		Name:     channel.Name,
+		Roles:    map[string]string{},
+		Channels: map[string]string{},
+	}
+	if channel.GuildID == "" {
+		return info, nil
+	}
+
+	guild, err := cliCtx.Client.GuildDescribe(channel.GuildID)
+	if err != nil {
+		return info, utils.DiscordErrorf("failed to get guild: %w", err)
+	}
+	info.Guild = guild.Name
+	roles, err := cliCtx.Client.GetGuildRoles(channel.GuildID)
+	if err != nil {
+		return info, utils.DiscordErrorf("failed to get roles: %w", err)
+	}
+	for _, role := range roles {
+		info.Roles[role.ID] = role.Name
+	}
+	channels, err := cliCtx.Client.GetGuildChannels(channel.GuildID)
+	if err != nil {
+		return info, utils.DiscordErrorf("failed to get channels: %w", err)
+	}
+	for _, ch := range channels {
+		info.Channels[ch.ID] = ch.Name
+	}
+	return info, nil
+}
+
+// attachmentDownloader saves message attachments into a directory
+type attachmentDownloader struct {
+	dir   string
+	count int
+}
+
+// download saves an attachment as <id>_<filename> and returns its path
+// relative to the directory holding dir, for links from the transcript
+func (d *attachmentDownloader) download(ctx context.Context, attachment *discordgo.MessageAttachment) (string, error) {
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return "", utils.NewErrorWithCause("failed to create attachments directory", utils.ExitError, err)
+	}
+	name := attachment.ID + "_" + filepath.Base(attachment.Filename)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
+	if err != nil {
+		return "", utils.NewErrorWithCause("failed to download attachment "+attachment.Filename, utils.ExitError, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", utils.NewErrorWithCause("failed to download attachment "+attachment.Filename, utils.ExitError, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", utils.NewError(fmt.Sprintf("failed to download attachment %s: %s", attachment.Filename, resp.Status), utils.ExitError)
+	}
+
+	file, err := os.Create(filepath.Join(d.dir, name))
+	if err != nil {
+		return "", utils.NewErrorWithCause("failed to save attachment "+attachment.Filename, utils.ExitError, err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return "", utils.NewErrorWithCause("failed to save attachment "+attachment.Filename, utils.ExitError, err)
+	}
+	d.count++
+	return filepath.ToSlash(filepath.Join(filepath.Base(d.dir), name)), nil
 }
\ No newline at end of file
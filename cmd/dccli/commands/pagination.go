@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"iter"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+)
+
+// paginationFlags are shared by list commands that can walk every page
+func paginationFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "Fetch every page instead of a single one (--limit is ignored)",
+		},
+		&cli.IntFlag{
+			Name:  "max",
+			Usage: "Stop after this many results (implies --all)",
+		},
+	}
+}
+
+// snowflakeBounds reads --before and --after, which take an ID or a time
+// (RFC3339, YYYY-MM-DD or a duration before now), as snowflake cursors
+func snowflakeBounds(c *cli.Command) (before, after string, err error) {
+	if before, err = utils.SnowflakeBefore(c.String("before")); err != nil {
+		return "", "", utils.ValidationErrorf("invalid --before: %w", err)
+	}
+	if after, err = utils.SnowflakeAfter(c.String("after")); err != nil {
+		return "", "", utils.ValidationErrorf("invalid --after: %w", err)
+	}
+	return before, after, nil
+}
+
+// paginateAll reports whether --all or --max was given
+func paginateAll(c *cli.Command) bool {
+	return c.Bool("all") || c.Int("max") > 0
+}
+
+// streamPages prints items from a paginating iterator as pages arrive and
+// stops after --max items. Results printed before a failed page are kept.
+func streamPages[T any](c *cli.Command, output *dprint.OutputManager, items iter.Seq2[T, error]) error {
+	stream := output.NewStream()
+	max := int(c.Int("max"))
+	for item, err := range items {
+		if err != nil {
+			if stream.Count() > 0 {
+				stream.Close()
+			}
+			return err
+		}
+		if err := stream.Add(item); err != nil {
+			return err
+		}
+		if max > 0 && stream.Count() >= max {
+			break
+		}
+	}
+	return stream.Close()
+}
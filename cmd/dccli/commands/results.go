@@ -0,0 +1,77 @@
+package commands
+
+import "time"
+
+// The types below are the data of JSON and YAML output for commands that act
+// on an object instead of returning one. Commands that return a Discord
+// object print it as is. Field names are part of the output schema (see
+// schema.go), so renaming one means a new dprint.APIVersion.
+
+// deleted is the result of deleting or leaving a single object
+type deleted struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	GuildID   string `json:"guild_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+}
+
+// bulkDeleted is the result of deleting several objects at once
+type bulkDeleted struct {
+	Deleted   int      `json:"deleted"`
+	IDs       []string `json:"ids"`
+	GuildID   string   `json:"guild_id,omitempty"`
+	ChannelID string   `json:"channel_id,omitempty"`
+}
+
+// memberAction is the result of commands acting on a guild member
+type memberAction struct {
+	GuildID  string     `json:"guild_id"`
+	UserID   string     `json:"user_id"`
+	RoleID   string     `json:"role_id,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+	Nickname string     `json:"nickname,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+}
+
+// reactionAction is the result of adding or removing a reaction
+type reactionAction struct {
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
+	UserID    string `json:"user_id,omitempty"`
+}
+
+// voiceConnection is the result of voice commands. File is the played
+// audio file, or the name prefix of the recorded ones.
+type voiceConnection struct {
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id,omitempty"`
+	File      string `json:"file,omitempty"`
+}
+
+// botResult is the result of commands that change a configured bot.
+// RemovedContexts lists the contexts removed along with a removed bot.
+type botResult struct {
+	Bot             string   `json:"bot"`
+	NewName         string   `json:"new_name,omitempty"`
+	RemovedContexts []string `json:"removed_contexts,omitempty"`
+}
+
+// contextResult is the result of commands that change a context. Context is
+// empty when no context is in use any more.
+type contextResult struct {
+	Context string `json:"context"`
+	Created bool   `json:"created,omitempty"`
+}
+
+// vaultResult is the result of rekeying the vault
+type vaultResult struct {
+	Tokens int `json:"tokens"`
+}
+
+// configValidation is the result of config validate
+type configValidation struct {
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
@@ -56,27 +56,8 @@ func RolesListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, role := range roles {
-					color := fmt.Sprintf("#%06X", role.Color)
-					hoist := "No"
-					if role.Hoist {
-						hoist = "Yes"
-					}
-					data = append(data, []string{
-						role.ID,
-						role.Name,
-						color,
-						hoist,
-					})
-				}
-				header := []string{"ID", "Name", "Color", "Hoisted"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(roles); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(roles, newRoleRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -106,8 +87,11 @@ func RolesGetCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("role ID is required")
 			}
-			roleID := c.Args().First()
 			guildID := c.String("guild")
+			roleID, err := cliCtx.Resolver.Role(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			role, err := cliCtx.Client.GetGuildRole(guildID, roleID)
 			if err != nil {
@@ -210,11 +194,7 @@ func RolesCreateCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", role.Name)
 				fmt.Printf("Color: #%06X\n", role.Color)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"role":    role,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(role); err != nil {
 					return err
 				}
 			}
@@ -267,8 +247,11 @@ func RolesEditCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("role ID is required")
 			}
-			roleID := c.Args().First()
 			guildID := c.String("guild")
+			roleID, err := cliCtx.Resolver.Role(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			data := &discordgo.RoleParams{}
 
@@ -304,11 +287,7 @@ func RolesEditCommand() *cli.Command {
 				fmt.Printf("ID: %s\n", role.ID)
 				fmt.Printf("Name: %s\n", role.Name)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"role":    role,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(role); err != nil {
 					return err
 				}
 			}
@@ -345,8 +324,11 @@ func RolesDeleteCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("role ID is required")
 			}
-			roleID := c.Args().First()
 			guildID := c.String("guild")
+			roleID, err := cliCtx.Resolver.Role(guildID, c.Args().First())
+			if err != nil {
+				return err
+			}
 
 			// Get role info for confirmation
 			role, err := cliCtx.Client.GetGuildRole(guildID, roleID)
@@ -355,13 +337,13 @@ func RolesDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete role: %s (ID: %s)\n", role.Name, roleID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete role: %s (ID: %s)\n", role.Name, roleID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -375,10 +357,10 @@ func RolesDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted role: %s\n", role.Name)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"role_id": roleID,
-					"name":    role.Name,
+				result := deleted{
+					ID:      roleID,
+					Name:    role.Name,
+					GuildID: guildID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -390,19 +372,19 @@ func RolesDeleteCommand() *cli.Command {
 	}
 }
 
-// RolesAssignCommand assigns a role to a user
+// RolesAssignCommand assigns a role to one or more users
 func RolesAssignCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "assign",
-		Usage:     "Assign a role to a user",
-		ArgsUsage: "[user-id] [role-id]",
-		Flags: []cli.Flag{
+		Usage:     "Assign a role to users",
+		ArgsUsage: "[user-id...] [role-id]",
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "guild",
 				Usage:    "Guild ID",
 				Required: true,
 			},
-		},
+		}, bulkFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -410,50 +392,60 @@ func RolesAssignCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			if c.NArg() < 2 {
+			args := c.Args().Slice()
+			if len(args) < 1 {
 				return utils.ValidationError("user ID and role ID are required")
 			}
-			userID := c.Args().Get(0)
-			roleID := c.Args().Get(1)
 			guildID := c.String("guild")
-
-			if err := cliCtx.Client.GuildMemberRoleAdd(guildID, userID, roleID); err != nil {
-				return utils.DiscordErrorf("failed to assign role: %w", err)
+			roleID, err := cliCtx.Resolver.Role(guildID, args[len(args)-1])
+			if err != nil {
+				return err
 			}
-
-			output := cliCtx.GetOutputManager()
-
-			if output.GetFormat() == dprint.FormatTable {
-				fmt.Printf("Successfully assigned role %s to user %s\n", roleID, userID)
-			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
-					"role_id": roleID,
-				}
-				if err := output.Print(result); err != nil {
-					return err
-				}
+			userIDs, err := bulkTargets(c, args[:len(args)-1])
+			if err != nil {
+				return err
+			}
+			if len(userIDs) == 0 {
+				return utils.ValidationError("user ID and role ID are required")
+			}
+			userIDs, err = cliCtx.Resolver.Users(guildID, userIDs)
+			if err != nil {
+				return err
 			}
 
-			return nil
+			return runBulk(ctx, c, cliCtx, userIDs, bulkOp{
+				Name:  "roles assign " + guildID + " " + roleID,
+				Label: "Assigning role",
+				Do: func(ctx context.Context, batch []string) error {
+					if err := cliCtx.Client.GuildMemberRoleAdd(guildID, batch[0], roleID); err != nil {
+						return utils.DiscordErrorf("failed to assign role: %w", err)
+					}
+					return nil
+				},
+				Summary: func(succeeded, total int) string {
+					if total == 1 && succeeded == 1 {
+						return fmt.Sprintf("Successfully assigned role %s to user %s", roleID, userIDs[0])
+					}
+					return fmt.Sprintf("Assigned role %s to %d of %d users", roleID, succeeded, total)
+				},
+			})
 		},
 	}
 }
 
-// RolesRemoveCommand removes a role from a user
+// RolesRemoveCommand removes a role from one or more users
 func RolesRemoveCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "remove",
-		Usage:     "Remove a role from a user",
-		ArgsUsage: "[user-id] [role-id]",
-		Flags: []cli.Flag{
+		Usage:     "Remove a role from users",
+		ArgsUsage: "[user-id...] [role-id]",
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "guild",
 				Usage:    "Guild ID",
 				Required: true,
 			},
-		},
+		}, bulkFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -461,33 +453,43 @@ func RolesRemoveCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			if c.NArg() < 2 {
+			args := c.Args().Slice()
+			if len(args) < 1 {
 				return utils.ValidationError("user ID and role ID are required")
 			}
-			userID := c.Args().Get(0)
-			roleID := c.Args().Get(1)
 			guildID := c.String("guild")
-
-			if err := cliCtx.Client.GuildMemberRoleRemove(guildID, userID, roleID); err != nil {
-				return utils.DiscordErrorf("failed to remove role: %w", err)
+			roleID, err := cliCtx.Resolver.Role(guildID, args[len(args)-1])
+			if err != nil {
+				return err
 			}
-
-			output := cliCtx.GetOutputManager()
-
-			if output.GetFormat() == dprint.FormatTable {
-				fmt.Printf("Successfully removed role %s from user %s\n", roleID, userID)
-			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"user_id": userID,
-					"role_id": roleID,
-				}
-				if err := output.Print(result); err != nil {
-					return err
-				}
+			userIDs, err := bulkTargets(c, args[:len(args)-1])
+			if err != nil {
+				return err
+			}
+			if len(userIDs) == 0 {
+				return utils.ValidationError("user ID and role ID are required")
+			}
+			userIDs, err = cliCtx.Resolver.Users(guildID, userIDs)
+			if err != nil {
+				return err
 			}
 
-			return nil
+			return runBulk(ctx, c, cliCtx, userIDs, bulkOp{
+				Name:  "roles remove " + guildID + " " + roleID,
+				Label: "Removing role",
+				Do: func(ctx context.Context, batch []string) error {
+					if err := cliCtx.Client.GuildMemberRoleRemove(guildID, batch[0], roleID); err != nil {
+						return utils.DiscordErrorf("failed to remove role: %w", err)
+					}
+					return nil
+				},
+				Summary: func(succeeded, total int) string {
+					if total == 1 && succeeded == 1 {
+						return fmt.Sprintf("Successfully removed role %s from user %s", roleID, userIDs[0])
+					}
+					return fmt.Sprintf("Removed role %s from %d of %d users", roleID, succeeded, total)
+				},
+			})
 		},
 	}
 }
\ No newline at end of file
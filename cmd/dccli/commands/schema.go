@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/archive"
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// outputTypes maps each command to a value of the type in the data field of
+// its JSON and YAML output. Commands without data map to nil. The guilds
+// channels, roles, members and invites subcommands share the entries of the
+// top level commands.
+var outputTypes = map[string]interface{}{
+	"applications commands list":            []discord.DiscordCommand(nil),
+	"applications commands create":          (*discordgo.ApplicationCommand)(nil),
+	"applications commands edit":            (*discordgo.ApplicationCommand)(nil),
+	"applications commands remove":          deleted{},
+	"applications commands delete-all":      bulkDeleted{},
+	"applications commands describe":        (*discord.DiscordCommandDescription)(nil),
+	"applications commands bulk-overwrite":  []*discordgo.ApplicationCommand(nil),
+	"applications commands permissions get": (*discordgo.GuildApplicationCommandPermissions)(nil),
+	"applications commands permissions set": (*discordgo.GuildApplicationCommandPermissions)(nil),
+
+	"guilds list":     []discord.DiscordGuild(nil),
+	"guilds describe": (*discord.DiscordGuildDescription)(nil),
+	"guilds leave":    deleted{},
+	"guilds edit":     (*discordgo.Guild)(nil),
+
+	"channels list":                 []*discordgo.Channel(nil),
+	"channels get":                  (*discordgo.Channel)(nil),
+	"channels create":               (*discordgo.Channel)(nil),
+	"channels edit":                 (*discordgo.Channel)(nil),
+	"channels delete":               deleted{},
+	"channels messages list":        []*discordgo.Message(nil),
+	"channels messages get":         (*discordgo.Message)(nil),
+	"channels messages send":        (*discordgo.Message)(nil),
+	"channels messages edit":        (*discordgo.Message)(nil),
+	"channels messages delete":      deleted{},
+	"channels messages bulk-delete": (*discord.BulkReport)(nil),
+	"channels webhooks list":        []*discordgo.Webhook(nil),
+	"channels webhooks get":         (*discordgo.Webhook)(nil),
+	"channels webhooks create":      (*discordgo.Webhook)(nil),
+	"channels webhooks edit":        (*discordgo.Webhook)(nil),
+	"channels webhooks delete":      deleted{},
+	"messages get":                  (*discordgo.Message)(nil),
+	"messages send":                 (*discordgo.Message)(nil),
+	"messages edit":                 (*discordgo.Message)(nil),
+	"messages delete":               deleted{},
+	"messages purge":                (*discord.BulkReport)(nil),
+	"messages listen":               ListenMessageOutput{},
+	"messages reactions list":       []*discordgo.MessageReactions(nil),
+	"messages reactions add":        reactionAction{},
+	"messages reactions remove":     reactionAction{},
+	"messages validate-embed":       discordgo.MessageEmbed{},
+	"messages export":               (*discordgo.Message)(nil),
+	"roles list":                    []*discordgo.Role(nil),
+	"roles get":                     (*discordgo.Role)(nil),
+	"roles create":                  (*discordgo.Role)(nil),
+	"roles edit":                    (*discordgo.Role)(nil),
+	"roles delete":                  deleted{},
+	"roles assign":                  (*discord.BulkReport)(nil),
+	"roles remove":                  (*discord.BulkReport)(nil),
+	"members list":                  []*discordgo.Member(nil),
+	"members get":                   (*discordgo.Member)(nil),
+	"members ban":                   (*discord.BulkReport)(nil),
+	"members unban":                 memberAction{},
+	"members bans":                  []*discordgo.GuildBan(nil),
+	"members kick":                  memberAction{},
+	"members timeout":               memberAction{},
+	"members untimeout":             memberAction{},
+	"members nick":                  memberAction{},
+	"members add-role":              memberAction{},
+	"members remove-role":           memberAction{},
+	"webhooks list":                 []*discordgo.Webhook(nil),
+	"webhooks get":                  (*discordgo.Webhook)(nil),
+	"webhooks create":               (*discordgo.Webhook)(nil),
+	"webhooks edit":                 (*discordgo.Webhook)(nil),
+	"webhooks delete":               deleted{},
+	"webhooks execute":              (*discordgo.Message)(nil),
+	"users get":                     (*discordgo.User)(nil),
+	"users guilds":                  []discord.DiscordGuild(nil),
+	"users connections":             []*discordgo.UserConnection(nil),
+	"emoji list":                    []*discordgo.Emoji(nil),
+	"emoji get":                     (*discordgo.Emoji)(nil),
+	"emoji create":                  (*discordgo.Emoji)(nil),
+	"emoji upload":                  (*discord.BulkReport)(nil),
+	"emoji edit":                    (*discordgo.Emoji)(nil),
+	"emoji delete":                  deleted{},
+	"stickers list":                 []*discordgo.Sticker(nil),
+	"stickers get":                  (*discordgo.Sticker)(nil),
+	"stickers create":               (*discordgo.Sticker)(nil),
+	"stickers edit":                 (*discordgo.Sticker)(nil),
+	"stickers delete":               deleted{},
+	"events list":                   []*discordgo.GuildScheduledEvent(nil),
+	"events get":                    (*discordgo.GuildScheduledEvent)(nil),
+	"events create":                 (*discordgo.GuildScheduledEvent)(nil),
+	"events edit":                   (*discordgo.GuildScheduledEvent)(nil),
+	"events delete":                 deleted{},
+	"events users":                  []*discordgo.GuildScheduledEventUser(nil),
+	"events start":                  (*discordgo.GuildScheduledEvent)(nil),
+	"events end":                    (*discordgo.GuildScheduledEvent)(nil),
+	"automod list":                  []*discordgo.AutoModerationRule(nil),
+	"automod get":                   (*discordgo.AutoModerationRule)(nil),
+	"automod create":                (*discordgo.AutoModerationRule)(nil),
+	"automod edit":                  (*discordgo.AutoModerationRule)(nil),
+	"automod delete":                deleted{},
+	"voice regions":                 []*discordgo.VoiceRegion(nil),
+	"voice join":                    voiceConnection{},
+	"voice leave":                   voiceConnection{},
+	"voice record":                  voiceConnection{},
+	"voice play":                    voiceConnection{},
+	"invites get":                   (*discordgo.Invite)(nil),
+	"invites list":                  []*discordgo.Invite(nil),
+	"invites create":                (*discordgo.Invite)(nil),
+	"invites delete":                deleted{},
+	"audit-log list":                []auditLogEntryOutput(nil),
+	"audit-log export":              []auditLogEntryOutput(nil),
+	"audit-log actions":             []actionInfo(nil),
+	"archive sync":                  []*archive.SyncResult(nil),
+	"archive search":                []archive.SearchResult(nil),
+	"snowflake decode":              []*snowflake.Snowflake(nil),
+	"config bot add":                botResult{},
+	"config bot remove":             botResult{},
+	"config bot set":                botResult{},
+	"config bot list":               []BotInfo(nil),
+	"config bot edit":               botResult{},
+	"config bot verify":             []BotVerification(nil),
+	"config context use":            contextResult{},
+	"config context list":           []ContextInfo(nil),
+	"config context set":            contextResult{},
+	"config vault rekey":            vaultResult{},
+	"config validate":               configValidation{},
+	"completion bash":               nil,
+	"completion zsh":                nil,
+	"completion fish":               nil,
+	"completion powershell":         nil,
+}
+
+// streamedCommands write NDJSON instead of an enveloped document for -o json
+var streamedCommands = map[string]bool{
+	"messages listen": true,
+	"messages export": true,
+}
+
+// outputSchema returns the JSON Schema of a command's JSON output
+func outputSchema(path string) (map[string]interface{}, bool) {
+	name := path
+	if rest, ok := strings.CutPrefix(path, "guilds "); ok {
+		switch strings.Fields(rest)[0] {
+		case "channels", "roles", "members", "invites":
+			name = rest
+		}
+	}
+	data, ok := outputTypes[name]
+	if !ok {
+		return nil, false
+	}
+	if streamedCommands[name] {
+		return dprint.ItemSchema(path, data), true
+	}
+	return dprint.OutputSchema(path, data), true
+}
+
+// ApplyOutputSchema names every leaf command in output envelopes and lets
+// --output-schema print the JSON Schema of its output instead of running it.
+// The hook runs before required flags are checked, so the schema of any
+// command can be printed without its arguments.
+func ApplyOutputSchema(root *cli.Command) {
+	var walk func(cmd *cli.Command, path []string)
+	walk = func(cmd *cli.Command, path []string) {
+		if len(cmd.Commands) == 0 {
+			name := strings.Join(path, " ")
+			before := cmd.Before
+			cmd.Before = func(ctx context.Context, c *cli.Command) (context.Context, error) {
+				dprint.SetCommand(name)
+				if c.Bool("output-schema") {
+					return ctx, printOutputSchema(name)
+				}
+				if before != nil {
+					return before(ctx, c)
+				}
+				return ctx, nil
+			}
+			return
+		}
+		for _, sub := range cmd.Commands {
+			walk(sub, append(path[:len(path):len(path)], sub.Name))
+		}
+	}
+	walk(root, nil)
+}
+
+// printOutputSchema prints the schema of a command and returns
+// utils.ErrOutputSchema to stop it from running
+func printOutputSchema(name string) error {
+	schema, ok := outputSchema(name)
+	if !ok {
+		return utils.NotFoundErrorf("no output schema for command '%s'", name)
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	fmt.Println(string(data))
+	return utils.ErrOutputSchema
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+func SnowflakeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "snowflake",
+		Usage: "Snowflake ID tools",
+		Commands: []*cli.Command{
+			SnowflakeDecodeCommand(),
+		},
+	}
+}
+
+// SnowflakeDecodeCommand shows what a snowflake ID is made of
+func SnowflakeDecodeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "decode",
+		Usage:     "Show when IDs were created and by which worker and process (works offline)",
+		ArgsUsage: "<id>...",
+		Action: func(ctx context.Context, c *cli.Command) error {
+			output := utils.NewOutputManager(c)
+
+			if c.NArg() < 1 {
+				return utils.ValidationError("at least one ID is required")
+			}
+			var snowflakes []*snowflake.Snowflake
+			for _, id := range c.Args().Slice() {
+				decoded, err := snowflake.Decode(id)
+				if err != nil {
+					return utils.ValidationErrorf("%w", err)
+				}
+				snowflakes = append(snowflakes, decoded)
+			}
+
+			return output.Print(snowflakes)
+		},
+	}
+}
@@ -57,26 +57,8 @@ func StickersListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, sticker := range stickers {
-					tags := sticker.Tags
-					if tags == "" {
-						tags = "-"
-					}
-					data = append(data, []string{
-						sticker.ID,
-						sticker.Name,
-						stickerTypeToString(sticker.Type),
-						tags,
-					})
-				}
-				header := []string{"ID", "Name", "Type", "Tags"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(stickers); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(stickers, newStickerRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -208,11 +190,7 @@ func StickersCreateCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", sticker.Name)
 				fmt.Printf("Tags: %s\n", sticker.Tags)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"sticker": sticker,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(sticker); err != nil {
 					return err
 				}
 			}
@@ -295,11 +273,7 @@ func StickersEditCommand() *cli.Command {
 				fmt.Printf("Name: %s\n", sticker.Name)
 				fmt.Printf("Tags: %s\n", sticker.Tags)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"sticker": sticker,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(sticker); err != nil {
 					return err
 				}
 			}
@@ -346,13 +320,13 @@ func StickersDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete sticker: %s (ID: %s)\n", sticker.Name, stickerID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete sticker: %s (ID: %s)\n", sticker.Name, stickerID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -366,10 +340,10 @@ func StickersDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted sticker: %s\n", sticker.Name)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"sticker_id": stickerID,
-					"name":       sticker.Name,
+				result := deleted{
+					ID:      stickerID,
+					Name:    sticker.Name,
+					GuildID: guildID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
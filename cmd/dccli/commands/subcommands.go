@@ -55,6 +55,8 @@ func ConfigCommands() *cli.Command {
 		Description: "Manage bot configurations and settings",
 		Commands: []*cli.Command{
 			BotCommand(),
+			ContextCommand(),
+			VaultCommand(),
 			ConfigValidateCommand(),
 		},
 	}
@@ -108,3 +110,15 @@ func VoiceRegionsRootCommand() *cli.Command {
 func InvitesRootCommand() *cli.Command {
 	return InvitesCommand()
 }
+
+func AuditLogRootCommand() *cli.Command {
+	return AuditLogCommand()
+}
+
+func ArchiveRootCommand() *cli.Command {
+	return ArchiveCommand()
+}
+
+func SnowflakeRootCommand() *cli.Command {
+	return SnowflakeCommand()
+}
@@ -0,0 +1,292 @@
+package commands
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// The types below add table columns to Discord objects that list commands
+// print. Each embeds the object, so JSON and YAML output is the object
+// itself, and carries the columns as fields hidden from both.
+
+// rowsOf converts a list to its table rows
+func rowsOf[T, R any](items []T, row func(T) R) []R {
+	rows := make([]R, len(items))
+	for i, item := range items {
+		rows[i] = row(item)
+	}
+	return rows
+}
+
+// tableRows maps a paginating iterator to the table rows of its items
+func tableRows[T, R any](items iter.Seq2[T, error], row func(T) R) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		for item, err := range items {
+			var r R
+			if err == nil {
+				r = row(item)
+			}
+			if !yield(r, err) {
+				return
+			}
+		}
+	}
+}
+
+// displayName is a username with its discriminator, for accounts that still
+// have one
+func displayName(user *discordgo.User) string {
+	if user.Discriminator == "" || user.Discriminator == "0" {
+		return user.Username
+	}
+	return user.Username + "#" + user.Discriminator
+}
+
+type channelRow struct {
+	*discordgo.Channel `yaml:",inline"`
+	ID                 string `json:"-" yaml:"-" table:"ID"`
+	Name               string `json:"-" yaml:"-" table:"Name"`
+	Type               string `json:"-" yaml:"-" table:"Type"`
+}
+
+func newChannelRow(ch *discordgo.Channel) channelRow {
+	return channelRow{Channel: ch, ID: ch.ID, Name: ch.Name, Type: channelTypeString(ch.Type)}
+}
+
+type messageRow struct {
+	*discordgo.Message `yaml:",inline"`
+	ID                 string    `json:"-" yaml:"-" table:"ID"`
+	Author             string    `json:"-" yaml:"-" table:"Author"`
+	Content            string    `json:"-" yaml:"-" table:"Content"`
+	Time               time.Time `json:"-" yaml:"-" table:"Time"`
+}
+
+func newMessageRow(m *discordgo.Message) messageRow {
+	content := m.Content
+	if len(content) > 50 {
+		content = content[:47] + "..."
+	}
+	if content == "" {
+		content = "[embed/attachment]"
+	}
+	var author string
+	if m.Author != nil {
+		author = m.Author.Username
+	}
+	return messageRow{Message: m, ID: m.ID, Author: author, Content: content, Time: m.Timestamp}
+}
+
+type reactionRow struct {
+	*discordgo.MessageReactions `yaml:",inline"`
+	Emoji                       string `json:"-" yaml:"-" table:"Emoji"`
+	EmojiID                     string `json:"-" yaml:"-" table:"Emoji ID"`
+	Count                       int    `json:"-" yaml:"-" table:"Count"`
+}
+
+func newReactionRow(r *discordgo.MessageReactions) reactionRow {
+	row := reactionRow{MessageReactions: r, Count: r.Count}
+	if r.Emoji != nil {
+		row.Emoji, row.EmojiID = r.Emoji.Name, r.Emoji.ID
+	}
+	return row
+}
+
+type webhookRow struct {
+	*discordgo.Webhook `yaml:",inline"`
+	ID                 string `json:"-" yaml:"-" table:"ID"`
+	Name               string `json:"-" yaml:"-" table:"Name"`
+	ChannelID          string `json:"-" yaml:"-" table:"Channel ID"`
+	Token              string `json:"-" yaml:"-" table:"Token"`
+	Avatar             bool   `json:"-" yaml:"-" table:"Avatar"`
+}
+
+func newWebhookRow(wh *discordgo.Webhook) webhookRow {
+	return webhookRow{Webhook: wh, ID: wh.ID, Name: wh.Name, ChannelID: wh.ChannelID, Token: wh.Token, Avatar: wh.Avatar != ""}
+}
+
+type roleRow struct {
+	*discordgo.Role `yaml:",inline"`
+	ID              string `json:"-" yaml:"-" table:"ID"`
+	Name            string `json:"-" yaml:"-" table:"Name"`
+	Color           string `json:"-" yaml:"-" table:"Color"`
+	Hoisted         bool   `json:"-" yaml:"-" table:"Hoisted"`
+}
+
+func newRoleRow(role *discordgo.Role) roleRow {
+	return roleRow{Role: role, ID: role.ID, Name: role.Name, Color: fmt.Sprintf("#%06X", role.Color), Hoisted: role.Hoist}
+}
+
+type memberRow struct {
+	*discordgo.Member `yaml:",inline"`
+	ID                string    `json:"-" yaml:"-" table:"ID"`
+	Username          string    `json:"-" yaml:"-" table:"Username"`
+	Nickname          string    `json:"-" yaml:"-" table:"Nickname"`
+	Joined            time.Time `json:"-" yaml:"-" table:"Joined"`
+}
+
+func newMemberRow(m *discordgo.Member) memberRow {
+	row := memberRow{Member: m, Nickname: m.Nick, Joined: m.JoinedAt}
+	if m.User != nil {
+		row.ID, row.Username = m.User.ID, displayName(m.User)
+	}
+	return row
+}
+
+type banRow struct {
+	*discordgo.GuildBan `yaml:",inline"`
+	UserID              string `json:"-" yaml:"-" table:"User ID"`
+	Username            string `json:"-" yaml:"-" table:"Username"`
+	Reason              string `json:"-" yaml:"-" table:"Reason"`
+}
+
+func newBanRow(b *discordgo.GuildBan) banRow {
+	row := banRow{GuildBan: b, Reason: b.Reason}
+	if b.User != nil {
+		row.UserID, row.Username = b.User.ID, displayName(b.User)
+	}
+	return row
+}
+
+type emojiRow struct {
+	*discordgo.Emoji `yaml:",inline"`
+	ID               string `json:"-" yaml:"-" table:"ID"`
+	Name             string `json:"-" yaml:"-" table:"Name"`
+	Animated         bool   `json:"-" yaml:"-" table:"Animated"`
+	Managed          bool   `json:"-" yaml:"-" table:"Managed"`
+}
+
+func newEmojiRow(emoji *discordgo.Emoji) emojiRow {
+	return emojiRow{Emoji: emoji, ID: emoji.ID, Name: emoji.Name, Animated: emoji.Animated, Managed: emoji.Managed}
+}
+
+type stickerRow struct {
+	*discordgo.Sticker `yaml:",inline"`
+	ID                 string `json:"-" yaml:"-" table:"ID"`
+	Name               string `json:"-" yaml:"-" table:"Name"`
+	Type               string `json:"-" yaml:"-" table:"Type"`
+	Tags               string `json:"-" yaml:"-" table:"Tags"`
+}
+
+func newStickerRow(sticker *discordgo.Sticker) stickerRow {
+	return stickerRow{Sticker: sticker, ID: sticker.ID, Name: sticker.Name, Type: stickerTypeToString(sticker.Type), Tags: sticker.Tags}
+}
+
+type eventRow struct {
+	*discordgo.GuildScheduledEvent `yaml:",inline"`
+	ID                             string    `json:"-" yaml:"-" table:"ID"`
+	Name                           string    `json:"-" yaml:"-" table:"Name"`
+	Status                         string    `json:"-" yaml:"-" table:"Status"`
+	StartTime                      time.Time `json:"-" yaml:"-" table:"Start Time"`
+}
+
+func newEventRow(event *discordgo.GuildScheduledEvent) eventRow {
+	return eventRow{
+		GuildScheduledEvent: event,
+		ID:                  event.ID,
+		Name:                event.Name,
+		Status:              eventStatusToString(event.Status),
+		StartTime:           event.ScheduledStartTime,
+	}
+}
+
+type eventUserRow struct {
+	*discordgo.GuildScheduledEventUser `yaml:",inline"`
+	ID                                 string `json:"-" yaml:"-" table:"ID"`
+	Username                           string `json:"-" yaml:"-" table:"Username"`
+}
+
+func newEventUserRow(u *discordgo.GuildScheduledEventUser) eventUserRow {
+	row := eventUserRow{GuildScheduledEventUser: u}
+	if u.User != nil {
+		row.ID, row.Username = u.User.ID, displayName(u.User)
+	}
+	return row
+}
+
+type inviteRow struct {
+	*discordgo.Invite `yaml:",inline"`
+	Code              string `json:"-" yaml:"-" table:"Code"`
+	Inviter           string `json:"-" yaml:"-" table:"Inviter"`
+	Uses              int    `json:"-" yaml:"-" table:"Uses"`
+	MaxUses           string `json:"-" yaml:"-" table:"Max Uses"`
+	Expires           string `json:"-" yaml:"-" table:"Expires"`
+}
+
+func newInviteRow(invite *discordgo.Invite) inviteRow {
+	row := inviteRow{Invite: invite, Code: invite.Code, Uses: invite.Uses, MaxUses: "∞", Expires: "Never"}
+	if invite.Inviter != nil {
+		row.Inviter = invite.Inviter.Username
+	}
+	if invite.MaxUses > 0 {
+		row.MaxUses = strconv.Itoa(invite.MaxUses)
+	}
+	if invite.MaxAge > 0 {
+		row.Expires = fmt.Sprintf("%ds", invite.MaxAge)
+	}
+	return row
+}
+
+type autoModRuleRow struct {
+	*discordgo.AutoModerationRule `yaml:",inline"`
+	ID                            string `json:"-" yaml:"-" table:"ID"`
+	Name                          string `json:"-" yaml:"-" table:"Name"`
+	Type                          string `json:"-" yaml:"-" table:"Type"`
+	Enabled                       bool   `json:"-" yaml:"-" table:"Enabled"`
+	Actions                       int    `json:"-" yaml:"-" table:"Actions"`
+}
+
+func newAutoModRuleRow(rule *discordgo.AutoModerationRule) autoModRuleRow {
+	return autoModRuleRow{
+		AutoModerationRule: rule,
+		ID:                 rule.ID,
+		Name:               rule.Name,
+		Type:               autoModRuleTypeToString(rule.EventType),
+		Enabled:            rule.Enabled != nil && *rule.Enabled,
+		Actions:            len(rule.Actions),
+	}
+}
+
+type commandPermissionRow struct {
+	*discordgo.ApplicationCommandPermissions `yaml:",inline"`
+	ID                                       string `json:"-" yaml:"-" table:"ID"`
+	Type                                     string `json:"-" yaml:"-" table:"Type"`
+	Permission                               bool   `json:"-" yaml:"-" table:"Permission"`
+}
+
+func newCommandPermissionRow(p *discordgo.ApplicationCommandPermissions) commandPermissionRow {
+	row := commandPermissionRow{ApplicationCommandPermissions: p, ID: p.ID, Type: "Unknown", Permission: p.Permission}
+	switch p.Type {
+	case discordgo.ApplicationCommandPermissionTypeRole:
+		row.Type = "Role"
+	case discordgo.ApplicationCommandPermissionTypeUser:
+		row.Type = "User"
+	case discordgo.ApplicationCommandPermissionTypeChannel:
+		row.Type = "Channel"
+	}
+	return row
+}
+
+type connectionRow struct {
+	*discordgo.UserConnection `yaml:",inline"`
+	ID                        string `json:"-" yaml:"-" table:"ID"`
+	Name                      string `json:"-" yaml:"-" table:"Name"`
+	Type                      string `json:"-" yaml:"-" table:"Type"`
+}
+
+func newConnectionRow(conn *discordgo.UserConnection) connectionRow {
+	return connectionRow{UserConnection: conn, ID: conn.ID, Name: conn.Name, Type: conn.Type}
+}
+
+type voiceRegionRow struct {
+	*discordgo.VoiceRegion `yaml:",inline"`
+	ID                     string `json:"-" yaml:"-" table:"ID"`
+	Name                   string `json:"-" yaml:"-" table:"Name"`
+}
+
+func newVoiceRegionRow(region *discordgo.VoiceRegion) voiceRegionRow {
+	return voiceRegionRow{VoiceRegion: region, ID: region.ID, Name: region.Name}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/FlameInTheDark/dccli/pkg/discord"
 	"github.com/FlameInTheDark/dccli/pkg/dprint"
 	"github.com/FlameInTheDark/dccli/pkg/utils"
 )
@@ -73,21 +74,21 @@ func UsersGuildsCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "guilds",
 		Usage: "List user guilds",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:  "before",
-				Usage: "Before guild ID",
+				Usage: "Before this guild ID, or guilds created before this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when the bot joined",
 			},
 			&cli.StringFlag{
 				Name:  "after",
-				Usage: "After guild ID",
+				Usage: "After this guild ID, or guilds created after this time (RFC3339, YYYY-MM-DD or a duration like 24h, 7d, 1d12h); not when the bot joined",
 			},
 			&cli.IntFlag{
 				Name:  "limit",
 				Usage: "Limit number of guilds. Max 100. Default is 10",
 				Value: 10,
 			},
-		},
+		}, paginationFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			cliCtx, err := utils.NewCLIContext(c)
 			if err != nil {
@@ -95,32 +96,37 @@ func UsersGuildsCommand() *cli.Command {
 			}
 			defer cliCtx.Close()
 
-			guilds, err := cliCtx.Client.GuildList(int(c.Int("limit")), c.String("before"), c.String("after"))
+			before, after, err := snowflakeBounds(c)
 			if err != nil {
-				return utils.DiscordErrorf("failed to list guilds: %w", err)
+				return err
 			}
 
 			output := cliCtx.GetOutputManager()
 
+			if paginateAll(c) {
+				guilds := discord.IterGuilds(cliCtx.Client, before, after)
+				if err := streamPages(c, output, guilds); err != nil {
+					return utils.DiscordErrorf("failed to list guilds: %w", err)
+				}
+				return nil
+			}
+
+			guilds, err := cliCtx.Client.GuildList(int(c.Int("limit")), before, after)
+			if err != nil {
+				return utils.DiscordErrorf("failed to list guilds: %w", err)
+			}
+
 			if output.GetFormat() == dprint.FormatTable {
 				if c.String("after") != "" {
-					fmt.Println("Results after: ", c.String("after"))
+					cliCtx.Statusf("Results after: %s\n", c.String("after"))
 				}
 				if c.String("before") != "" {
-					fmt.Println("Results before: ", c.String("before"))
-				}
-
-				data := [][]string{}
-				for _, g := range guilds {
-					data = append(data, []string{g.ID, g.Name})
-				}
-				header := []string{"ID", "Name"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(guilds); err != nil {
-					return err
+					cliCtx.Statusf("Results before: %s\n", c.String("before"))
 				}
 			}
+			if err := output.Print(guilds); err != nil {
+				return err
+			}
 
 			return nil
 		},
@@ -145,26 +151,12 @@ func UsersConnectionsCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				if len(connections) == 0 {
-					fmt.Println("No connections found")
-					return nil
-				}
-
-				data := [][]string{}
-				for _, conn := range connections {
-					data = append(data, []string{
-						conn.ID,
-						conn.Name,
-						conn.Type,
-					})
-				}
-				header := []string{"ID", "Name", "Type"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(connections); err != nil {
-					return err
-				}
+			if output.GetFormat() == dprint.FormatTable && len(connections) == 0 {
+				fmt.Println("No connections found")
+				return nil
+			}
+			if err := output.Print(rowsOf(connections, newConnectionRow)); err != nil {
+				return err
 			}
 
 			return nil
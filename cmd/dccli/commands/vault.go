@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/cfg"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+	"github.com/FlameInTheDark/dccli/pkg/utils"
+)
+
+func VaultCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "vault",
+		Usage:       "Encrypted token vault management",
+		Description: "Manage the vault that stores bot tokens added with --encrypt",
+		Commands: []*cli.Command{
+			vaultRekeyCommand(),
+		},
+	}
+}
+
+func vaultRekeyCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "rekey",
+		Usage:       "Change the vault passphrase",
+		Description: "Unlocks the vault with the current passphrase and re-encrypts every token under a new one. The new passphrase is read from --new-passphrase-fd, DCLI_VAULT_NEW_PASSPHRASE or a prompt.",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  utils.NewPassphraseFDFlag,
+				Usage: "Read the new passphrase from this file descriptor",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			// The passphrases are read and the keys derived before the config
+			// is locked
+			config, err := cfg.LoadConfig()
+			if err != nil {
+				return utils.ConfigErrorf("failed to load config: %w", err)
+			}
+			key, err := utils.UnlockVault(c, config)
+			if err != nil {
+				return err
+			}
+			passphrase, err := utils.ReadPassphrase(c, utils.NewPassphraseFDFlag, utils.NewPassphraseEnv, "New vault passphrase: ", true)
+			if err != nil {
+				return err
+			}
+			vault, newKey, err := cfg.NewVault(passphrase)
+			if err != nil {
+				return utils.ConfigErrorf("failed to create vault: %w", err)
+			}
+
+			salt := config.Vault.Salt
+
+			count := 0
+			err = updateConfig(func(config *cfg.Config) error {
+				if config.Vault == nil || config.Vault.Salt != salt {
+					return utils.ConfigError("the vault changed while the command ran, run it again")
+				}
+				if err := config.Rekey(key, vault, newKey); err != nil {
+					return utils.ConfigErrorf("failed to rekey vault: %w", err)
+				}
+
+				for _, bot := range config.Bots {
+					if bot.Encrypted() {
+						count++
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			output := utils.NewOutputManager(c)
+			if output.GetFormat() == dprint.FormatTable {
+				fmt.Printf("Vault rekeyed, %d token(s) re-encrypted\n", count)
+			} else {
+				result := vaultResult{Tokens: count}
+				if err := output.Print(result); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
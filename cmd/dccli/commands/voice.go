@@ -6,7 +6,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/urfave/cli/v3"
 
@@ -39,20 +38,8 @@ func VoiceRegionsCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, region := range regions {
-					data = append(data, []string{
-						region.ID,
-						region.Name,
-					})
-				}
-				header := []string{"ID", "Name"}
-				dprint.Table(header, data)
-			} else {
-				for _, region := range regions {
-					dprint.Print(fmt.Sprintf("%s: %s\n", region.ID, region.Name))
-				}
+			if err := output.Print(rowsOf(regions, newVoiceRegionRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -86,6 +73,15 @@ The bot will stay connected until manually disconnected or the program exits.`,
 			}
 			defer cliCtx.Close()
 
+			guildID, err = cliCtx.Resolver.Guild(guildID)
+			if err != nil {
+				return err
+			}
+			channelID, err = cliCtx.Resolver.Channel(guildID, channelID)
+			if err != nil {
+				return err
+			}
+
 			if guildID == "" {
 				channel, err := cliCtx.Client.GetChannel(channelID)
 				if err != nil {
@@ -94,39 +90,35 @@ The bot will stay connected until manually disconnected or the program exits.`,
 				guildID = channel.GuildID
 			}
 
-			voiceConn, err := cliCtx.Client.Session().ChannelVoiceJoin(guildID, channelID, false, false)
+			conn, err := cliCtx.Client.JoinVoiceChannel(guildID, channelID, false, false)
 			if err != nil {
 				return utils.DiscordErrorf("failed to join voice channel: %w", err)
 			}
 
-			timeout := time.After(30 * time.Second)
-			ticker := time.NewTicker(100 * time.Millisecond)
-			ready := false
+			output := cliCtx.GetOutputManager()
 
-			for !ready {
-				select {
-				case <-timeout:
-					voiceConn.Disconnect()
-					return utils.DiscordErrorf("voice connection timeout")
-				case <-ticker.C:
-					if voiceConn.Ready {
-						ready = true
-					}
+			if output.GetFormat() == dprint.FormatTable {
+				fmt.Printf("Successfully joined voice channel %s\n", channelID)
+			} else {
+				result := voiceConnection{
+					GuildID:   guildID,
+					ChannelID: channelID,
+				}
+				if err := output.Print(result); err != nil {
+					conn.Leave()
+					return err
 				}
 			}
-			ticker.Stop()
-
-			fmt.Printf("Successfully joined voice channel %s\n", channelID)
-			fmt.Println("Press Ctrl+C to disconnect and exit")
+			cliCtx.Statusf("Press Ctrl+C to disconnect and exit\n")
 
 			// Wait for interrupt signal
 			sigChan := make(chan os.Signal, 1)
 			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 			<-sigChan
 
-			fmt.Println("\nDisconnecting...")
-			voiceConn.Disconnect()
-			fmt.Println("Disconnected from voice channel")
+			cliCtx.Statusf("\nDisconnecting...\n")
+			conn.Leave()
+			cliCtx.Statusf("Disconnected from voice channel\n")
 			return nil
 		},
 	}
@@ -153,11 +145,26 @@ If the bot is not connected, this command will return an error.`,
 			}
 			defer cliCtx.Close()
 
+			guildID, err = cliCtx.Resolver.Guild(guildID)
+			if err != nil {
+				return err
+			}
+
 			if err := cliCtx.Client.LeaveVoiceChannel(guildID); err != nil {
 				return utils.DiscordErrorf("failed to leave voice channel: %w", err)
 			}
 
-			fmt.Printf("Disconnected from voice channel in guild %s\n", guildID)
+			output := cliCtx.GetOutputManager()
+
+			if output.GetFormat() == dprint.FormatTable {
+				fmt.Printf("Disconnected from voice channel in guild %s\n", guildID)
+			} else {
+				result := voiceConnection{GuildID: guildID}
+				if err := output.Print(result); err != nil {
+					return err
+				}
+			}
+
 			return nil
 		},
 	}
@@ -202,6 +209,15 @@ Each user will have their own file named: <timestamp>_<SSRC>_<output>.ogg`,
 			}
 			defer cliCtx.Close()
 
+			guildID, err = cliCtx.Resolver.Guild(guildID)
+			if err != nil {
+				return err
+			}
+			channelID, err = cliCtx.Resolver.Channel(guildID, channelID)
+			if err != nil {
+				return err
+			}
+
 			if guildID == "" {
 				channel, err := cliCtx.Client.GetChannel(channelID)
 				if err != nil {
@@ -210,42 +226,33 @@ Each user will have their own file named: <timestamp>_<SSRC>_<output>.ogg`,
 				guildID = channel.GuildID
 			}
 
-			voiceConn, err := cliCtx.Client.Session().ChannelVoiceJoin(guildID, channelID, true, false)
+			conn, err := cliCtx.Client.JoinVoiceChannel(guildID, channelID, true, false)
 			if err != nil {
 				return utils.DiscordErrorf("failed to join voice channel: %w", err)
 			}
 
-			timeout := time.After(30 * time.Second)
-			ticker := time.NewTicker(100 * time.Millisecond)
-			ready := false
-
-			for !ready {
-				select {
-				case <-timeout:
-					voiceConn.Disconnect()
-					return utils.DiscordErrorf("voice connection timeout")
-				case <-ticker.C:
-					if voiceConn.Ready {
-						ready = true
-					}
-				}
-			}
-			ticker.Stop()
-
-			conn := voicepkg.NewConnection(voiceConn)
-
 			recorder, err := voicepkg.NewRecorder(conn)
 			if err != nil {
-				voiceConn.Disconnect()
+				conn.Leave()
 				return utils.DiscordErrorf("failed to create recorder: %w", err)
 			}
 
 			if err := recorder.RecordToFile(outputPath); err != nil {
-				voiceConn.Disconnect()
+				conn.Leave()
 				return utils.DiscordErrorf("recording failed: %w", err)
 			}
 
-			voiceConn.Disconnect()
+			conn.Leave()
+
+			output := cliCtx.GetOutputManager()
+			if output.GetFormat() != dprint.FormatTable {
+				result := voiceConnection{
+					GuildID:   guildID,
+					ChannelID: channelID,
+					File:      outputPath,
+				}
+				return output.Print(result)
+			}
 			return nil
 		},
 	}
@@ -291,6 +298,15 @@ Supports any audio format that ffmpeg can decode.`,
 			}
 			defer cliCtx.Close()
 
+			guildID, err = cliCtx.Resolver.Guild(guildID)
+			if err != nil {
+				return err
+			}
+			channelID, err = cliCtx.Resolver.Channel(guildID, channelID)
+			if err != nil {
+				return err
+			}
+
 			if guildID == "" {
 				channel, err := cliCtx.Client.GetChannel(channelID)
 				if err != nil {
@@ -299,40 +315,30 @@ Supports any audio format that ffmpeg can decode.`,
 				guildID = channel.GuildID
 			}
 
-			voiceConn, err := cliCtx.Client.Session().ChannelVoiceJoin(guildID, channelID, false, false)
+			conn, err := cliCtx.Client.JoinVoiceChannel(guildID, channelID, false, false)
 			if err != nil {
 				return utils.DiscordErrorf("failed to join voice channel: %w", err)
 			}
 
-			timeout := time.After(30 * time.Second)
-			ticker := time.NewTicker(100 * time.Millisecond)
-			ready := false
-
-			for !ready {
-				select {
-				case <-timeout:
-					voiceConn.Disconnect()
-					return utils.DiscordErrorf("voice connection timeout")
-				case <-ticker.C:
-					if voiceConn.Ready {
-						ready = true
-					}
-				}
-			}
-			ticker.Stop()
-
-			conn := voicepkg.NewConnection(voiceConn)
-
 			player := voicepkg.NewPlayer(conn)
 
 			if err := player.PlayFile(filePath); err != nil {
-				voiceConn.Disconnect()
+				conn.Leave()
 				return utils.DiscordErrorf("playback failed: %w", err)
 			}
 
-			fmt.Println("Playback finished, leaving voice channel...")
-			voiceConn.Disconnect()
+			cliCtx.Statusf("Playback finished, leaving voice channel...\n")
+			conn.Leave()
 
+			output := cliCtx.GetOutputManager()
+			if output.GetFormat() != dprint.FormatTable {
+				result := voiceConnection{
+					GuildID:   guildID,
+					ChannelID: channelID,
+					File:      filePath,
+				}
+				return output.Print(result)
+			}
 			return nil
 		},
 	}
@@ -67,26 +67,8 @@ func WebhooksListCommand() *cli.Command {
 
 			output := cliCtx.GetOutputManager()
 
-			if output.GetFormat() == dprint.FormatTable {
-				data := [][]string{}
-				for _, wh := range webhooks {
-					avatar := "No"
-					if wh.Avatar != "" {
-						avatar = "Yes"
-					}
-					data = append(data, []string{
-						wh.ID,
-						wh.Name,
-						wh.ChannelID,
-						avatar,
-					})
-				}
-				header := []string{"ID", "Name", "Channel ID", "Avatar"}
-				dprint.Table(header, data)
-			} else {
-				if err := output.Print(webhooks); err != nil {
-					return err
-				}
+			if err := output.Print(rowsOf(webhooks, newWebhookRow)); err != nil {
+				return err
 			}
 
 			return nil
@@ -167,7 +149,10 @@ func WebhooksCreateCommand() *cli.Command {
 			if c.NArg() < 1 {
 				return utils.ValidationError("channel ID is required")
 			}
-			channelID := c.Args().First()
+			channelID, err := cliCtx.Resolver.Channel("", c.Args().First())
+			if err != nil {
+				return err
+			}
 			name := c.String("name")
 
 			var avatar string
@@ -193,11 +178,7 @@ func WebhooksCreateCommand() *cli.Command {
 				fmt.Printf("Token: %s\n", webhook.Token)
 				fmt.Printf("URL: https://discord.com/api/webhooks/%s/%s\n", webhook.ID, webhook.Token)
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"webhook": webhook,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(webhook); err != nil {
 					return err
 				}
 			}
@@ -276,11 +257,7 @@ func WebhooksEditCommand() *cli.Command {
 					fmt.Printf("Channel ID: %s\n", webhook.ChannelID)
 				}
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-					"webhook": webhook,
-				}
-				if err := output.Print(result); err != nil {
+				if err := output.Print(webhook); err != nil {
 					return err
 				}
 			}
@@ -321,13 +298,13 @@ func WebhooksDeleteCommand() *cli.Command {
 			}
 
 			// Confirmation prompt
-			if !c.Bool("force") {
-				fmt.Printf("You are about to delete webhook: %s (ID: %s)\n", webhook.Name, webhookID)
-				fmt.Print("Are you sure? (yes/no): ")
+			if !c.Bool("force") && !c.Bool("dry-run") {
+				fmt.Fprintf(os.Stderr, "You are about to delete webhook: %s (ID: %s)\n", webhook.Name, webhookID)
+				fmt.Fprint(os.Stderr, "Are you sure? (yes/no): ")
 				reader := bufio.NewReader(os.Stdin)
 				response, _ := reader.ReadString('\n')
 				if response != "yes\n" && response != "yes\r\n" {
-					fmt.Println("Operation cancelled.")
+					fmt.Fprintln(os.Stderr, "Operation cancelled.")
 					return nil
 				}
 			}
@@ -341,10 +318,11 @@ func WebhooksDeleteCommand() *cli.Command {
 			if output.GetFormat() == dprint.FormatTable {
 				fmt.Printf("Successfully deleted webhook: %s\n", webhook.Name)
 			} else {
-				result := map[string]interface{}{
-					"success":    true,
-					"webhook_id": webhookID,
-					"name":       webhook.Name,
+				result := deleted{
+					ID:        webhookID,
+					Name:      webhook.Name,
+					GuildID:   webhook.GuildID,
+					ChannelID: webhook.ChannelID,
 				}
 				if err := output.Print(result); err != nil {
 					return err
@@ -440,13 +418,8 @@ func WebhooksExecuteCommand() *cli.Command {
 					fmt.Printf("Message ID: %s\n", message.ID)
 				}
 			} else {
-				result := map[string]interface{}{
-					"success": true,
-				}
-				if wait && message != nil {
-					result["message_id"] = message.ID
-				}
-				if err := output.Print(result); err != nil {
+				// Without --wait Discord returns no message and data is null
+				if err := output.Print(message); err != nil {
 					return err
 				}
 			}
@@ -43,58 +43,7 @@ func main() {
 		fmt.Print(getVersionInfo())
 	}
 
-	app := &cli.Command{
-		Name:        "dccli",
-		Version:     build,
-		Usage:       "Discord CLI Tool - Manage Discord bots and guilds from the command line",
-		Description: "A powerful CLI for Discord API operations including guild management, messaging, roles, and more.",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:        "output",
-				Aliases:     []string{"o"},
-				Usage:       "Output format (table|json|yaml)",
-				Value:       "table",
-				Sources:     cli.EnvVars("DCLI_OUTPUT"),
-				DefaultText: "table",
-			},
-			&cli.StringFlag{
-				Name:    "bot",
-				Aliases: []string{"b"},
-				Usage:   "Bot name to use (overrides current from config)",
-				Sources: cli.EnvVars("DCLI_BOT"),
-			},
-			&cli.StringFlag{
-				Name:    "token",
-				Aliases: []string{"t"},
-				Usage:   "Bot token (overrides config entirely)",
-				Sources: cli.EnvVars("DCLI_TOKEN"),
-			},
-			&cli.BoolFlag{
-				Name:    "quiet",
-				Aliases: []string{"q"},
-				Usage:   "Suppress status messages",
-			},
-		},
-		Commands: []*cli.Command{
-			commands.ApplicationsCommands(),
-			commands.GuildsCommand(),
-			commands.ChannelsRootCommand(),
-			commands.MessagesRootCommand(),
-			commands.RolesRootCommand(),
-			commands.MembersRootCommand(),
-			commands.WebhooksRootCommand(),
-			commands.UsersRootCommand(),
-			commands.EmojiRootCommand(),
-			commands.StickersRootCommand(),
-			commands.EventsRootCommand(),
-			commands.AutoModRootCommand(),
-			commands.VoiceRootCommand(),
-			commands.InvitesRootCommand(),
-			commands.ConfigCommands(),
-			commands.CompletionCommand(),
-		},
-	}
-
+	app := commands.NewApp(build)
 	err := app.Run(context.Background(), os.Args)
 	utils.HandleError(err)
 }
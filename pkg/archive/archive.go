@@ -0,0 +1,166 @@
+// Package archive keeps an offline copy of channel history in a directory of
+// per-channel JSONL files. Each sync appends only what changed since the last
+// one: new messages, and edits and deletions noticed among recent messages.
+//
+//	<dir>/archive.lock                  held while the archive is open
+//	<dir>/state.json                    sync checkpoint of every channel
+//	<dir>/index.gob                     search index, rebuilt when missing
+//	<dir>/<guild-id>/<channel-id>.jsonl records of one channel, oldest first
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Record types
+const (
+	RecordMessage = "message" // A message seen for the first time
+	RecordEdit    = "edit"    // A new version of an archived message
+	RecordDelete  = "delete"  // An archived message that no longer exists
+)
+
+// stateVersion is the layout version of state.json
+const stateVersion = 1
+
+// Archives hold message history, so only the owner may read them
+const (
+	dirMode  os.FileMode = 0o700
+	fileMode os.FileMode = 0o600
+)
+
+// Record is one line of a channel file
+type Record struct {
+	Type     string             `json:"type"`
+	ID       string             `json:"id"`
+	SyncedAt time.Time          `json:"synced_at"`
+	Message  *discordgo.Message `json:"message,omitempty"` // Absent for deletions
+}
+
+// ChannelState is the sync checkpoint of one channel
+type ChannelState struct {
+	GuildID       string    `json:"guild_id"`
+	Name          string    `json:"name"`
+	LastMessageID string    `json:"last_message_id"`
+	SyncedAt      time.Time `json:"synced_at"`
+	Messages      int       `json:"messages"`
+	// Size is the length of the channel file when the checkpoint was saved.
+	// Records written after it by an interrupted sync are cut off, since
+	// the next sync fetches them again.
+	Size int64 `json:"size"`
+	// Recent maps the IDs of messages inside the recheck window to their
+	// edited timestamp, to notice edits and deletions without reading the
+	// channel file
+	Recent map[string]string `json:"recent,omitempty"`
+}
+
+// state is the content of state.json
+type state struct {
+	Version  int                      `json:"version"`
+	Channels map[string]*ChannelState `json:"channels"`
+}
+
+// Archive is an archive directory
+type Archive struct {
+	Dir    string
+	state  state
+	unlock func()
+}
+
+// Open opens the archive in dir, creating it if needed. It holds the archive
+// lock, waiting for other dccli processes using it, until Close.
+func Open(dir string) (*Archive, error) {
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	unlock, err := lockFile(filepath.Join(dir, "archive.lock"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock archive: %w", err)
+	}
+	a := &Archive{Dir: dir, state: state{Version: stateVersion, Channels: map[string]*ChannelState{}}, unlock: unlock}
+	if err := a.load(); err != nil {
+		unlock()
+		return nil, err
+	}
+	return a, nil
+}
+
+// load reads state.json, if the archive has one
+func (a *Archive) load() error {
+	data, err := os.ReadFile(a.statePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read archive state: %w", err)
+	}
+	if err := json.Unmarshal(data, &a.state); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", a.statePath(), err)
+	}
+	if a.state.Version > stateVersion {
+		return fmt.Errorf("archive %s was written by a newer dccli (state version %d)", a.Dir, a.state.Version)
+	}
+	if a.state.Channels == nil {
+		a.state.Channels = map[string]*ChannelState{}
+	}
+	return nil
+}
+
+// Close releases the archive lock
+func (a *Archive) Close() {
+	if a.unlock != nil {
+		a.unlock()
+		a.unlock = nil
+	}
+}
+
+// Channel returns the checkpoint of a channel, or nil if it was never synced
+func (a *Archive) Channel(channelID string) *ChannelState {
+	return a.state.Channels[channelID]
+}
+
+// ChannelIDs returns the IDs of every synced channel, sorted
+func (a *Archive) ChannelIDs() []string {
+	ids := make([]string, 0, len(a.state.Channels))
+	for id := range a.state.Channels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ChannelPath returns the JSONL file of a channel
+func (a *Archive) ChannelPath(guildID, channelID string) string {
+	if guildID == "" {
+		guildID = "dm"
+	}
+	return filepath.Join(a.Dir, guildID, channelID+".jsonl")
+}
+
+func (a *Archive) statePath() string {
+	return filepath.Join(a.Dir, "state.json")
+}
+
+// save writes state.json through a temporary file so an interrupted save
+// keeps the previous checkpoint
+func (a *Archive) save() error {
+	data, err := json.MarshalIndent(a.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive state: %w", err)
+	}
+	tmp := a.statePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, fileMode); err != nil {
+		return fmt.Errorf("failed to write archive state: %w", err)
+	}
+	if err := os.Rename(tmp, a.statePath()); err != nil {
+		return fmt.Errorf("failed to write archive state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// indexVersion is the layout version of the index file. An index of another
+// version is rebuilt.
+const indexVersion = 1
+
+// indexDoc is what the index keeps of a message to filter on without
+// reading the channel file
+type indexDoc struct {
+	ChannelID   string
+	AuthorID    string
+	Authors     []string // Username and global name, lower case
+	Attachments bool
+	Edited      bool
+	Deleted     bool
+	Offset      int64 // Start of the message's latest record in the channel file
+}
+
+// index is an inverted index of the archived messages. It is saved as one
+// file and brought up to date with the records appended since it was last
+// saved.
+type index struct {
+	Version int
+	Files   map[string]int64     // Bytes of each channel file indexed so far
+	Docs    map[uint64]*indexDoc // Messages by ID
+	Terms   map[string][]uint64  // Message IDs containing each term, ascending
+}
+
+func newIndex() *index {
+	return &index{
+		Version: indexVersion,
+		Files:   map[string]int64{},
+		Docs:    map[uint64]*indexDoc{},
+		Terms:   map[string][]uint64{},
+	}
+}
+
+func (a *Archive) indexPath() string {
+	return filepath.Join(a.Dir, "index.gob")
+}
+
+// loadIndex reads the index and adds the records synced since it was saved.
+// An index that does not match the channel files is rebuilt.
+func (a *Archive) loadIndex() (*index, error) {
+	idx := newIndex()
+	if file, err := os.Open(a.indexPath()); err == nil {
+		err = gob.NewDecoder(file).Decode(idx)
+		file.Close()
+		if err != nil || idx.Version != indexVersion {
+			idx = newIndex()
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	for channelID, indexed := range idx.Files {
+		if st := a.Channel(channelID); st == nil || st.Size < indexed {
+			// The channel file was replaced since
+			idx = newIndex()
+			break
+		}
+	}
+
+	changed := false
+	for _, channelID := range a.ChannelIDs() {
+		st := a.Channel(channelID)
+		if idx.Files[channelID] == st.Size {
+			continue
+		}
+		if err := idx.addFile(a.ChannelPath(st.GuildID, channelID), channelID, idx.Files[channelID], st.Size); err != nil {
+			return nil, err
+		}
+		idx.Files[channelID] = st.Size
+		changed = true
+	}
+	if changed {
+		if err := a.saveIndex(idx); err != nil {
+			return nil, err
+		}
+	}
+	return idx, nil
+}
+
+// saveIndex writes the index through a temporary file
+func (a *Archive) saveIndex(idx *index) error {
+	tmp := a.indexPath() + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fileMode)
+	if err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	if err := gob.NewEncoder(w).Encode(idx); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmp, a.indexPath()); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// addFile indexes the records of a channel file between from and to
+func (idx *index) addFile(path, channelID string, from, to int64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(from, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	r := bufio.NewReader(io.LimitReader(file, to-from))
+	offset := from
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var record Record
+			if err := json.Unmarshal(line, &record); err != nil {
+				return fmt.Errorf("failed to parse %s at byte %d: %w", path, offset, err)
+			}
+			if err := idx.add(file, channelID, offset, &record); err != nil {
+				return err
+			}
+			offset += int64(len(line))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+}
+
+// add indexes one record found at offset in the channel file
+func (idx *index) add(file *os.File, channelID string, offset int64, record *Record) error {
+	id, err := strconv.ParseUint(record.ID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	doc := idx.Docs[id]
+
+	switch record.Type {
+	case RecordDelete:
+		if doc != nil {
+			doc.Deleted = true
+		}
+		return nil
+	case RecordEdit:
+		if doc != nil {
+			// Drop the terms the edit removed
+			previous, err := readRecord(file, doc.Offset)
+			if err != nil {
+				return err
+			}
+			current := terms(record.Message)
+			for _, term := range terms(previous.Message) {
+				if slices.Contains(current, term) {
+					continue
+				}
+				if ids := removeID(idx.Terms[term], id); len(ids) > 0 {
+					idx.Terms[term] = ids
+				} else {
+					delete(idx.Terms, term)
+				}
+			}
+		}
+	}
+	if record.Message == nil {
+		return nil
+	}
+
+	m := record.Message
+	doc = &indexDoc{
+		ChannelID:   channelID,
+		Attachments: len(m.Attachments) > 0,
+		Edited:      record.Type == RecordEdit || m.EditedTimestamp != nil,
+		Offset:      offset,
+	}
+	if m.Author != nil {
+		doc.AuthorID = m.Author.ID
+		doc.Authors = []string{strings.ToLower(m.Author.Username), strings.ToLower(m.Author.GlobalName)}
+	}
+	idx.Docs[id] = doc
+	for _, term := range terms(m) {
+		idx.Terms[term] = insertID(idx.Terms[term], id)
+	}
+	return nil
+}
+
+// readRecord reads the record starting at offset
+func readRecord(file *os.File, offset int64) (*Record, error) {
+	line, err := bufio.NewReader(io.NewSectionReader(file, offset, 1<<62)).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name(), err)
+	}
+	var record Record
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse %s at byte %d: %w", file.Name(), offset, err)
+	}
+	return &record, nil
+}
+
+// terms returns the distinct terms of a message's text, embeds and
+// attachment names
+func terms(m *discordgo.Message) []string {
+	if m == nil {
+		return nil
+	}
+	return tokenize(searchText(m))
+}
+
+// searchText is the text of a message that searches look at
+func searchText(m *discordgo.Message) string {
+	parts := []string{m.Content}
+	for _, e := range m.Embeds {
+		parts = append(parts, e.Title, e.Description)
+		for _, f := range e.Fields {
+			parts = append(parts, f.Name, f.Value)
+		}
+		if e.Footer != nil {
+			parts = append(parts, e.Footer.Text)
+		}
+	}
+	for _, a := range m.Attachments {
+		parts = append(parts, a.Filename)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// tokenize splits text into distinct lower case words
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+// insertID adds id to a sorted list of IDs
+func insertID(ids []uint64, id uint64) []uint64 {
+	i, found := slices.BinarySearch(ids, id)
+	if found {
+		return ids
+	}
+	return slices.Insert(ids, i, id)
+}
+
+// removeID removes id from a sorted list of IDs
+func removeID(ids []uint64, id uint64) []uint64 {
+	if i, found := slices.BinarySearch(ids, id); found {
+		return slices.Delete(ids, i, i+1)
+	}
+	return ids
+}
@@ -0,0 +1,194 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/discord/fake"
+)
+
+// testArchive is an archive of one channel on a fake Discord server
+type testArchive struct {
+	*Archive
+	srv     *fake.Server
+	client  *discord.DiscordClient
+	channel *discordgo.Channel
+	author  *discordgo.User
+}
+
+func newTestArchive(t *testing.T) *testArchive {
+	t.Helper()
+	srv := fake.NewServer()
+	t.Cleanup(srv.Close)
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	guild := srv.AddGuild("Test Guild")
+	a, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(a.Close)
+	return &testArchive{
+		Archive: a,
+		srv:     srv,
+		client:  client,
+		channel: srv.AddChannel(guild.ID, "general", discordgo.ChannelTypeGuildText),
+		author:  srv.AddUser("author"),
+	}
+}
+
+func (a *testArchive) sync(t *testing.T) *SyncResult {
+	t.Helper()
+	result, err := a.Sync(context.Background(), a.client, a.channel, time.Hour)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	return result
+}
+
+// search returns the IDs of the messages containing text
+func (a *testArchive) search(t *testing.T, text string) []string {
+	t.Helper()
+	results, err := a.Search(SearchQuery{Text: text})
+	if err != nil {
+		t.Fatalf("search %q: %v", text, err)
+	}
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+	return ids
+}
+
+func TestIndexEdit(t *testing.T) {
+	a := newTestArchive(t)
+	m := a.srv.AddMessage(a.channel.ID, a.author, "hello world")
+	a.sync(t)
+	if ids := a.search(t, "world"); len(ids) != 1 || ids[0] != m.ID {
+		t.Fatalf("search world = %v, want [%s]", ids, m.ID)
+	}
+
+	content := "hello there"
+	if _, err := a.client.EditChannelMessage(a.channel.ID, m.ID, &discordgo.MessageEdit{ID: m.ID, Channel: a.channel.ID, Content: &content}); err != nil {
+		t.Fatal(err)
+	}
+	if result := a.sync(t); result.Edited != 1 {
+		t.Fatalf("edited = %d, want 1", result.Edited)
+	}
+
+	// The saved index is brought up to date with the edit record
+	if ids := a.search(t, "world"); len(ids) != 0 {
+		t.Errorf("search world after edit = %v, want none", ids)
+	}
+	if ids := a.search(t, "there"); len(ids) != 1 || ids[0] != m.ID {
+		t.Errorf("search there = %v, want [%s]", ids, m.ID)
+	}
+	results, err := a.Search(SearchQuery{Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Edited || results[0].Content != content {
+		t.Errorf("search hello = %+v, want the edited message once", results)
+	}
+	idx, err := a.loadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := idx.Terms["world"]; ok {
+		t.Error("index still has the term the edit removed")
+	}
+}
+
+func TestIndexDelete(t *testing.T) {
+	a := newTestArchive(t)
+	m := a.srv.AddMessage(a.channel.ID, a.author, "goodbye")
+	a.sync(t)
+	a.search(t, "goodbye")
+
+	if err := a.client.DeleteChannelMessage(a.channel.ID, m.ID); err != nil {
+		t.Fatal(err)
+	}
+	if result := a.sync(t); result.Deleted != 1 {
+		t.Fatalf("deleted = %d, want 1", result.Deleted)
+	}
+	results, err := a.Search(SearchQuery{Text: "goodbye"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Deleted {
+		t.Errorf("search = %+v, want the message marked deleted", results)
+	}
+}
+
+func TestLoadIndexRebuild(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(t *testing.T, a *testArchive)
+	}{
+		{"corrupt", func(t *testing.T, a *testArchive) {
+			if err := os.WriteFile(a.indexPath(), []byte("not an index"), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"other version", func(t *testing.T, a *testArchive) {
+			idx, err := a.loadIndex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			idx.Version = indexVersion + 1
+			idx.Terms["stale"] = []uint64{1}
+			if err := a.saveIndex(idx); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"channel file replaced", func(t *testing.T, a *testArchive) {
+			idx, err := a.loadIndex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			idx.Files[a.channel.ID] = a.Channel(a.channel.ID).Size + 1
+			idx.Terms["stale"] = []uint64{1}
+			if err := a.saveIndex(idx); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"missing", func(t *testing.T, a *testArchive) {
+			if err := os.Remove(a.indexPath()); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestArchive(t)
+			m := a.srv.AddMessage(a.channel.ID, a.author, "archived text")
+			a.sync(t)
+			a.search(t, "archived")
+			tt.damage(t, a)
+
+			idx, err := a.loadIndex()
+			if err != nil {
+				t.Fatalf("loadIndex: %v", err)
+			}
+			if idx.Version != indexVersion {
+				t.Errorf("version = %d, want %d", idx.Version, indexVersion)
+			}
+			if _, ok := idx.Terms["stale"]; ok {
+				t.Error("index was not rebuilt")
+			}
+			if idx.Files[a.channel.ID] != a.Channel(a.channel.ID).Size {
+				t.Errorf("indexed %d bytes, want %d", idx.Files[a.channel.ID], a.Channel(a.channel.ID).Size)
+			}
+			if ids := a.search(t, "text"); len(ids) != 1 || ids[0] != m.ID {
+				t.Errorf("search = %v, want [%s]", ids, m.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// SearchQuery selects archived messages. Empty fields match everything.
+type SearchQuery struct {
+	Text          string   // Words that must all appear, in any order and case
+	Author        string   // Author ID, username or global name
+	ChannelIDs    []string // Only these channels
+	Since         time.Time
+	Until         time.Time
+	HasAttachment bool
+	Regex         *regexp.Regexp // Must match the text of the message
+	Limit         int            // At most this many results, 0 for all
+}
+
+// SearchResult is an archived message matching a search
+type SearchResult struct {
+	ID          string    `json:"id" table:"-"`
+	Time        time.Time `json:"time" table:"Time"`
+	GuildID     string    `json:"guild_id" table:"-"`
+	ChannelID   string    `json:"channel_id" table:"-"`
+	Channel     string    `json:"channel" table:"Channel"`
+	AuthorID    string    `json:"author_id" table:"-"`
+	Author      string    `json:"author" table:"Author"`
+	Content     string    `json:"content" table:"-"`
+	Snippet     string    `json:"-" table:"Content"`
+	Attachments int       `json:"attachments" table:"-"`
+	Edited      bool      `json:"edited" table:"-"`
+	Deleted     bool      `json:"deleted" table:"Deleted"`
+	URL         string    `json:"url" table:"Link"`
+}
+
+// snippetLength is the number of characters of content shown in a table
+const snippetLength = 60
+
+// FindChannels returns the IDs of the archived channels matching an ID or a
+// name, with or without #. A name can match channels of several guilds.
+func (a *Archive) FindChannels(s string) ([]string, error) {
+	if a.Channel(s) != nil {
+		return []string{s}, nil
+	}
+	name := strings.TrimPrefix(s, "#")
+	var ids []string
+	for _, id := range a.ChannelIDs() {
+		if strings.EqualFold(a.Channel(id).Name, name) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("channel %q is not in the archive", s)
+	}
+	return ids, nil
+}
+
+// Search returns the archived messages matching q, newest first. The index
+// is brought up to date with the last sync first.
+func (a *Archive) Search(q SearchQuery) ([]SearchResult, error) {
+	idx, err := a.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	words := tokenize(q.Text)
+	candidates := idx.lookup(words)
+	author := strings.ToLower(strings.TrimPrefix(q.Author, "@"))
+
+	files := map[string]*os.File{}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	results := []SearchResult{}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		id := candidates[i]
+		doc := idx.Docs[id]
+		created, _ := snowflake.Time(strconv.FormatUint(id, 10))
+		switch {
+		case len(q.ChannelIDs) > 0 && !slices.Contains(q.ChannelIDs, doc.ChannelID),
+			author != "" && doc.AuthorID != author && !slices.Contains(doc.Authors, author),
+			!q.Since.IsZero() && created.Before(q.Since),
+			!q.Until.IsZero() && !created.Before(q.Until),
+			q.HasAttachment && !doc.Attachments:
+			continue
+		}
+
+		st := a.Channel(doc.ChannelID)
+		file, ok := files[doc.ChannelID]
+		if !ok {
+			path := a.ChannelPath(st.GuildID, doc.ChannelID)
+			if file, err = os.Open(path); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			files[doc.ChannelID] = file
+		}
+		record, err := readRecord(file, doc.Offset)
+		if err != nil {
+			return nil, err
+		}
+		m := record.Message
+		if m == nil {
+			continue
+		}
+		// The index keeps the terms of earlier versions of edited messages
+		if current := terms(m); !containsAll(current, words) {
+			continue
+		}
+		if q.Regex != nil && !q.Regex.MatchString(searchText(m)) {
+			continue
+		}
+
+		results = append(results, newSearchResult(m, st, doc, created))
+		if q.Limit > 0 && len(results) >= q.Limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// lookup returns the IDs of the messages containing every word, ascending.
+// Without words it returns every message.
+func (idx *index) lookup(words []string) []uint64 {
+	if len(words) == 0 {
+		ids := make([]uint64, 0, len(idx.Docs))
+		for id := range idx.Docs {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	lists := make([][]uint64, len(words))
+	for i, word := range words {
+		lists[i] = idx.Terms[word]
+	}
+	// Intersect starting with the rarest word
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+	ids := slices.Clone(lists[0])
+	for _, list := range lists[1:] {
+		ids = slices.DeleteFunc(ids, func(id uint64) bool {
+			_, found := slices.BinarySearch(list, id)
+			return !found
+		})
+	}
+	return ids
+}
+
+func newSearchResult(m *discordgo.Message, st *ChannelState, doc *indexDoc, created time.Time) SearchResult {
+	result := SearchResult{
+		ID:          m.ID,
+		Time:        created.UTC(),
+		GuildID:     st.GuildID,
+		ChannelID:   doc.ChannelID,
+		Channel:     "#" + st.Name,
+		Content:     m.Content,
+		Attachments: len(m.Attachments),
+		Edited:      doc.Edited,
+		Deleted:     doc.Deleted,
+		URL:         JumpURL(st.GuildID, doc.ChannelID, m.ID),
+	}
+	if m.Author != nil {
+		result.AuthorID = m.Author.ID
+		result.Author = m.Author.Username
+	}
+
+	snippet, _, _ := strings.Cut(m.Content, "\n")
+	if runes := []rune(snippet); len(runes) > snippetLength {
+		snippet = string(runes[:snippetLength]) + "…"
+	}
+	if snippet == "" && result.Attachments > 0 {
+		snippet = fmt.Sprintf("(%d attachments)", result.Attachments)
+	}
+	result.Snippet = snippet
+	return result
+}
+
+// JumpURL returns the link that opens a message in Discord
+func JumpURL(guildID, channelID, messageID string) string {
+	if guildID == "" {
+		guildID = "@me"
+	}
+	return "https://discord.com/channels/" + guildID + "/" + channelID + "/" + messageID
+}
+
+// containsAll reports whether the sorted terms contain every word
+func containsAll(terms, words []string) bool {
+	for _, word := range words {
+		if _, found := slices.BinarySearch(terms, word); !found {
+			return false
+		}
+	}
+	return true
+}
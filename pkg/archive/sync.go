@@ -0,0 +1,175 @@
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// checkpointEvery is the number of new messages written between checkpoints
+// while a channel syncs, so an interrupted first sync of a long history does
+// not start over
+const checkpointEvery = 1000
+
+// SyncResult is the outcome of syncing one channel
+type SyncResult struct {
+	ChannelID string `json:"channel_id" table:"Channel ID"`
+	GuildID   string `json:"guild_id" table:"-"`
+	Name      string `json:"name" table:"Name"`
+	New       int    `json:"new" table:"New"`
+	Edited    int    `json:"edited" table:"Edited"`
+	Deleted   int    `json:"deleted" table:"Deleted"`
+	Messages  int    `json:"messages" table:"Archived"` // Messages archived so far, deleted ones included
+	Error     string `json:"error,omitempty" table:"Error"`
+}
+
+// Sync brings the archive of a channel up to date. It appends every message
+// sent after the last synced one, then looks again at the messages sent
+// within recheck before that and records the ones that were edited or
+// deleted since. Older messages are not rechecked; a zero recheck turns
+// rechecking off. Cancelling ctx stops the sync at the last checkpoint.
+func (a *Archive) Sync(ctx context.Context, api discord.DiscordAPI, channel *discordgo.Channel, recheck time.Duration) (*SyncResult, error) {
+	st := &ChannelState{GuildID: channel.GuildID}
+	if saved := a.state.Channels[channel.ID]; saved != nil {
+		// Work on a copy so only checkpoints reach the saved state
+		*st = *saved
+		st.Recent = maps.Clone(saved.Recent)
+	}
+	st.Name = channel.Name
+	if st.Recent == nil {
+		st.Recent = map[string]string{}
+	}
+	result := &SyncResult{ChannelID: channel.ID, GuildID: channel.GuildID, Name: channel.Name}
+
+	path := a.ChannelPath(channel.GuildID, channel.ID)
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return result, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, fileMode)
+	if err != nil {
+		return result, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	// Cut the records an interrupted sync wrote after its last checkpoint
+	if err := file.Truncate(st.Size); err != nil {
+		return result, fmt.Errorf("failed to truncate %s: %w", path, err)
+	}
+	if _, err := file.Seek(st.Size, io.SeekStart); err != nil {
+		return result, fmt.Errorf("failed to seek %s: %w", path, err)
+	}
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+
+	now := time.Now().UTC()
+	windowStart := now.Add(-recheck)
+	lastSynced := st.LastMessageID
+	cursor := "0"
+	if lastSynced != "" {
+		cursor = lastSynced
+		if recheck > 0 {
+			if id := snowflake.FromTime(windowStart); snowflake.Less(id, cursor) {
+				cursor = snowflake.Previous(id)
+			}
+		}
+	}
+
+	write := func(record Record) error {
+		record.SyncedAt = now
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+	checkpoint := func() error {
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		size, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		st.Size = size
+		st.SyncedAt = now
+		saved := *st
+		saved.Recent = maps.Clone(st.Recent)
+		a.state.Channels[channel.ID] = &saved
+		return a.save()
+	}
+
+	seen := map[string]bool{}
+	for message, err := range discord.IterChannelMessages(api, channel.ID, "", cursor) {
+		if err != nil {
+			return result, err
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		seen[message.ID] = true
+		edited := editedAt(message)
+
+		if lastSynced != "" && !snowflake.Less(lastSynced, message.ID) {
+			// Already archived, check for an edit
+			if previous, ok := st.Recent[message.ID]; ok && previous != edited {
+				if err := write(Record{Type: RecordEdit, ID: message.ID, Message: message}); err != nil {
+					return result, err
+				}
+				st.Recent[message.ID] = edited
+				result.Edited++
+			}
+			continue
+		}
+
+		if err := write(Record{Type: RecordMessage, ID: message.ID, Message: message}); err != nil {
+			return result, err
+		}
+		st.LastMessageID = message.ID
+		st.Messages++
+		result.New++
+		if recheck > 0 && message.Timestamp.After(windowStart) {
+			st.Recent[message.ID] = edited
+		}
+		if result.New%checkpointEvery == 0 {
+			if err := checkpoint(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	// Recent messages the walk passed over are gone
+	for id := range st.Recent {
+		if snowflake.Less(cursor, id) && !seen[id] {
+			if err := write(Record{Type: RecordDelete, ID: id}); err != nil {
+				return result, err
+			}
+			delete(st.Recent, id)
+			result.Deleted++
+		}
+	}
+	for id := range st.Recent {
+		if created, err := snowflake.Time(id); err != nil || created.Before(windowStart) {
+			delete(st.Recent, id)
+		}
+	}
+
+	result.Messages = st.Messages
+	return result, checkpoint()
+}
+
+// editedAt returns when a message was last edited, empty if never
+func editedAt(m *discordgo.Message) string {
+	if m.EditedTimestamp == nil {
+		return ""
+	}
+	return m.EditedTimestamp.UTC().Format(time.RFC3339Nano)
+}
@@ -2,37 +2,59 @@ package cfg
 
 import (
 	"errors"
-	"log"
-	"os"
-
-	"gopkg.in/yaml.v3"
-)
-
-const (
-	filePath = "/.dccli"
-	fileName = "/config.yaml"
+	"fmt"
 )
 
 var (
 	ErrNotConfigured     = errors.New("bot not configured")
 	ErrBotConfigNotFound = errors.New("bot config not found")
+	ErrContextNotFound   = errors.New("context not found")
 )
 
 // BotConfig represents a bot configuration from a config file
 type BotConfig struct {
 	Name string `yaml:"name"`
 	Bot  Bot    `yaml:"bot"`
+	// APIBase points the bot at a Discord-compatible REST API (e.g. https://host/api/v9)
+	APIBase string `yaml:"api-base,omitempty"`
+	// GatewayURL overrides the websocket gateway returned by the API
+	GatewayURL string `yaml:"gateway-url,omitempty"`
+	// DefaultReason is sent as the audit log reason when --reason is not given
+	DefaultReason string `yaml:"default-reason,omitempty"`
 }
 
 // Bot contains bot configurations
 type Bot struct {
-	Token string `yaml:"token"`
+	Token string `yaml:"token,omitempty"`
+	// EncryptedToken is the token sealed with the config vault
+	EncryptedToken string `yaml:"encrypted-token,omitempty"`
+	// TokenCommand is a shell command printing the token (e.g. "pass show discord/prod")
+	TokenCommand string `yaml:"token-command,omitempty"`
+	// TokenFile is a file holding the token, such as a mounted secret
+	TokenFile string `yaml:"token-file,omitempty"`
+	// TokenEnv names an environment variable holding the token
+	TokenEnv string `yaml:"token-env,omitempty"`
+}
+
+// Context binds a bot to the guild, channel and output format commands
+// default to while it is active
+type Context struct {
+	Name    string `yaml:"name"`
+	Bot     string `yaml:"bot"`
+	Guild   string `yaml:"guild,omitempty"`
+	Channel string `yaml:"channel,omitempty"`
+	Output  string `yaml:"output,omitempty"`
 }
 
 // Config represents a CLI configuration file
 type Config struct {
-	CurrentBot string      `yaml:"current-bot"`
-	Bots       []BotConfig `yaml:"bots"`
+	// Version is the schema version, see CurrentVersion
+	Version        int         `yaml:"version"`
+	CurrentBot     string      `yaml:"current-bot"`
+	Bots           []BotConfig `yaml:"bots"`
+	CurrentContext string      `yaml:"current-context,omitempty"`
+	Contexts       []Context   `yaml:"contexts,omitempty"`
+	Vault          *Vault      `yaml:"vault,omitempty"`
 }
 
 // GetBotByName returns bot by its config name
@@ -45,71 +67,29 @@ func (c *Config) GetBotByName(name string) (*BotConfig, error) {
 	return nil, ErrBotConfigNotFound
 }
 
-// GetCurrent returns current configured bot.
-// If current bot is not found, change current to first from the configured bot list
-func (c *Config) GetCurrent() (*BotConfig, error) {
-	if len(c.Bots) == 0 {
-		return nil, ErrNotConfigured
-	}
-
-	bot, err := c.GetBotByName(c.CurrentBot)
-	if err != nil {
-		log.Printf("Bot %s not found in config. Changing to first from list", c.CurrentBot)
-		c.CurrentBot = c.Bots[0].Name
-		SaveConfig(c)
-		return &c.Bots[0], nil
-	}
-	return bot, nil
-}
-
-func LoadConfig() (*Config, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal("Unable to get home directory")
-	}
-	if _, err := os.Stat(home + filePath); os.IsNotExist(err) {
-		err := os.Mkdir(home+filePath, os.ModePerm)
-		if err != nil {
-			log.Fatal("Unable to create config directory: ", err)
+// GetContextByName returns context by its name
+func (c *Config) GetContextByName(name string) (*Context, error) {
+	for i, ctx := range c.Contexts {
+		if ctx.Name == name {
+			return &c.Contexts[i], nil
 		}
-		return nil, ErrNotConfigured
 	}
-	if _, err := os.Stat(home + filePath + fileName); os.IsNotExist(err) {
-		return nil, ErrNotConfigured
-	}
-
-	file, err := os.ReadFile(home + filePath + fileName)
-	if err != nil {
-		log.Fatal("Unable to open config file: ", err)
-	}
-
-	var config *Config
-	err = yaml.Unmarshal(file, &config)
-	if err != nil {
-		log.Fatal("Unable to parse config")
-	}
-
-	return config, nil
+	return nil, ErrContextNotFound
 }
 
-func SaveConfig(config *Config) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal("Unable to get home directory")
-	}
-	file, err := os.OpenFile(home+filePath+fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
-	if err != nil {
-		log.Fatal("Unable to open config file: ", err)
+// GetCurrent returns current configured bot, or the first bot when no
+// current bot is set. It never modifies the config.
+func (c *Config) GetCurrent() (*BotConfig, error) {
+	if len(c.Bots) == 0 {
+		return nil, ErrNotConfigured
 	}
-	defer file.Close()
-
-	out, err := yaml.Marshal(config)
-	if err != nil {
-		log.Fatal("Unable to parse config")
+	if c.CurrentBot == "" {
+		return &c.Bots[0], nil
 	}
 
-	_, err = file.Write(out)
+	bot, err := c.GetBotByName(c.CurrentBot)
 	if err != nil {
-		log.Fatal("Unable to write config")
+		return nil, fmt.Errorf("current bot '%s': %w", c.CurrentBot, err)
 	}
+	return bot, nil
 }
@@ -0,0 +1,220 @@
+package cfg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PathEnv overrides the config file location
+	PathEnv = "DCCLI_CONFIG"
+
+	appName  = "dccli"
+	fileName = "config.yaml"
+	// legacyDir is where versions before XDG support kept the config, in $HOME
+	legacyDir = ".dccli"
+
+	// The config may hold plaintext tokens, so only the owner gets access
+	dirMode  os.FileMode = 0o700
+	fileMode os.FileMode = 0o600
+)
+
+// ErrInvalidConfig is wrapped by errors for config files that cannot be parsed
+var ErrInvalidConfig = errors.New("invalid config")
+
+// FileError records a failed operation on the config file
+type FileError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("failed to %s config %s: %v", e.Op, e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// pathOverride is set by SetPath
+var pathOverride string
+
+// SetPath makes LoadConfig, SaveConfig and UpdateConfig use path. An empty
+// path restores the default lookup.
+func SetPath(path string) {
+	pathOverride = path
+}
+
+// Path returns the config file location: the SetPath override, then
+// DCCLI_CONFIG, then ~/.dccli/config.yaml if it exists, then
+// $XDG_CONFIG_HOME/dccli/config.yaml (the platform config directory outside
+// Linux and BSD).
+func Path() (string, error) {
+	if pathOverride != "" {
+		return pathOverride, nil
+	}
+	if path := os.Getenv(PathEnv); path != "" {
+		return path, nil
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		legacy := filepath.Join(home, legacyDir, fileName)
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy, nil
+		}
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", &FileError{Op: "locate", Err: err}
+	}
+	return filepath.Join(dir, appName, fileName), nil
+}
+
+// LoadConfig reads the config file and migrates it to the current schema in
+// memory. It returns ErrNotConfigured when the file does not exist.
+func LoadConfig() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Load reads and migrates the config file at path
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotConfigured
+		}
+		return nil, &FileError{Op: "read", Path: path, Err: err}
+	}
+	restrictPermissions(path)
+
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, &FileError{Op: "parse", Path: path, Err: fmt.Errorf("%w: %v", ErrInvalidConfig, err)}
+	}
+	if err := migrate(config); err != nil {
+		return nil, &FileError{Op: "migrate", Path: path, Err: err}
+	}
+	return config, nil
+}
+
+// SaveConfig writes config to the config file
+func SaveConfig(config *Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return Save(path, config)
+}
+
+// Save writes config to path while holding the config lock
+func Save(path string, config *Config) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return write(path, config)
+}
+
+// UpdateConfig loads the config, applies fn and saves the result while
+// holding the config lock, so concurrent dccli processes do not overwrite
+// each other's changes. fn gets an empty config when none exists yet.
+// Nothing is written when fn returns an error.
+func UpdateConfig(fn func(config *Config) error) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return Update(path, fn)
+}
+
+// Update is UpdateConfig for the config file at path
+func Update(path string, fn func(config *Config) error) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	config, err := Load(path)
+	if errors.Is(err, ErrNotConfigured) {
+		config, err = &Config{Version: CurrentVersion}, nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := fn(config); err != nil {
+		return err
+	}
+	return write(path, config)
+}
+
+// write replaces the config file atomically: the new content goes to a
+// temporary file next to it which is then renamed over the old one, so
+// readers never see a partial file
+func write(path string, config *Config) error {
+	config.Version = CurrentVersion
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	if err := encoder.Encode(config); err != nil {
+		return &FileError{Op: "encode", Path: path, Err: err}
+	}
+	if err := encoder.Close(); err != nil {
+		return &FileError{Op: "encode", Path: path, Err: err}
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return &FileError{Op: "create directory for", Path: path, Err: err}
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return &FileError{Op: "write", Path: path, Err: err}
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(fileMode); err != nil && runtime.GOOS != "windows" {
+		tmp.Close()
+		return &FileError{Op: "write", Path: path, Err: err}
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return &FileError{Op: "write", Path: path, Err: err}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return &FileError{Op: "write", Path: path, Err: err}
+	}
+	if err := tmp.Close(); err != nil {
+		return &FileError{Op: "write", Path: path, Err: err}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return &FileError{Op: "replace", Path: path, Err: err}
+	}
+	return nil
+}
+
+// restrictPermissions takes group and other access away from a config file
+// that older versions created world-readable. Failures are ignored, the
+// file stays usable either way.
+func restrictPermissions(path string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&^fileMode == 0 {
+		return
+	}
+	_ = os.Chmod(path, fileMode)
+}
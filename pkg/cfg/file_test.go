@@ -0,0 +1,216 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	const n = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Update(path, func(config *Config) error {
+				// Widen the window between load and write so an update
+				// made without the lock would be lost
+				time.Sleep(time.Millisecond)
+				config.Bots = append(config.Bots, BotConfig{Name: fmt.Sprintf("bot%d", i)})
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Bots) != n {
+		t.Fatalf("got %d bots, want %d", len(config.Bots), n)
+	}
+	for i := range n {
+		if _, err := config.GetBotByName(fmt.Sprintf("bot%d", i)); err != nil {
+			t.Errorf("bot%d was lost", i)
+		}
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	unlock, err := lockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := lockFile(path)
+		if err != nil {
+			t.Error(err)
+		} else {
+			unlock()
+		}
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("second lock taken while the first was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lock not taken after the first was released")
+	}
+}
+
+func TestUpdateFailureKeepsFile(t *testing.T) {
+	old, err := os.ReadFile(filepath.Join("testdata", "config_v0.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("fn error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, old, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		fnErr := errors.New("refused")
+		err := Update(path, func(config *Config) error {
+			config.Bots = nil
+			return fnErr
+		})
+		if !errors.Is(err, fnErr) {
+			t.Fatalf("Update = %v, want %v", err, fnErr)
+		}
+		assertFile(t, path, old)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		// The temporary file name is longer than the config file's and its
+		// lock's, so a name near the 255 byte limit makes only creating it
+		// fail, even for root
+		dir := t.TempDir()
+		path := filepath.Join(dir, strings.Repeat("c", 245)+".yaml")
+		if err := os.WriteFile(path, old, 0o600); err != nil {
+			t.Skipf("file system does not allow long names: %v", err)
+		}
+		err := Update(path, func(config *Config) error {
+			config.Bots = nil
+			return nil
+		})
+		var fileErr *FileError
+		if !errors.As(err, &fileErr) || fileErr.Op != "write" {
+			t.Fatalf("Update = %v, want a write FileError", err)
+		}
+		assertFile(t, path, old)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".tmp") {
+				t.Errorf("temporary file %s left behind", entry.Name())
+			}
+		}
+	})
+}
+
+func TestLoadMigrates(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "config_v0.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	// Versions before 1 created the file world-readable
+	if err := os.WriteFile(path, fixture, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if config.Version != CurrentVersion {
+		t.Errorf("version = %d, want %d", config.Version, CurrentVersion)
+	}
+	if config.CurrentBot != "main" || len(config.Bots) != 2 {
+		t.Fatalf("config = %+v, want bots main and staging", config)
+	}
+	for _, bot := range config.Bots {
+		if want := bot.Name + "-token"; bot.Bot.Token != want {
+			t.Errorf("bot %s token = %q, want %q", bot.Name, bot.Bot.Token, want)
+		}
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != fileMode {
+			t.Errorf("mode = %v, want %v", perm, fileMode)
+		}
+	}
+
+	// Loading migrates in memory only; the next write stores the version
+	assertFile(t, path, fixture)
+	if err := Update(path, func(config *Config) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), fmt.Sprintf("version: %d\n", CurrentVersion)) {
+		t.Errorf("saved config has no version:\n%s", data)
+	}
+}
+
+func TestLoadNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte(fmt.Sprintf("version: %d\ncurrent-bot: main\n", CurrentVersion+1))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("Load = %v, want ErrUnsupportedVersion", err)
+	}
+	if err := Update(path, func(config *Config) error { return nil }); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("Update = %v, want ErrUnsupportedVersion", err)
+	}
+	assertFile(t, path, data)
+}
+
+// assertFile fails unless the file at path holds want
+func assertFile(t *testing.T, path string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s changed:\n%s\nwant:\n%s", filepath.Base(path), got, want)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockFile takes an exclusive advisory lock on path.lock, waiting for other
+// dccli processes to release it, and returns the function releasing it
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return nil, &FileError{Op: "create directory for", Path: path, Err: err}
+	}
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, fileMode)
+	if err != nil {
+		return nil, &FileError{Op: "lock", Path: path, Err: err}
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, &FileError{Op: "lock", Path: path, Err: err}
+	}
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
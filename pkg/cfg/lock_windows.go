@@ -0,0 +1,32 @@
+//go:build windows
+
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile takes an exclusive advisory lock on path.lock, waiting for other
+// dccli processes to release it, and returns the function releasing it
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return nil, &FileError{Op: "create directory for", Path: path, Err: err}
+	}
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, fileMode)
+	if err != nil {
+		return nil, &FileError{Op: "lock", Path: path, Err: err}
+	}
+	handle := windows.Handle(file.Fd())
+	overlapped := new(windows.Overlapped)
+	if err := windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped); err != nil {
+		file.Close()
+		return nil, &FileError{Op: "lock", Path: path, Err: err}
+	}
+	return func() {
+		windows.UnlockFileEx(handle, 0, 1, 0, overlapped)
+		file.Close()
+	}, nil
+}
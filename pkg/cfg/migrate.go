@@ -0,0 +1,34 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CurrentVersion is the config schema version this package reads and writes
+const CurrentVersion = 1
+
+// ErrUnsupportedVersion is returned for config files written by a newer dccli
+var ErrUnsupportedVersion = errors.New("unsupported config version")
+
+// migrations[i] upgrades a config from version i to i+1. Configs without a
+// version field are version 0.
+var migrations = []func(config *Config) error{
+	// 0 -> 1: the version field was added. The layout is unchanged.
+	func(config *Config) error { return nil },
+}
+
+// migrate upgrades config to CurrentVersion in place. Files from a newer
+// dccli are refused rather than rewritten without the fields it added.
+func migrate(config *Config) error {
+	if config.Version > CurrentVersion {
+		return fmt.Errorf("%w %d, this dccli supports up to %d", ErrUnsupportedVersion, config.Version, CurrentVersion)
+	}
+	for config.Version < CurrentVersion {
+		if err := migrations[config.Version](config); err != nil {
+			return fmt.Errorf("failed to migrate config from version %d: %w", config.Version, err)
+		}
+		config.Version++
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cfg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrTokenNotSet is returned when a bot has neither a token nor a token source
+var ErrTokenNotSet = errors.New("bot token is not set")
+
+// HasTokenSource reports whether the token is read from a command, file or
+// environment variable instead of being stored in the config
+func (b *Bot) HasTokenSource() bool {
+	return b.TokenCommand != "" || b.TokenFile != "" || b.TokenEnv != ""
+}
+
+// tokenSources returns the names of the token fields that are set, the one
+// that takes effect first
+func (b *Bot) tokenSources() []string {
+	var sources []string
+	if b.EncryptedToken != "" {
+		sources = append(sources, "encrypted-token")
+	}
+	if b.Token != "" {
+		sources = append(sources, "token")
+	}
+	if b.TokenEnv != "" {
+		sources = append(sources, "token-env")
+	}
+	if b.TokenFile != "" {
+		sources = append(sources, "token-file")
+	}
+	if b.TokenCommand != "" {
+		sources = append(sources, "token-command")
+	}
+	return sources
+}
+
+// ResolveToken returns the literal token or reads it from token-env,
+// token-file or token-command, in that order. Encrypted tokens need the
+// vault and are handled by DecryptToken.
+func (b *Bot) ResolveToken() (string, error) {
+	switch {
+	case b.Token != "":
+		return b.Token, nil
+	case b.TokenEnv != "":
+		token := strings.TrimSpace(os.Getenv(b.TokenEnv))
+		if token == "" {
+			return "", fmt.Errorf("environment variable %s is not set", b.TokenEnv)
+		}
+		return token, nil
+	case b.TokenFile != "":
+		data, err := os.ReadFile(b.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", b.TokenFile)
+		}
+		return token, nil
+	case b.TokenCommand != "":
+		return runTokenCommand(b.TokenCommand)
+	default:
+		return "", ErrTokenNotSet
+	}
+}
+
+// runTokenCommand runs command through the shell and returns the first line
+// it prints. stdin and stderr stay attached so password managers can prompt.
+func runTokenCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command failed: %w", err)
+	}
+	// Tools like `pass show` print the secret on the first line
+	token, _, _ := strings.Cut(stdout.String(), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", errors.New("token command printed nothing")
+	}
+	return token, nil
+}
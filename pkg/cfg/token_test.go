@@ -0,0 +1,68 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestResolveToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DCCLI_TEST_TOKEN", "env-token\n")
+	t.Setenv("DCCLI_TEST_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		bot     Bot
+		want    string
+		wantErr bool
+	}{
+		{"literal", Bot{Token: "literal-token", TokenEnv: "DCCLI_TEST_TOKEN"}, "literal-token", false},
+		{"env", Bot{TokenEnv: "DCCLI_TEST_TOKEN"}, "env-token", false},
+		{"env before file", Bot{TokenEnv: "DCCLI_TEST_TOKEN", TokenFile: tokenFile}, "env-token", false},
+		{"unset env", Bot{TokenEnv: "DCCLI_TEST_EMPTY"}, "", true},
+		{"file", Bot{TokenFile: tokenFile}, "file-token", false},
+		{"empty file", Bot{TokenFile: emptyFile}, "", true},
+		{"missing file", Bot{TokenFile: filepath.Join(dir, "missing")}, "", true},
+		{"command", Bot{TokenCommand: "echo command-token"}, "command-token", false},
+		{"command first line", Bot{TokenCommand: "echo first && echo second"}, "first", false},
+		{"failing command", Bot{TokenCommand: "exit 3"}, "", true},
+		{"silent command", Bot{TokenCommand: "echo"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" && tt.bot.TokenCommand != "" {
+				t.Skip("token commands run through sh")
+			}
+			got, err := tt.bot.ResolveToken()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResolveToken = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveToken: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveToken = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTokenNotSet(t *testing.T) {
+	if _, err := (&Bot{}).ResolveToken(); !errors.Is(err, ErrTokenNotSet) {
+		t.Errorf("ResolveToken = %v, want ErrTokenNotSet", err)
+	}
+}
@@ -2,6 +2,7 @@ package cfg
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -47,6 +48,23 @@ func ValidateConfig(config *Config) *ValidationResult {
 		botNames := make(map[string]bool)
 		for i, bot := range config.Bots {
 			validateBotConfig(&bot, i, result, botNames)
+			if bot.Encrypted() && config.Vault == nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, ValidationError{
+					Field:   fmt.Sprintf("bots[%d].bot.encrypted-token", i),
+					Message: "encrypted token found but no vault is configured",
+				})
+			}
+		}
+	}
+
+	if config.Vault != nil {
+		if err := config.Vault.Validate(); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "vault",
+				Message: err.Error(),
+			})
 		}
 	}
 
@@ -63,9 +81,66 @@ func ValidateConfig(config *Config) *ValidationResult {
 		result.Warnings = append(result.Warnings, "no current bot set, using first bot")
 	}
 
+	// Validate contexts
+	contextNames := make(map[string]bool)
+	for i, ctx := range config.Contexts {
+		validateContext(config, &ctx, i, result, contextNames)
+	}
+
+	// Validate current context
+	if config.CurrentContext != "" {
+		if _, err := config.GetContextByName(config.CurrentContext); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "current-context",
+				Message: fmt.Sprintf("current context '%s' not found in configured contexts", config.CurrentContext),
+			})
+		}
+	}
+
 	return result
 }
 
+// validateContext validates a single context
+func validateContext(config *Config, ctx *Context, index int, result *ValidationResult, contextNames map[string]bool) {
+	prefix := fmt.Sprintf("contexts[%d]", index)
+
+	if ctx.Name == "" {
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   prefix + ".name",
+			Message: "context name is required",
+		})
+	} else {
+		if contextNames[ctx.Name] {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   prefix + ".name",
+				Message: fmt.Sprintf("duplicate context name '%s'", ctx.Name),
+			})
+		}
+		contextNames[ctx.Name] = true
+	}
+
+	if _, err := config.GetBotByName(ctx.Bot); err != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   prefix + ".bot",
+			Message: fmt.Sprintf("bot '%s' not found in configured bots", ctx.Bot),
+		})
+	}
+
+	switch ctx.Output {
+	case "", "table", "json", "yaml", "ndjson", "csv", "tsv":
+	default:
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   prefix + ".output",
+			Message: fmt.Sprintf("unsupported output format '%s'", ctx.Output),
+		})
+	}
+}
+
 // validateBotConfig validates a single bot configuration
 func validateBotConfig(bot *BotConfig, index int, result *ValidationResult, botNames map[string]bool) {
 	prefix := fmt.Sprintf("bots[%d]", index)
@@ -96,15 +171,29 @@ func validateBotConfig(bot *BotConfig, index int, result *ValidationResult, botN
 	}
 
 	// Validate token
-	if bot.Bot.Token == "" {
+	sources := bot.Bot.tokenSources()
+	if len(sources) > 1 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("bot '%s' sets %s, only %s is used", bot.Name, strings.Join(sources, ", "), sources[0]))
+	}
+	if len(sources) == 0 {
 		result.Valid = false
 		result.Errors = append(result.Errors, ValidationError{
 			Field:   prefix + ".bot.token",
 			Message: "bot token is required",
 		})
+	} else if bot.Encrypted() {
+		// Encrypted tokens can only be checked once the vault is unlocked
+	} else if token, err := bot.Bot.ResolveToken(); err != nil {
+		// Token sources are resolved so a broken command or mount shows up here
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   prefix + ".bot." + sources[0],
+			Message: err.Error(),
+		})
 	} else {
 		// Check token format
-		tokenValidation := ValidateBotToken(bot.Bot.Token)
+		tokenValidation := ValidateBotToken(token)
 		if !tokenValidation.Valid {
 			result.Valid = false
 			for _, err := range tokenValidation.Errors {
@@ -119,6 +208,36 @@ func validateBotConfig(bot *BotConfig, index int, result *ValidationResult, botN
 				fmt.Sprintf("bot '%s' token: %s", bot.Name, warning))
 		}
 	}
+
+	// Validate endpoint overrides
+	if bot.APIBase != "" && !isValidURL(bot.APIBase, "http", "https") {
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   prefix + ".api-base",
+			Message: fmt.Sprintf("'%s' is not a valid http(s) URL", bot.APIBase),
+		})
+	}
+	if bot.GatewayURL != "" && !isValidURL(bot.GatewayURL, "ws", "wss") {
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   prefix + ".gateway-url",
+			Message: fmt.Sprintf("'%s' is not a valid ws(s) URL", bot.GatewayURL),
+		})
+	}
+}
+
+// isValidURL checks that raw is an absolute URL with one of the given schemes
+func isValidURL(raw string, schemes ...string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return true
+		}
+	}
+	return false
 }
 
 // TokenValidationResult contains the results of token validation
@@ -0,0 +1,208 @@
+package cfg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var (
+	ErrVaultNotConfigured = errors.New("vault not configured")
+	ErrWrongPassphrase    = errors.New("wrong vault passphrase")
+)
+
+// Argon2id parameters for new vaults. They are stored with the vault so
+// they can be raised later without breaking existing files.
+const (
+	vaultKDF     = "argon2id"
+	vaultTime    = 3
+	vaultMemory  = 64 * 1024 // KiB
+	vaultThreads = 4
+	vaultKeyLen  = 32
+	vaultSaltLen = 16
+)
+
+// Limits on stored Argon2id parameters. A zero time or thread count makes
+// argon2 panic and a huge memory cost exhausts RAM, so a tampered or broken
+// vault is rejected before any key is derived.
+const (
+	vaultMaxTime   = 64
+	vaultMaxMemory = 4 * 1024 * 1024 // KiB
+)
+
+// vaultCheck is encrypted into Vault.Check so a passphrase can be verified
+// before any token is touched
+const vaultCheck = "dccli-vault"
+
+// Vault holds the key derivation settings for encrypted bot tokens. Tokens
+// are sealed with AES-256-GCM under a key derived from a passphrase.
+type Vault struct {
+	KDF     string `yaml:"kdf"`
+	Salt    string `yaml:"salt"`
+	Time    uint32 `yaml:"time"`
+	Memory  uint32 `yaml:"memory"`
+	Threads uint8  `yaml:"threads"`
+	Check   string `yaml:"check"`
+}
+
+// VaultKey is an unlocked vault key
+type VaultKey struct {
+	aead cipher.AEAD
+}
+
+// NewVault creates a vault with a fresh salt and returns it unlocked
+func NewVault(passphrase []byte) (*Vault, *VaultKey, error) {
+	salt := make([]byte, vaultSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	vault := &Vault{
+		KDF:     vaultKDF,
+		Salt:    base64.StdEncoding.EncodeToString(salt),
+		Time:    vaultTime,
+		Memory:  vaultMemory,
+		Threads: vaultThreads,
+	}
+	key, err := vault.deriveKey(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	vault.Check, err = key.Encrypt(vaultCheck)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vault, key, nil
+}
+
+// Unlock derives the vault key from passphrase and verifies it
+func (v *Vault) Unlock(passphrase []byte) (*VaultKey, error) {
+	key, err := v.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	check, err := key.Decrypt(v.Check)
+	if err != nil || check != vaultCheck {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+// Validate checks the key derivation settings of the vault
+func (v *Vault) Validate() error {
+	if v.KDF != vaultKDF {
+		return fmt.Errorf("unsupported vault kdf '%s'", v.KDF)
+	}
+	if v.Time < 1 || v.Time > vaultMaxTime {
+		return fmt.Errorf("vault time must be between 1 and %d, got %d", vaultMaxTime, v.Time)
+	}
+	if v.Threads < 1 {
+		return errors.New("vault threads must be at least 1")
+	}
+	if v.Memory < 8*uint32(v.Threads) || v.Memory > vaultMaxMemory {
+		return fmt.Errorf("vault memory must be between %d and %d KiB, got %d", 8*uint32(v.Threads), vaultMaxMemory, v.Memory)
+	}
+	return nil
+}
+
+func (v *Vault) deriveKey(passphrase []byte) (*VaultKey, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	salt, err := base64.StdEncoding.DecodeString(v.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault salt: %w", err)
+	}
+	key := argon2.IDKey(passphrase, salt, v.Time, v.Memory, v.Threads, vaultKeyLen)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultKey{aead: aead}, nil
+}
+
+// Encrypt seals plaintext and returns base64 of nonce followed by ciphertext
+func (k *VaultKey) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := k.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt
+func (k *VaultKey) Decrypt(value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(sealed) < k.aead.NonceSize() {
+		return "", errors.New("invalid encrypted value: too short")
+	}
+	nonce, ciphertext := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+	plaintext, err := k.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+	return string(plaintext), nil
+}
+
+// Encrypted reports whether the bot token is stored in the vault
+func (b *BotConfig) Encrypted() bool {
+	return b.Bot.EncryptedToken != ""
+}
+
+// DecryptToken fills Bot.Token from the encrypted token
+func (b *BotConfig) DecryptToken(key *VaultKey) error {
+	if !b.Encrypted() {
+		return nil
+	}
+	token, err := key.Decrypt(b.Bot.EncryptedToken)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt token of bot '%s': %w", b.Name, err)
+	}
+	b.Bot.Token = token
+	return nil
+}
+
+// EncryptToken moves token into the vault, clearing the plaintext token and
+// any token source
+func (b *BotConfig) EncryptToken(key *VaultKey, token string) error {
+	encrypted, err := key.Encrypt(token)
+	if err != nil {
+		return err
+	}
+	b.Bot = Bot{EncryptedToken: encrypted}
+	return nil
+}
+
+// Rekey re-encrypts every vault token under a new vault made by NewVault.
+// key must be the current vault key.
+func (c *Config) Rekey(key *VaultKey, vault *Vault, newKey *VaultKey) error {
+	if c.Vault == nil {
+		return ErrVaultNotConfigured
+	}
+	for i := range c.Bots {
+		bot := &c.Bots[i]
+		if !bot.Encrypted() {
+			continue
+		}
+		if err := bot.DecryptToken(key); err != nil {
+			return err
+		}
+		if err := bot.EncryptToken(newKey, bot.Bot.Token); err != nil {
+			return err
+		}
+	}
+	c.Vault = vault
+	return nil
+}
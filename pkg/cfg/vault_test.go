@@ -0,0 +1,188 @@
+package cfg
+
+import (
+	"encoding/base64"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestVaultRoundTrip(t *testing.T) {
+	vault, key, err := NewVault([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{Vault: vault, Bots: []BotConfig{{Name: "main"}}}
+	if err := config.Bots[0].EncryptToken(key, "bot-token"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := Save(path, config); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot := &loaded.Bots[0]
+	if !bot.Encrypted() || bot.Bot.Token != "" {
+		t.Fatalf("saved bot = %+v, want only an encrypted token", bot.Bot)
+	}
+	key, err = loaded.Vault.Unlock([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := bot.DecryptToken(key); err != nil {
+		t.Fatalf("DecryptToken: %v", err)
+	}
+	if bot.Bot.Token != "bot-token" {
+		t.Errorf("token = %q, want %q", bot.Bot.Token, "bot-token")
+	}
+}
+
+func TestVaultWrongPassphrase(t *testing.T) {
+	vault, _, err := NewVault([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, passphrase := range []string{"Secret", "secret ", ""} {
+		if _, err := vault.Unlock([]byte(passphrase)); !errors.Is(err, ErrWrongPassphrase) {
+			t.Errorf("Unlock(%q) = %v, want ErrWrongPassphrase", passphrase, err)
+		}
+	}
+}
+
+func TestRekey(t *testing.T) {
+	vault, key, err := NewVault([]byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := map[string]string{"main": "main-token", "staging": "staging-token"}
+	config := &Config{
+		Vault: vault,
+		Bots: []BotConfig{
+			{Name: "main"},
+			{Name: "plain", Bot: Bot{TokenEnv: "DCCLI_TOKEN"}},
+			{Name: "staging"},
+		},
+	}
+	for i := range config.Bots {
+		if token, ok := tokens[config.Bots[i].Name]; ok {
+			if err := config.Bots[i].EncryptToken(key, token); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	newVault, newKey, err := NewVault([]byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Rekey(key, newVault, newKey); err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+	if config.Vault != newVault {
+		t.Error("vault was not replaced")
+	}
+	if _, err := config.Vault.Unlock([]byte("new")); err != nil {
+		t.Errorf("Unlock with the new passphrase: %v", err)
+	}
+	for _, bot := range config.Bots {
+		token, ok := tokens[bot.Name]
+		if !ok {
+			if bot.Encrypted() || bot.Bot.TokenEnv != "DCCLI_TOKEN" {
+				t.Errorf("bot %s = %+v, want it untouched", bot.Name, bot.Bot)
+			}
+			continue
+		}
+		if bot.Bot.Token != "" {
+			t.Errorf("bot %s kept a plaintext token", bot.Name)
+		}
+		if _, err := key.Decrypt(bot.Bot.EncryptedToken); err == nil {
+			t.Errorf("bot %s still opens with the old key", bot.Name)
+		}
+		if err := bot.DecryptToken(newKey); err != nil {
+			t.Errorf("bot %s: %v", bot.Name, err)
+		} else if bot.Bot.Token != token {
+			t.Errorf("bot %s token = %q, want %q", bot.Name, bot.Bot.Token, token)
+		}
+	}
+}
+
+func TestRekeyNotConfigured(t *testing.T) {
+	vault, key, err := NewVault([]byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Config{}).Rekey(key, vault, key); !errors.Is(err, ErrVaultNotConfigured) {
+		t.Errorf("Rekey = %v, want ErrVaultNotConfigured", err)
+	}
+}
+
+func TestVaultTampered(t *testing.T) {
+	vault, key, err := NewVault([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := key.Encrypt("bot-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flip := func(i int) string {
+		b := append([]byte(nil), sealed...)
+		b[i] ^= 1
+		return base64.StdEncoding.EncodeToString(b)
+	}
+
+	values := map[string]string{
+		"nonce":      flip(0),
+		"ciphertext": flip(len(sealed) / 2),
+		"tag":        flip(len(sealed) - 1),
+		"truncated":  base64.StdEncoding.EncodeToString(sealed[:len(sealed)-1]),
+		"too short":  base64.StdEncoding.EncodeToString(sealed[:4]),
+		"not base64": "!" + encrypted,
+	}
+	for name, value := range values {
+		if token, err := key.Decrypt(value); err == nil {
+			t.Errorf("%s: Decrypt = %q, want an error", name, token)
+		}
+	}
+
+	// A tampered check value makes the right passphrase fail too
+	vault.Check = flip(len(sealed) - 1)
+	if _, err := vault.Unlock([]byte("secret")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Unlock with tampered check = %v, want ErrWrongPassphrase", err)
+	}
+}
+
+func TestVaultValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(v *Vault)
+	}{
+		{"kdf", func(v *Vault) { v.KDF = "scrypt" }},
+		{"zero time", func(v *Vault) { v.Time = 0 }},
+		{"huge time", func(v *Vault) { v.Time = vaultMaxTime + 1 }},
+		{"zero threads", func(v *Vault) { v.Threads = 0 }},
+		{"low memory", func(v *Vault) { v.Memory = 1 }},
+		{"huge memory", func(v *Vault) { v.Memory = vaultMaxMemory + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := Vault{KDF: vaultKDF, Time: vaultTime, Memory: vaultMemory, Threads: vaultThreads}
+			tt.tamper(&vault)
+			if err := vault.Validate(); err == nil {
+				t.Error("Validate passed")
+			}
+			// Derivation must refuse rather than panic or allocate
+			if _, err := vault.Unlock([]byte("secret")); err == nil || errors.Is(err, ErrWrongPassphrase) {
+				t.Errorf("Unlock = %v, want a settings error", err)
+			}
+		})
+	}
+}
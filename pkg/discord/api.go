@@ -0,0 +1,143 @@
+package discord
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/voice"
+)
+
+// DiscordAPI is the set of operations commands perform against Discord.
+// DiscordClient is the default implementation; tools embedding the command
+// tree can wrap it (caching, auditing) or replace it with a mock.
+type DiscordAPI interface {
+	// Gateway lifecycle
+	Connect() error
+	Connected() bool
+	Close() error
+	AddMessageHandler(handler func(*discordgo.MessageCreate)) func()
+
+	// Voice
+	JoinVoiceChannel(guildID, channelID string, mute, deaf bool) (*voice.Connection, error)
+	LeaveVoiceChannel(guildID string) error
+	GetVoiceConnection(guildID string) (*voice.Connection, bool)
+	GetVoiceRegions() ([]*discordgo.VoiceRegion, error)
+
+	// Applications and commands
+	GetApplications() ([]DiscordApplication, error)
+	GetCurrentApplication() (*discordgo.Application, error)
+	GetComands(appID, guildID string) ([]DiscordCommand, error)
+	GetCurrentAppGlobalCommands() ([]DiscordCommand, error)
+	GetCurrentAppID() (string, error)
+	RemoveCurrentAppCommand(guildID, commandID string) error
+	RemoveGuildCommand(appID, commandID, guildID string) error
+	DescribeCommand(appID, commandID, guildID string) (*DiscordCommandDescription, error)
+	DescribeCurrentAppCommand(guildID, commandID string) (*DiscordCommandDescription, error)
+	CreateCommand(appID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error)
+	CreateGuildCommand(appID, guildID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error)
+	CreateCurrentAppCommand(guildID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error)
+	EditCommand(appID, guildID, commandID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error)
+	EditCurrentAppCommand(guildID, commandID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error)
+	DeleteAllCommands(appID, guildID string) error
+	DeleteAllCurrentAppCommands(guildID string) error
+	BulkOverwriteCommands(appID, guildID string, cmds []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error)
+	BulkOverwriteCurrentAppCommands(guildID string, cmds []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error)
+	GetCommandPermissions(appID, guildID, commandID string) (*discordgo.GuildApplicationCommandPermissions, error)
+	GetCurrentAppCommandPermissions(guildID, commandID string) (*discordgo.GuildApplicationCommandPermissions, error)
+	SetCommandPermissions(appID, guildID, commandID string, permissions *discordgo.ApplicationCommandPermissionsList) (*discordgo.GuildApplicationCommandPermissions, error)
+	SetCurrentAppCommandPermissions(guildID, commandID string, permissions *discordgo.ApplicationCommandPermissionsList) (*discordgo.GuildApplicationCommandPermissions, error)
+
+	// Guilds
+	GuildList(limit int, beforeID, afterID string) ([]DiscordGuild, error)
+	GuildDescribe(id string) (*DiscordGuildDescription, error)
+	LeaveGuild(guildID string) error
+	EditGuild(guildID string, params *discordgo.GuildParams) (*discordgo.Guild, error)
+	GetGuildAuditLog(guildID string, filter AuditLogFilter) (*discordgo.GuildAuditLog, error)
+
+	// Channels
+	GetGuildChannels(guildID string) ([]*discordgo.Channel, error)
+	GetChannel(channelID string) (*discordgo.Channel, error)
+	CreateGuildChannel(guildID string, data *discordgo.GuildChannelCreateData) (*discordgo.Channel, error)
+	EditChannel(channelID string, data *discordgo.ChannelEdit) (*discordgo.Channel, error)
+	DeleteChannel(channelID string) error
+
+	// Roles
+	GetGuildRoles(guildID string) ([]*discordgo.Role, error)
+	GetGuildRole(guildID, roleID string) (*discordgo.Role, error)
+	CreateGuildRole(guildID string, params *discordgo.RoleParams) (*discordgo.Role, error)
+	EditGuildRole(guildID, roleID string, params *discordgo.RoleParams) (*discordgo.Role, error)
+	DeleteGuildRole(guildID, roleID string) error
+
+	// Members and bans
+	GetGuildMembers(guildID string, limit int, after string) ([]*discordgo.Member, error)
+	GetGuildMember(guildID, userID string) (*discordgo.Member, error)
+	SearchGuildMembers(guildID, query string, limit int) ([]*discordgo.Member, error)
+	GetGuildBans(guildID string, limit int, before, after string) ([]*discordgo.GuildBan, error)
+	GuildBanCreate(guildID, userID string, deleteDays int, reason string) error
+	GuildBanDelete(guildID, userID string) error
+	GuildMemberDelete(guildID, userID string) error
+	GuildMemberRoleAdd(guildID, userID, roleID string) error
+	GuildMemberRoleRemove(guildID, userID, roleID string) error
+	GuildMemberTimeout(guildID, userID string, until *time.Time) error
+	GuildMemberNickname(guildID, userID, nick string) error
+
+	// Invites
+	GetGuildInvites(guildID string) ([]*discordgo.Invite, error)
+	GetChannelInvites(channelID string) ([]*discordgo.Invite, error)
+	CreateChannelInvite(channelID string, maxAge, maxUses int, temporary, unique bool) (*discordgo.Invite, error)
+	GetInvite(inviteCode string) (*discordgo.Invite, error)
+	DeleteInvite(inviteCode string) error
+
+	// Messages
+	GetChannelMessages(channelID string, limit int, before, after, around string) ([]*discordgo.Message, error)
+	GetChannelMessage(channelID, messageID string) (*discordgo.Message, error)
+	SendChannelMessage(channelID string, msg *discordgo.MessageSend) (*discordgo.Message, error)
+	EditChannelMessage(channelID, messageID string, msg *discordgo.MessageEdit) (*discordgo.Message, error)
+	DeleteChannelMessage(channelID, messageID string) error
+	BulkDeleteMessages(channelID string, messageIDs []string) error
+	AddReaction(channelID, messageID, emoji string) error
+	RemoveReaction(channelID, messageID, emoji, userID string) error
+
+	// Webhooks
+	GetChannelWebhooks(channelID string) ([]*discordgo.Webhook, error)
+	GetGuildWebhooks(guildID string) ([]*discordgo.Webhook, error)
+	GetWebhook(webhookID string) (*discordgo.Webhook, error)
+	CreateWebhook(channelID, name, avatar string) (*discordgo.Webhook, error)
+	EditWebhook(webhookID, name, avatar, channelID string) (*discordgo.Webhook, error)
+	DeleteWebhook(webhookID string) error
+	ExecuteWebhook(webhookID, token string, wait bool, params *discordgo.WebhookParams) (*discordgo.Message, error)
+
+	// Users
+	GetCurrentUser() (*discordgo.User, error)
+	GetUserConnections() ([]*discordgo.UserConnection, error)
+
+	// Emoji and stickers
+	GetGuildEmojis(guildID string) ([]*discordgo.Emoji, error)
+	GetGuildEmoji(guildID, emojiID string) (*discordgo.Emoji, error)
+	CreateGuildEmoji(guildID string, params *discordgo.EmojiParams) (*discordgo.Emoji, error)
+	EditGuildEmoji(guildID, emojiID string, params *discordgo.EmojiParams) (*discordgo.Emoji, error)
+	DeleteGuildEmoji(guildID, emojiID string) error
+	GetGuildStickers(guildID string) ([]*discordgo.Sticker, error)
+	GetGuildSticker(guildID, stickerID string) (*discordgo.Sticker, error)
+	CreateGuildSticker(guildID, name, description, tags, filePath string) (*discordgo.Sticker, error)
+	EditGuildSticker(guildID, stickerID, name, description, tags string) (*discordgo.Sticker, error)
+	DeleteGuildSticker(guildID, stickerID string) error
+
+	// Scheduled events
+	GetGuildEvents(guildID string) ([]*discordgo.GuildScheduledEvent, error)
+	GetGuildEvent(guildID, eventID string) (*discordgo.GuildScheduledEvent, error)
+	CreateGuildEvent(guildID string, params *discordgo.GuildScheduledEventParams) (*discordgo.GuildScheduledEvent, error)
+	EditGuildEvent(guildID, eventID string, params *discordgo.GuildScheduledEventParams) (*discordgo.GuildScheduledEvent, error)
+	DeleteGuildEvent(guildID, eventID string) error
+	GetGuildEventUsers(guildID, eventID string, limit int, before, after string) ([]*discordgo.GuildScheduledEventUser, error)
+
+	// Auto moderation
+	GetAutoModRules(guildID string) ([]*discordgo.AutoModerationRule, error)
+	GetAutoModRule(guildID, ruleID string) (*discordgo.AutoModerationRule, error)
+	CreateAutoModRule(guildID string, rule *discordgo.AutoModerationRule) (*discordgo.AutoModerationRule, error)
+	EditAutoModRule(guildID, ruleID string, rule *discordgo.AutoModerationRule) (*discordgo.AutoModerationRule, error)
+	DeleteAutoModRule(guildID, ruleID string) error
+}
+
+var _ DiscordAPI = (*DiscordClient)(nil)
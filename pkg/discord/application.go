@@ -0,0 +1,43 @@
+package discord
+
+// Application flags for the privileged gateway intents. The limited variants
+// are set instead for unverified bots in fewer than 100 guilds.
+const (
+	appFlagGatewayPresence              = 1 << 12
+	appFlagGatewayPresenceLimited       = 1 << 13
+	appFlagGatewayGuildMembers          = 1 << 14
+	appFlagGatewayGuildMembersLimited   = 1 << 15
+	appFlagGatewayMessageContent        = 1 << 18
+	appFlagGatewayMessageContentLimited = 1 << 19
+)
+
+// privilegedIntents maps intent names to the application flags enabling them
+var privilegedIntents = []struct {
+	name  string
+	flags int
+}{
+	{"GUILD_PRESENCES", appFlagGatewayPresence | appFlagGatewayPresenceLimited},
+	{"GUILD_MEMBERS", appFlagGatewayGuildMembers | appFlagGatewayGuildMembersLimited},
+	{"MESSAGE_CONTENT", appFlagGatewayMessageContent | appFlagGatewayMessageContentLimited},
+}
+
+// PrivilegedIntents returns the names of the privileged gateway intents
+// enabled in the flags of an application
+func PrivilegedIntents(flags int) []string {
+	intents := []string{}
+	for _, intent := range privilegedIntents {
+		if flags&intent.flags != 0 {
+			intents = append(intents, intent.name)
+		}
+	}
+	return intents
+}
+
+// WithUserToken sends the token as is instead of as a bot token. It exists
+// to tell user tokens apart from invalid ones; dccli itself only supports
+// bot accounts.
+func WithUserToken() ClientOption {
+	return func(o *clientOptions) {
+		o.userToken = true
+	}
+}
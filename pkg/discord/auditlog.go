@@ -0,0 +1,186 @@
+package discord
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// auditLogPageSize is the maximum number of entries Discord returns per request
+const auditLogPageSize = 100
+
+// AuditLogFilter narrows a guild audit log query
+type AuditLogFilter struct {
+	UserID     string
+	ActionType discordgo.AuditLogAction // 0 for every action
+	Before     string                   // only entries older than this entry ID
+	Since      time.Time                // only entries created at or after this time
+	Until      time.Time                // only entries created before this time
+	Limit      int                      // maximum number of entries, 0 for the whole history
+}
+
+// GetGuildAuditLog pages backwards through a guild audit log, newest entry
+// first, until the filter's limit or time range is exhausted. Users, webhooks
+// and integrations referenced by the entries are merged across pages.
+func (c *DiscordClient) GetGuildAuditLog(guildID string, filter AuditLogFilter) (*discordgo.GuildAuditLog, error) {
+	result := &discordgo.GuildAuditLog{
+		AuditLogEntries: []*discordgo.AuditLogEntry{},
+	}
+	seenUsers := make(map[string]bool)
+	seenWebhooks := make(map[string]bool)
+	seenIntegrations := make(map[string]bool)
+
+	before := filter.Before
+	if !filter.Until.IsZero() {
+		untilID := snowflake.FromTime(filter.Until)
+		if before == "" || snowflake.Less(untilID, before) {
+			before = untilID
+		}
+	}
+
+	for {
+		pageSize := auditLogPageSize
+		if filter.Limit > 0 && filter.Limit-len(result.AuditLogEntries) < pageSize {
+			pageSize = filter.Limit - len(result.AuditLogEntries)
+		}
+
+		page, err := c.session.GuildAuditLog(guildID, filter.UserID, before, int(filter.ActionType), pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range page.Users {
+			if !seenUsers[user.ID] {
+				seenUsers[user.ID] = true
+				result.Users = append(result.Users, user)
+			}
+		}
+		for _, webhook := range page.Webhooks {
+			if !seenWebhooks[webhook.ID] {
+				seenWebhooks[webhook.ID] = true
+				result.Webhooks = append(result.Webhooks, webhook)
+			}
+		}
+		for _, integration := range page.Integrations {
+			if !seenIntegrations[integration.ID] {
+				seenIntegrations[integration.ID] = true
+				result.Integrations = append(result.Integrations, integration)
+			}
+		}
+
+		for _, entry := range page.AuditLogEntries {
+			if !filter.Since.IsZero() {
+				if created, err := snowflake.Time(entry.ID); err == nil && created.Before(filter.Since) {
+					return result, nil
+				}
+			}
+			result.AuditLogEntries = append(result.AuditLogEntries, entry)
+			before = entry.ID
+		}
+
+		if len(page.AuditLogEntries) < pageSize {
+			return result, nil
+		}
+		if filter.Limit > 0 && len(result.AuditLogEntries) >= filter.Limit {
+			return result, nil
+		}
+	}
+}
+
+// auditLogActionNames maps actions to the names used in Discord's documentation
+var auditLogActionNames = map[discordgo.AuditLogAction]string{
+	discordgo.AuditLogActionGuildUpdate:                             "GUILD_UPDATE",
+	discordgo.AuditLogActionChannelCreate:                           "CHANNEL_CREATE",
+	discordgo.AuditLogActionChannelUpdate:                           "CHANNEL_UPDATE",
+	discordgo.AuditLogActionChannelDelete:                           "CHANNEL_DELETE",
+	discordgo.AuditLogActionChannelOverwriteCreate:                  "CHANNEL_OVERWRITE_CREATE",
+	discordgo.AuditLogActionChannelOverwriteUpdate:                  "CHANNEL_OVERWRITE_UPDATE",
+	discordgo.AuditLogActionChannelOverwriteDelete:                  "CHANNEL_OVERWRITE_DELETE",
+	discordgo.AuditLogActionMemberKick:                              "MEMBER_KICK",
+	discordgo.AuditLogActionMemberPrune:                             "MEMBER_PRUNE",
+	discordgo.AuditLogActionMemberBanAdd:                            "MEMBER_BAN_ADD",
+	discordgo.AuditLogActionMemberBanRemove:                         "MEMBER_BAN_REMOVE",
+	discordgo.AuditLogActionMemberUpdate:                            "MEMBER_UPDATE",
+	discordgo.AuditLogActionMemberRoleUpdate:                        "MEMBER_ROLE_UPDATE",
+	discordgo.AuditLogActionMemberMove:                              "MEMBER_MOVE",
+	discordgo.AuditLogActionMemberDisconnect:                        "MEMBER_DISCONNECT",
+	discordgo.AuditLogActionBotAdd:                                  "BOT_ADD",
+	discordgo.AuditLogActionRoleCreate:                              "ROLE_CREATE",
+	discordgo.AuditLogActionRoleUpdate:                              "ROLE_UPDATE",
+	discordgo.AuditLogActionRoleDelete:                              "ROLE_DELETE",
+	discordgo.AuditLogActionInviteCreate:                            "INVITE_CREATE",
+	discordgo.AuditLogActionInviteUpdate:                            "INVITE_UPDATE",
+	discordgo.AuditLogActionInviteDelete:                            "INVITE_DELETE",
+	discordgo.AuditLogActionWebhookCreate:                           "WEBHOOK_CREATE",
+	discordgo.AuditLogActionWebhookUpdate:                           "WEBHOOK_UPDATE",
+	discordgo.AuditLogActionWebhookDelete:                           "WEBHOOK_DELETE",
+	discordgo.AuditLogActionEmojiCreate:                             "EMOJI_CREATE",
+	discordgo.AuditLogActionEmojiUpdate:                             "EMOJI_UPDATE",
+	discordgo.AuditLogActionEmojiDelete:                             "EMOJI_DELETE",
+	discordgo.AuditLogActionMessageDelete:                           "MESSAGE_DELETE",
+	discordgo.AuditLogActionMessageBulkDelete:                       "MESSAGE_BULK_DELETE",
+	discordgo.AuditLogActionMessagePin:                              "MESSAGE_PIN",
+	discordgo.AuditLogActionMessageUnpin:                            "MESSAGE_UNPIN",
+	discordgo.AuditLogActionIntegrationCreate:                       "INTEGRATION_CREATE",
+	discordgo.AuditLogActionIntegrationUpdate:                       "INTEGRATION_UPDATE",
+	discordgo.AuditLogActionIntegrationDelete:                       "INTEGRATION_DELETE",
+	discordgo.AuditLogActionStageInstanceCreate:                     "STAGE_INSTANCE_CREATE",
+	discordgo.AuditLogActionStageInstanceUpdate:                     "STAGE_INSTANCE_UPDATE",
+	discordgo.AuditLogActionStageInstanceDelete:                     "STAGE_INSTANCE_DELETE",
+	discordgo.AuditLogActionStickerCreate:                           "STICKER_CREATE",
+	discordgo.AuditLogActionStickerUpdate:                           "STICKER_UPDATE",
+	discordgo.AuditLogActionStickerDelete:                           "STICKER_DELETE",
+	discordgo.AuditLogGuildScheduledEventCreate:                     "GUILD_SCHEDULED_EVENT_CREATE",
+	discordgo.AuditLogGuildScheduledEventUpdate:                     "GUILD_SCHEDULED_EVENT_UPDATE",
+	discordgo.AuditLogGuildScheduledEventDelete:                     "GUILD_SCHEDULED_EVENT_DELETE",
+	discordgo.AuditLogActionThreadCreate:                            "THREAD_CREATE",
+	discordgo.AuditLogActionThreadUpdate:                            "THREAD_UPDATE",
+	discordgo.AuditLogActionThreadDelete:                            "THREAD_DELETE",
+	discordgo.AuditLogActionApplicationCommandPermissionUpdate:      "APPLICATION_COMMAND_PERMISSION_UPDATE",
+	discordgo.AuditLogActionAutoModerationRuleCreate:                "AUTO_MODERATION_RULE_CREATE",
+	discordgo.AuditLogActionAutoModerationRuleUpdate:                "AUTO_MODERATION_RULE_UPDATE",
+	discordgo.AuditLogActionAutoModerationRuleDelete:                "AUTO_MODERATION_RULE_DELETE",
+	discordgo.AuditLogActionAutoModerationBlockMessage:              "AUTO_MODERATION_BLOCK_MESSAGE",
+	discordgo.AuditLogActionAutoModerationFlagToChannel:             "AUTO_MODERATION_FLAG_TO_CHANNEL",
+	discordgo.AuditLogActionAutoModerationUserCommunicationDisabled: "AUTO_MODERATION_USER_COMMUNICATION_DISABLED",
+}
+
+// AuditLogActionName returns the documented name of an audit log action,
+// or its number for actions this version does not know about
+func AuditLogActionName(action discordgo.AuditLogAction) string {
+	if name, ok := auditLogActionNames[action]; ok {
+		return name
+	}
+	return strconv.Itoa(int(action))
+}
+
+// ParseAuditLogAction accepts an action name (MEMBER_BAN_ADD, member-ban-add)
+// or its number
+func ParseAuditLogAction(s string) (discordgo.AuditLogAction, error) {
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return discordgo.AuditLogAction(n), nil
+	}
+	name := strings.ToUpper(strings.ReplaceAll(s, "-", "_"))
+	for action, actionName := range auditLogActionNames {
+		if actionName == name {
+			return action, nil
+		}
+	}
+	return 0, errors.Errorf("unknown audit log action: %s", s)
+}
+
+// AuditLogActionNames returns every known action name
+func AuditLogActionNames() []string {
+	names := make([]string, 0, len(auditLogActionNames))
+	for _, name := range auditLogActionNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
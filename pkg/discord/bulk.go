@@ -0,0 +1,276 @@
+package discord
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Statuses of a target in a BulkReport
+const (
+	BulkSucceeded = "succeeded"
+	BulkFailed    = "failed"
+	BulkSkipped   = "skipped" // Done by an earlier run, per the checkpoint
+	BulkPending   = "pending" // Not attempted before the run was interrupted
+)
+
+// maxRateLimitRetries is how often a batch is retried after Discord rate
+// limited it despite the session's bucket tracking
+const maxRateLimitRetries = 5
+
+// BulkDeleteMaxAge is the age past which Discord refuses to bulk delete a
+// message; older ones have to be deleted one by one
+const BulkDeleteMaxAge = 14 * 24 * time.Hour
+
+// ErrInterrupted is returned by BulkExecutor.Run when its context was
+// cancelled before every target was attempted
+var ErrInterrupted = errors.New("interrupted")
+
+// BulkExecutor runs one operation over many targets, such as assigning a role
+// to many members. Batches run concurrently through the client's session,
+// whose rate limiter keeps requests to the same route bucket in order and
+// waits out their limits, so concurrency only speeds up independent routes.
+// A failed target does not stop the others.
+type BulkExecutor struct {
+	// Concurrency is the number of batches in flight, at least 1
+	Concurrency int
+	// BatchSize is the number of targets passed to each call, at least 1
+	BatchSize int
+	// Batchable reports whether a target can share a call with others.
+	// Targets it rejects are passed alone. Every target is batchable when
+	// it is nil.
+	Batchable func(target string) bool
+	// Checkpoint is a file recording every target that succeeded, one per
+	// line. It is left out when empty.
+	Checkpoint string
+	// Resume skips the targets recorded in Checkpoint instead of starting
+	// over
+	Resume bool
+	// Progress is called after every batch with the number of targets
+	// finished so far, how many of them failed and the total
+	Progress func(done, failed, total int)
+}
+
+// BulkReport is the outcome of a bulk run
+type BulkReport struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Skipped   int          `json:"skipped"`
+	Results   []BulkResult `json:"results"`
+}
+
+// BulkResult is the outcome for one target of a bulk run
+type BulkResult struct {
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Err    error  `json:"-"` // The error behind Error
+}
+
+// Run calls do for every target, in batches of BatchSize. On success the
+// checkpoint is removed; otherwise it is kept so a run with Resume only
+// retries what is left. Cancelling ctx stops new batches from starting and
+// returns ErrInterrupted once the running ones finish.
+func (b *BulkExecutor) Run(ctx context.Context, targets []string, do func(ctx context.Context, batch []string) error) (*BulkReport, error) {
+	done := map[string]bool{}
+	if b.Resume && b.Checkpoint != "" {
+		var err error
+		if done, err = readCheckpoint(b.Checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &BulkReport{Total: len(targets), Results: make([]BulkResult, len(targets))}
+	var pending []int
+	for i, target := range targets {
+		report.Results[i] = BulkResult{Target: target, Status: BulkPending}
+		if done[target] {
+			report.Results[i].Status = BulkSkipped
+			report.Skipped++
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	checkpoint, err := b.openCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil {
+		defer checkpoint.Close()
+	}
+
+	batches := make(chan []int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range max(b.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				ids := make([]string, len(batch))
+				for i, index := range batch {
+					ids[i] = targets[index]
+				}
+				err := runWithRetry(ctx, func() error { return do(ctx, ids) })
+
+				mu.Lock()
+				for _, index := range batch {
+					result := &report.Results[index]
+					switch {
+					case errors.Is(err, ErrDryRun):
+						// Nothing was sent, so nothing to checkpoint
+						result.Status = BulkSucceeded
+						report.Succeeded++
+					case err != nil:
+						result.Status = BulkFailed
+						result.Error = err.Error()
+						result.Err = err
+						report.Failed++
+					default:
+						result.Status = BulkSucceeded
+						report.Succeeded++
+						if checkpoint != nil {
+							fmt.Fprintln(checkpoint, result.Target)
+						}
+					}
+				}
+				if b.Progress != nil {
+					b.Progress(report.Succeeded+report.Failed+report.Skipped, report.Failed, report.Total)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	interrupted := false
+dispatch:
+	for _, batch := range b.batches(targets, pending) {
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			interrupted = true
+			break dispatch
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	if interrupted {
+		return report, ErrInterrupted
+	}
+	if report.Failed == 0 && checkpoint != nil {
+		checkpoint.Close()
+		os.Remove(b.Checkpoint)
+	}
+	return report, nil
+}
+
+// batches splits the pending target indexes into batches of BatchSize, in
+// order, putting every target Batchable rejects in a batch of its own
+func (b *BulkExecutor) batches(targets []string, pending []int) [][]int {
+	batchSize := max(b.BatchSize, 1)
+	var batches [][]int
+	var current []int
+	for _, index := range pending {
+		if b.Batchable != nil && !b.Batchable(targets[index]) {
+			batches = append(batches, []int{index})
+			continue
+		}
+		current = append(current, index)
+		if len(current) == batchSize {
+			batches = append(batches, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// openCheckpoint opens the checkpoint for appending, truncating it unless
+// the run resumes
+func (b *BulkExecutor) openCheckpoint() (*os.File, error) {
+	if b.Checkpoint == "" {
+		return nil, nil
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !b.Resume {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(b.Checkpoint, flags, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	return file, nil
+}
+
+// readCheckpoint returns the targets recorded in a checkpoint. A missing
+// checkpoint means nothing was done yet.
+func readCheckpoint(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if target := strings.TrimSpace(scanner.Text()); target != "" {
+			done[target] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return done, nil
+}
+
+// runWithRetry runs call again after the delay Discord asks for when it
+// answers with 429 anyway, e.g. for a shared or global limit
+func runWithRetry(ctx context.Context, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := call()
+		wait, limited := retryAfter(err)
+		if !limited || attempt == maxRateLimitRetries {
+			return err
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// retryAfter reports whether err is a rate limit and how long to wait
+func retryAfter(err error) (time.Duration, bool) {
+	var rateErr *discordgo.RateLimitError
+	if errors.As(err, &rateErr) {
+		return rateErr.RetryAfter, true
+	}
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusTooManyRequests {
+		var body struct {
+			RetryAfter float64 `json:"retry_after"`
+		}
+		json.Unmarshal(restErr.ResponseBody, &body)
+		return time.Duration(body.RetryAfter * float64(time.Second)), true
+	}
+	return 0, false
+}
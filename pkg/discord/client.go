@@ -2,6 +2,8 @@ package discord
 
 import (
 	"fmt"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -12,28 +14,54 @@ import (
 
 var (
 	ErrCannotGetID = errors.New("cannot get application ID")
+	// ErrNotFound is returned when an object looked up in a list is not in it
+	ErrNotFound = errors.New("not found")
 )
 
 type DiscordClient struct {
 	token    string
 	session  *discordgo.Session
 	voiceMgr *voice.ConnectionManager
+
+	mu        sync.Mutex
+	connected bool
+	appID     string
 }
 
-func NewClient(token string) (*DiscordClient, error) {
+// NewClient creates a REST-only client. The gateway session is not opened
+// until Connect is called, so one-shot commands never identify.
+func NewClient(token string, opts ...ClientOption) (*DiscordClient, error) {
 	sess, err := discordgo.New("Bot " + token)
 	if err != nil {
 		return nil, err
 	}
 
-	// Set intents to listen for messages and guild events
-	sess.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsGuilds | discordgo.IntentsMessageContent
-
-	err = sess.Open()
-	if err != nil {
-		return nil, err
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.apiBase != "" || options.gatewayURL != "" {
+		transport, err := newEndpointTransport(sess.Client.Transport, options)
+		if err != nil {
+			return nil, err
+		}
+		sess.Client.Transport = transport
+	}
+	if options.dryRun != nil {
+		sess.Client.Transport = &dryRunTransport{base: baseTransport(sess), handler: options.dryRun}
+	}
+	// Outermost, so dry runs report the reason too
+	if options.reason != "" {
+		sess.Client.Transport = &reasonTransport{base: baseTransport(sess), reason: options.reason}
+	}
+	if options.userToken {
+		sess.Token = token
+		sess.Identify.Token = token
 	}
 
+	// Set intents to listen for messages and guild events
+	sess.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsGuilds | discordgo.IntentsMessageContent | discordgo.IntentsGuildVoiceStates
+
 	client := &DiscordClient{
 		session: sess,
 		token:   token,
@@ -43,9 +71,57 @@ func NewClient(token string) (*DiscordClient, error) {
 	return client, nil
 }
 
-// JoinVoiceChannel joins a voice channel in a guild
-func (c *DiscordClient) JoinVoiceChannel(guildID, channelID string) (*voice.Connection, error) {
-	return c.voiceMgr.Join(guildID, channelID)
+// baseTransport returns the session's current transport for wrapping
+func baseTransport(sess *discordgo.Session) http.RoundTripper {
+	if sess.Client.Transport == nil {
+		return http.DefaultTransport
+	}
+	return sess.Client.Transport
+}
+
+// Connect opens the gateway websocket. It is only needed by event-driven
+// commands (message listeners, voice); calling it more than once is a no-op.
+func (c *DiscordClient) Connect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.connected {
+		return nil
+	}
+	if err := c.session.Open(); err != nil {
+		return errors.Wrap(err, "failed to open gateway session")
+	}
+	c.connected = true
+	return nil
+}
+
+// Connected reports whether the gateway session is open
+func (c *DiscordClient) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// Close leaves all voice channels and closes the gateway session if it was opened
+func (c *DiscordClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.connected {
+		return nil
+	}
+	c.voiceMgr.Close()
+	c.connected = false
+	return c.session.Close()
+}
+
+// JoinVoiceChannel joins a voice channel in a guild and waits until the
+// connection is ready. The gateway is opened first if needed.
+func (c *DiscordClient) JoinVoiceChannel(guildID, channelID string, mute, deaf bool) (*voice.Connection, error) {
+	if err := c.Connect(); err != nil {
+		return nil, err
+	}
+	return c.voiceMgr.Join(guildID, channelID, mute, deaf)
 }
 
 // LeaveVoiceChannel leaves a voice channel in a guild
@@ -58,7 +134,17 @@ func (c *DiscordClient) GetVoiceConnection(guildID string) (*voice.Connection, b
 	return c.voiceMgr.GetConnection(guildID)
 }
 
-// Session returns the underlying discordgo session
+// AddMessageHandler registers a handler for MESSAGE_CREATE gateway events.
+// Events are only delivered once Connect has been called. The returned
+// function removes the handler.
+func (c *DiscordClient) AddMessageHandler(handler func(*discordgo.MessageCreate)) func() {
+	return c.session.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageCreate) {
+		handler(m)
+	})
+}
+
+// Session returns the underlying discordgo session. It is not part of
+// DiscordAPI; commands should not depend on it.
 func (c *DiscordClient) Session() *discordgo.Session {
 	return c.session
 }
@@ -90,10 +176,16 @@ func (c *DiscordClient) GetApplications() ([]DiscordApplication, error) {
 	return applications, nil
 }
 
+// GetCurrentApplication returns the application of the bot the client is
+// authenticated as
+func (c *DiscordClient) GetCurrentApplication() (*discordgo.Application, error) {
+	return c.session.Application("@me")
+}
+
 type DiscordCommand struct {
-	ID           string
-	Name         string
-	OptionsCount int
+	ID           string `table:"ID"`
+	Name         string `table:"Name"`
+	OptionsCount int    `table:"Options"`
 }
 
 func (c *DiscordClient) GetComands(appID, guildID string) ([]DiscordCommand, error) {
@@ -113,16 +205,33 @@ func (c *DiscordClient) GetComands(appID, guildID string) ([]DiscordCommand, err
 }
 
 func (c *DiscordClient) GetCurrentAppGlobalCommands() ([]DiscordCommand, error) {
-	return c.GetComands(c.session.State.User.ID, "")
+	id, err := c.GetCurrentAppID()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetComands(id, "")
 }
 
+// GetCurrentAppID returns the bot's application ID. The gateway state is used
+// when a session is open, otherwise the bot user is fetched over REST once.
 func (c *DiscordClient) GetCurrentAppID() (string, error) {
-	if c.session.State != nil {
-		if c.session.State.User != nil {
-			return c.session.State.User.ID, nil
-		}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.appID != "" {
+		return c.appID, nil
+	}
+	if c.session.State != nil && c.session.State.User != nil {
+		c.appID = c.session.State.User.ID
+		return c.appID, nil
+	}
+
+	user, err := c.session.User("@me")
+	if err != nil {
+		return "", errors.Wrap(ErrCannotGetID, err.Error())
 	}
-	return "", ErrCannotGetID
+	c.appID = user.ID
+	return c.appID, nil
 }
 
 func (c *DiscordClient) RemoveCurrentAppCommand(guildID, commandID string) error {
@@ -144,9 +253,9 @@ type DiscordCommandDescription struct {
 }
 
 type DiscordCommandOption struct {
-	Name        string
-	Required    bool
-	Description string
+	Name        string `table:"Name"`
+	Required    bool   `table:"Required"`
+	Description string `table:"Description"`
 }
 
 func (c *DiscordClient) DescribeCommand(appID, commandID, guildID string) (*DiscordCommandDescription, error) {
@@ -212,11 +321,21 @@ func (c *DiscordClient) DeleteAllCommands(appID, guildID string) error {
 		return err
 	}
 
+	dryRun := false
 	for _, cmd := range cmds {
-		if err := c.session.ApplicationCommandDelete(appID, guildID, cmd.ID); err != nil {
+		err := c.session.ApplicationCommandDelete(appID, guildID, cmd.ID)
+		if errors.Is(err, ErrDryRun) {
+			// Report every delete, not just the first
+			dryRun = true
+			continue
+		}
+		if err != nil {
 			return errors.Wrapf(err, "failed to delete command %s", cmd.ID)
 		}
 	}
+	if dryRun {
+		return ErrDryRun
+	}
 	return nil
 }
 
@@ -273,8 +392,8 @@ func (c *DiscordClient) SetCurrentAppCommandPermissions(guildID, commandID strin
 }
 
 type DiscordGuild struct {
-	ID   string
-	Name string
+	ID   string `table:"ID"`
+	Name string `table:"Name"`
 }
 
 func (c *DiscordClient) GuildList(limit int, beforeID, afterID string) ([]DiscordGuild, error) {
@@ -368,7 +487,7 @@ func (c *DiscordClient) GetGuildRole(guildID, roleID string) (*discordgo.Role, e
 			return role, nil
 		}
 	}
-	return nil, fmt.Errorf("role not found")
+	return nil, fmt.Errorf("role %w", ErrNotFound)
 }
 
 func (c *DiscordClient) CreateGuildRole(guildID string, params *discordgo.RoleParams) (*discordgo.Role, error) {
@@ -391,6 +510,15 @@ func (c *DiscordClient) GetGuildMember(guildID, userID string) (*discordgo.Membe
 	return c.session.GuildMember(guildID, userID)
 }
 
+// SearchGuildMembers returns members whose username or nickname starts with query
+func (c *DiscordClient) SearchGuildMembers(guildID, query string, limit int) ([]*discordgo.Member, error) {
+	return c.session.GuildMembersSearch(guildID, query, limit)
+}
+
+func (c *DiscordClient) GetGuildBans(guildID string, limit int, before, after string) ([]*discordgo.GuildBan, error) {
+	return c.session.GuildBans(guildID, limit, before, after)
+}
+
 func (c *DiscordClient) GuildBanCreate(guildID, userID string, deleteDays int, reason string) error {
 	return c.session.GuildBanCreateWithReason(guildID, userID, reason, deleteDays)
 }
@@ -551,7 +679,7 @@ func (c *DiscordClient) GetGuildSticker(guildID, stickerID string) (*discordgo.S
 			return sticker, nil
 		}
 	}
-	return nil, fmt.Errorf("sticker not found")
+	return nil, fmt.Errorf("sticker %w", ErrNotFound)
 }
 
 func (c *DiscordClient) CreateGuildSticker(guildID, name, description, tags, filePath string) (*discordgo.Sticker, error) {
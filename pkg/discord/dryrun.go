@@ -0,0 +1,118 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+// ErrDryRun is returned for every mutating request made by a dry-run client.
+// The request has been reported to the DryRunHandler and was not sent. There
+// is no response to continue with, so code making several writes in a row
+// stops at the first one and only that one is previewed; bulk operations,
+// which do not depend on earlier responses, report every request. Callers
+// should treat it as success, e.g. with errors.Is.
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// DryRunRequest describes a REST call that a dry-run client refused to send
+type DryRunRequest struct {
+	Method string      `json:"method" yaml:"method"`
+	Route  string      `json:"route" yaml:"route"`
+	Reason string      `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Body   interface{} `json:"body,omitempty" yaml:"body,omitempty"`
+	Files  []string    `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// DryRunHandler receives each request intercepted by a dry-run client
+type DryRunHandler func(*DryRunRequest)
+
+// WithDryRun makes the client report create/edit/delete requests to handler
+// instead of sending them. Read-only requests still go out so commands can
+// resolve their inputs.
+func WithDryRun(handler DryRunHandler) ClientOption {
+	return func(o *clientOptions) {
+		o.dryRun = handler
+	}
+}
+
+// dryRunTransport intercepts every non-GET request
+type dryRunTransport struct {
+	base    http.RoundTripper
+	handler DryRunHandler
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	dr := &DryRunRequest{
+		Method: req.Method,
+		Route:  "/" + strings.TrimPrefix(req.URL.String(), discordgo.EndpointAPI),
+	}
+	if reason := req.Header.Get(auditLogReasonHeader); reason != "" {
+		dr.Reason, _ = url.PathUnescape(reason)
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := dr.setBody(req.Header.Get("Content-Type"), body); err != nil {
+			return nil, err
+		}
+	}
+
+	t.handler(dr)
+	return nil, ErrDryRun
+}
+
+// setBody decodes a JSON or multipart (payload_json + files) request body
+func (r *DryRunRequest) setBody(contentType string, body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if mediaType != "multipart/form-data" {
+		return decodeJSON(body, &r.Body)
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read multipart body")
+		}
+		if part.FormName() != "payload_json" {
+			r.Files = append(r.Files, part.FileName())
+			continue
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return err
+		}
+		if err := decodeJSON(data, &r.Body); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeJSON keeps numbers as json.Number so IDs and bitfields print exactly
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
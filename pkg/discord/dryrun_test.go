@@ -0,0 +1,65 @@
+package discord_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/discord/fake"
+)
+
+// A dry run reports the first mutating request and returns ErrDryRun for
+// it, so a caller making several writes stops there
+func TestDryRunStopsAtFirstWrite(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	guild := srv.AddGuild("Test Guild")
+
+	var reported []*discord.DryRunRequest
+	client, err := discord.NewClient(srv.Token(),
+		discord.WithAPIBase(srv.APIBase()),
+		discord.WithGatewayURL(srv.GatewayURL()),
+		discord.WithAuditLogReason("cleanup"),
+		discord.WithDryRun(func(req *discord.DryRunRequest) { reported = append(reported, req) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads still go out
+	if _, err := client.GetGuildRoles(guild.ID); err != nil {
+		t.Fatalf("GetGuildRoles: %v", err)
+	}
+
+	createAndEdit := func() error {
+		name := "Helper"
+		role, err := client.CreateGuildRole(guild.ID, &discordgo.RoleParams{Name: name})
+		if err != nil {
+			return err
+		}
+		_, err = client.EditGuildRole(guild.ID, role.ID, &discordgo.RoleParams{Name: name})
+		return err
+	}
+	if err := createAndEdit(); !errors.Is(err, discord.ErrDryRun) {
+		t.Fatalf("create and edit = %v, want ErrDryRun", err)
+	}
+
+	if len(reported) != 1 {
+		t.Fatalf("reported %d requests, want only the create", len(reported))
+	}
+	req := reported[0]
+	if req.Method != http.MethodPost || req.Route != "/guilds/"+guild.ID+"/roles" || req.Reason != "cleanup" {
+		t.Errorf("reported %+v", req)
+	}
+	if body, ok := req.Body.(map[string]interface{}); !ok || body["name"] != "Helper" {
+		t.Errorf("body = %#v", req.Body)
+	}
+	for _, r := range srv.Requests() {
+		if r.Method != http.MethodGet {
+			t.Errorf("dry run sent %s %s", r.Method, r.Path)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+// ClientOption configures a DiscordClient
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	apiBase    string
+	gatewayURL string
+	dryRun     DryRunHandler
+	reason     string
+	userToken  bool
+}
+
+// WithAPIBase points REST calls at a Discord-compatible server.
+// The base must include the API version path, e.g. https://spacebar.example/api/v9
+func WithAPIBase(base string) ClientOption {
+	return func(o *clientOptions) {
+		o.apiBase = base
+	}
+}
+
+// WithGatewayURL overrides the websocket gateway URL instead of asking the
+// REST API for it
+func WithGatewayURL(gateway string) ClientOption {
+	return func(o *clientOptions) {
+		o.gatewayURL = gateway
+	}
+}
+
+// endpointTransport rewrites requests built against the hard-wired discordgo
+// endpoints so they reach a configured backend
+type endpointTransport struct {
+	base       http.RoundTripper
+	apiBase    string
+	gatewayURL string
+}
+
+func newEndpointTransport(base http.RoundTripper, opts clientOptions) (*endpointTransport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &endpointTransport{
+		base:       base,
+		gatewayURL: opts.gatewayURL,
+	}
+	if opts.apiBase != "" {
+		u, err := url.Parse(opts.apiBase)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, errors.Errorf("invalid API base URL: %s", opts.apiBase)
+		}
+		t.apiBase = strings.TrimSuffix(opts.apiBase, "/") + "/"
+	}
+	return t, nil
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := req.URL.String()
+
+	if t.gatewayURL != "" && (target == discordgo.EndpointGateway || target == discordgo.EndpointGatewayBot) {
+		return t.gatewayResponse(req)
+	}
+
+	if t.apiBase != "" && strings.HasPrefix(target, discordgo.EndpointAPI) {
+		rewritten, err := url.Parse(t.apiBase + strings.TrimPrefix(target, discordgo.EndpointAPI))
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.URL = rewritten
+		req.Host = rewritten.Host
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// gatewayResponse answers GET /gateway and /gateway/bot locally with the
+// configured gateway URL
+func (t *endpointTransport) gatewayResponse(req *http.Request) (*http.Response, error) {
+	body, err := json.Marshal(discordgo.GatewayBotResponse{
+		URL:    t.gatewayURL,
+		Shards: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// heartbeatInterval is advertised in Hello; clients beat well within tests
+const heartbeatInterval = 41250
+
+// gatewayPayload is a gateway frame in either direction
+type gatewayPayload struct {
+	Op       int             `json:"op"`
+	Type     string          `json:"t,omitempty"`
+	Sequence int64           `json:"s,omitempty"`
+	Data     json.RawMessage `json:"d"`
+}
+
+// gateway serves websocket sessions and fans out dispatch events
+type gateway struct {
+	server   *Server
+	upgrader websocket.Upgrader
+
+	mu    sync.Mutex
+	conns map[*gatewayConn]bool
+}
+
+// gatewayConn is a single identified websocket session
+type gatewayConn struct {
+	ws        *websocket.Conn
+	sessionID string
+
+	mu       sync.Mutex
+	sequence int64
+}
+
+func newGateway(s *Server) *gateway {
+	return &gateway{
+		server: s,
+		conns:  make(map[*gatewayConn]bool),
+	}
+}
+
+// serve upgrades the request and runs the session until the client leaves
+func (g *gateway) serve(w http.ResponseWriter, r *http.Request) {
+	ws, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := &gatewayConn{ws: ws}
+	defer func() {
+		g.mu.Lock()
+		delete(g.conns, conn)
+		g.mu.Unlock()
+		ws.Close()
+	}()
+
+	if err := conn.send(10, "", map[string]int{"heartbeat_interval": heartbeatInterval}); err != nil {
+		return
+	}
+
+	for {
+		var p gatewayPayload
+		if err := ws.ReadJSON(&p); err != nil {
+			return
+		}
+
+		switch p.Op {
+		case 1: // Heartbeat
+			if err := conn.send(11, "", nil); err != nil {
+				return
+			}
+		case 2: // Identify
+			var identify discordgo.Identify
+			json.Unmarshal(p.Data, &identify)
+			if identify.Token != "Bot "+g.server.token && identify.Token != g.server.token {
+				ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(4004, "Authentication failed."))
+				return
+			}
+			if err := g.ready(conn); err != nil {
+				return
+			}
+		case 6: // Resume
+			if err := conn.send(0, "RESUMED", nil); err != nil {
+				return
+			}
+			g.register(conn)
+		}
+	}
+}
+
+// ready sends READY with a snapshot of the server state and starts dispatching
+func (g *gateway) ready(conn *gatewayConn) error {
+	s := g.server
+	s.mu.Lock()
+	conn.sessionID = "session-" + s.nextID()
+	ready := discordgo.Ready{
+		Version:          10,
+		SessionID:        conn.sessionID,
+		User:             s.bot,
+		ResumeGatewayURL: s.GatewayURL(),
+		Guilds:           make([]*discordgo.Guild, 0, len(s.guilds)),
+	}
+	for _, guild := range s.guilds {
+		ready.Guilds = append(ready.Guilds, guild)
+	}
+	sortByID(ready.Guilds, func(g *discordgo.Guild) string { return g.ID })
+	data, err := json.Marshal(ready)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := conn.sendRaw(0, "READY", data); err != nil {
+		return err
+	}
+	g.register(conn)
+	return nil
+}
+
+func (g *gateway) register(conn *gatewayConn) {
+	g.mu.Lock()
+	g.conns[conn] = true
+	g.mu.Unlock()
+}
+
+// dispatch sends an event to every ready session. Write errors are ignored;
+// the read loop notices the broken connection and cleans it up.
+func (g *gateway) dispatch(eventType string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	g.mu.Lock()
+	conns := make([]*gatewayConn, 0, len(g.conns))
+	for conn := range g.conns {
+		conns = append(conns, conn)
+	}
+	g.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.sendRaw(0, eventType, data)
+	}
+}
+
+// close drops every session with a normal closure
+func (g *gateway) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for conn := range g.conns {
+		conn.mu.Lock()
+		conn.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		conn.ws.Close()
+		conn.mu.Unlock()
+		delete(g.conns, conn)
+	}
+}
+
+func (c *gatewayConn) send(op int, eventType string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.sendRaw(op, eventType, data)
+}
+
+func (c *gatewayConn) sendRaw(op int, eventType string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := gatewayPayload{Op: op, Type: eventType, Data: data}
+	if op == 0 {
+		c.sequence++
+		p.Sequence = c.sequence
+	}
+	return c.ws.WriteJSON(p)
+}
@@ -0,0 +1,1389 @@
+package fake
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// Discord JSON error codes returned by the fake server
+const (
+	codeUnknownChannel     = 10003
+	codeUnknownGuild       = 10004
+	codeUnknownInvite      = 10006
+	codeUnknownMember      = 10007
+	codeUnknownMessage     = 10008
+	codeUnknownRole        = 10011
+	codeUnknownUser        = 10013
+	codeUnknownEmoji       = 10014
+	codeUnknownWebhook     = 10015
+	codeUnknownBan         = 10026
+	codeUnknownCommand     = 10063
+	codeMissingPermissions = 50013
+	codeBulkDeleteTooOld   = 50034
+	codeInvalidBody        = 50035
+)
+
+// routes registers REST handlers on the server mux
+func (s *Server) routes() {
+	api := "/api/v" + discordgo.APIVersion
+
+	handle := func(pattern string, h func(http.ResponseWriter, *http.Request)) {
+		method, route, _ := strings.Cut(pattern, " ")
+		s.mux.HandleFunc(method+" "+api+route, func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			h(w, r)
+		})
+	}
+
+	// Gateway discovery
+	handle("GET /gateway", s.getGateway)
+	handle("GET /gateway/bot", s.getGateway)
+
+	// Users
+	handle("GET /users/{user}", s.getUser)
+	handle("GET /users/@me/guilds", s.listUserGuilds)
+	handle("DELETE /users/@me/guilds/{guild}", s.leaveGuild)
+	handle("GET /users/@me/connections", s.listConnections)
+	handle("GET /oauth2/applications", s.listApplications)
+	handle("GET /oauth2/applications/@me", s.getCurrentApplication)
+	handle("GET /voice/regions", s.listVoiceRegions)
+
+	// Guilds
+	handle("GET /guilds/{guild}", s.getGuild)
+	handle("PATCH /guilds/{guild}", s.editGuild)
+	handle("GET /guilds/{guild}/channels", s.listChannels)
+	handle("POST /guilds/{guild}/channels", s.createChannel)
+	handle("GET /guilds/{guild}/roles", s.listRoles)
+	handle("POST /guilds/{guild}/roles", s.createRole)
+	handle("PATCH /guilds/{guild}/roles/{role}", s.editRole)
+	handle("DELETE /guilds/{guild}/roles/{role}", s.deleteRole)
+	handle("GET /guilds/{guild}/members", s.listMembers)
+	handle("GET /guilds/{guild}/members/search", s.searchMembers)
+	handle("GET /guilds/{guild}/members/{user}", s.getMember)
+	handle("PATCH /guilds/{guild}/members/{user}", s.editMember)
+	handle("DELETE /guilds/{guild}/members/{user}", s.kickMember)
+	handle("PUT /guilds/{guild}/members/{user}/roles/{role}", s.addMemberRole)
+	handle("DELETE /guilds/{guild}/members/{user}/roles/{role}", s.removeMemberRole)
+	handle("GET /guilds/{guild}/bans", s.listBans)
+	handle("PUT /guilds/{guild}/bans/{user}", s.createBan)
+	handle("DELETE /guilds/{guild}/bans/{user}", s.deleteBan)
+	handle("GET /guilds/{guild}/invites", s.listGuildInvites)
+	handle("GET /guilds/{guild}/webhooks", s.listGuildWebhooks)
+	handle("GET /guilds/{guild}/emojis", s.listEmojis)
+	handle("POST /guilds/{guild}/emojis", s.createEmoji)
+	handle("GET /guilds/{guild}/emojis/{emoji}", s.getEmoji)
+	handle("PATCH /guilds/{guild}/emojis/{emoji}", s.editEmoji)
+	handle("DELETE /guilds/{guild}/emojis/{emoji}", s.deleteEmoji)
+	handle("GET /guilds/{guild}/audit-logs", s.getAuditLog)
+
+	// Channels and messages
+	handle("GET /channels/{channel}", s.getChannel)
+	handle("PATCH /channels/{channel}", s.editChannel)
+	handle("DELETE /channels/{channel}", s.deleteChannel)
+	handle("GET /channels/{channel}/messages", s.listMessages)
+	handle("POST /channels/{channel}/messages", s.createMessage)
+	handle("POST /channels/{channel}/messages/bulk-delete", s.bulkDeleteMessages)
+	handle("GET /channels/{channel}/messages/{message}", s.getMessage)
+	handle("PATCH /channels/{channel}/messages/{message}", s.editMessage)
+	handle("DELETE /channels/{channel}/messages/{message}", s.deleteMessage)
+	handle("PUT /channels/{channel}/messages/{message}/reactions/{emoji}/{user}", s.addReaction)
+	handle("DELETE /channels/{channel}/messages/{message}/reactions/{emoji}/{user}", s.removeReaction)
+	handle("GET /channels/{channel}/webhooks", s.listChannelWebhooks)
+	handle("POST /channels/{channel}/webhooks", s.createWebhook)
+	handle("GET /channels/{channel}/invites", s.listChannelInvites)
+	handle("POST /channels/{channel}/invites", s.createInvite)
+
+	// Webhooks and invites
+	handle("GET /webhooks/{webhook}", s.getWebhook)
+	handle("PATCH /webhooks/{webhook}", s.editWebhook)
+	handle("DELETE /webhooks/{webhook}", s.deleteWebhook)
+	handle("POST /webhooks/{webhook}/{token}", s.executeWebhook)
+	handle("GET /invites/{code}", s.getInvite)
+	handle("DELETE /invites/{code}", s.deleteInvite)
+
+	// Application commands
+	handle("GET /applications/{app}/commands", s.listCommands)
+	handle("POST /applications/{app}/commands", s.createCommand)
+	handle("PUT /applications/{app}/commands", s.overwriteCommands)
+	handle("GET /applications/{app}/commands/{command}", s.getCommand)
+	handle("PATCH /applications/{app}/commands/{command}", s.editCommand)
+	handle("DELETE /applications/{app}/commands/{command}", s.deleteCommand)
+	handle("GET /applications/{app}/guilds/{guild}/commands", s.listCommands)
+	handle("POST /applications/{app}/guilds/{guild}/commands", s.createCommand)
+	handle("PUT /applications/{app}/guilds/{guild}/commands", s.overwriteCommands)
+	handle("GET /applications/{app}/guilds/{guild}/commands/{command}", s.getCommand)
+	handle("PATCH /applications/{app}/guilds/{guild}/commands/{command}", s.editCommand)
+	handle("DELETE /applications/{app}/guilds/{guild}/commands/{command}", s.deleteCommand)
+	handle("GET /applications/{app}/guilds/{guild}/commands/{command}/permissions", s.getCommandPermissions)
+	handle("PUT /applications/{app}/guilds/{guild}/commands/{command}/permissions", s.editCommandPermissions)
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, 0, "404: Not Found")
+	})
+}
+
+// decode reads a JSON request body into v and reports a 400 on failure
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, "Invalid Form Body")
+		return false
+	}
+	return true
+}
+
+func queryInt(r *http.Request, name string, def int) int {
+	if v, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil {
+		return v
+	}
+	return def
+}
+
+// guild looks up the guild from the {guild} path value
+func (s *Server) guild(w http.ResponseWriter, r *http.Request) (*discordgo.Guild, bool) {
+	g, ok := s.guilds[r.PathValue("guild")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownGuild, "Unknown Guild")
+	}
+	return g, ok
+}
+
+// channel looks up the channel from the {channel} path value
+func (s *Server) channel(w http.ResponseWriter, r *http.Request) (*discordgo.Channel, bool) {
+	c, ok := s.channels[r.PathValue("channel")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+	}
+	return c, ok
+}
+
+// message looks up the message from the {channel} and {message} path values
+func (s *Server) message(w http.ResponseWriter, r *http.Request) (*discordgo.Message, bool) {
+	if _, ok := s.channel(w, r); !ok {
+		return nil, false
+	}
+	m := s.findMessage(r.PathValue("channel"), r.PathValue("message"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return nil, false
+	}
+	return m, true
+}
+
+// member looks up the member from the {guild} and {user} path values
+func (s *Server) member(w http.ResponseWriter, r *http.Request) (*discordgo.Member, bool) {
+	if _, ok := s.guild(w, r); !ok {
+		return nil, false
+	}
+	userID := r.PathValue("user")
+	if userID == "@me" {
+		userID = s.bot.ID
+	}
+	m, ok := s.members[r.PathValue("guild")][userID]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownMember, "Unknown Member")
+	}
+	return m, ok
+}
+
+// Gateway discovery
+
+func (s *Server) getGateway(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, discordgo.GatewayBotResponse{
+		URL:    s.GatewayURL(),
+		Shards: 1,
+	})
+}
+
+// Users
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("user")
+	if id == "@me" {
+		id = s.bot.ID
+	}
+	user, ok := s.users[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownUser, "Unknown User")
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) listUserGuilds(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	for id := range s.guilds {
+		ids = append(ids, id)
+	}
+	sortByID(ids, func(id string) string { return id })
+
+	before, after := r.URL.Query().Get("before"), r.URL.Query().Get("after")
+	limit := queryInt(r, "limit", 200)
+
+	result := []*discordgo.UserGuild{}
+	for _, id := range ids {
+		if before != "" && !snowflake.Less(id, before) {
+			continue
+		}
+		if after != "" && !snowflake.Less(after, id) {
+			continue
+		}
+		g := s.guilds[id]
+		result = append(result, &discordgo.UserGuild{
+			ID:    g.ID,
+			Name:  g.Name,
+			Owner: g.OwnerID == s.bot.ID,
+		})
+	}
+	if before != "" && len(result) > limit {
+		result = result[len(result)-limit:]
+	} else if len(result) > limit {
+		result = result[:limit]
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) leaveGuild(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	guildID := r.PathValue("guild")
+	delete(s.members[guildID], s.bot.ID)
+	delete(s.guilds, guildID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listConnections(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, []*discordgo.UserConnection{})
+}
+
+func (s *Server) listApplications(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, []*discordgo.Application{{
+		ID:   s.bot.ID,
+		Name: s.bot.Username,
+	}})
+}
+
+// getCurrentApplication reports the bot's application with the members and
+// message content intents enabled, as an unverified bot would have them
+func (s *Server) getCurrentApplication(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, &discordgo.Application{
+		ID:    s.bot.ID,
+		Name:  s.bot.Username,
+		Flags: 1<<15 | 1<<19,
+	})
+}
+
+func (s *Server) listVoiceRegions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, []*discordgo.VoiceRegion{
+		{ID: "us-east", Name: "US East"},
+		{ID: "rotterdam", Name: "Rotterdam"},
+	})
+}
+
+// Guilds
+
+func (s *Server) getGuild(w http.ResponseWriter, r *http.Request) {
+	if g, ok := s.guild(w, r); ok {
+		writeJSON(w, http.StatusOK, g)
+	}
+}
+
+func (s *Server) editGuild(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+	var params discordgo.GuildParams
+	if !decode(w, r, &params) {
+		return
+	}
+	if params.Name != "" {
+		g.Name = params.Name
+	}
+	if params.Region != "" {
+		g.Region = params.Region
+	}
+	if params.VerificationLevel != nil {
+		g.VerificationLevel = *params.VerificationLevel
+	}
+	if params.AfkChannelID != "" {
+		g.AfkChannelID = params.AfkChannelID
+	}
+	if params.AfkTimeout != 0 {
+		g.AfkTimeout = params.AfkTimeout
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+func (s *Server) listChannels(w http.ResponseWriter, r *http.Request) {
+	if g, ok := s.guild(w, r); ok {
+		channels := g.Channels
+		if channels == nil {
+			channels = []*discordgo.Channel{}
+		}
+		writeJSON(w, http.StatusOK, channels)
+	}
+}
+
+func (s *Server) createChannel(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	var data discordgo.GuildChannelCreateData
+	if !decode(w, r, &data) {
+		return
+	}
+	ch := s.addChannel(r.PathValue("guild"), &discordgo.Channel{
+		Name:                 data.Name,
+		Type:                 data.Type,
+		Topic:                data.Topic,
+		Bitrate:              data.Bitrate,
+		UserLimit:            data.UserLimit,
+		RateLimitPerUser:     data.RateLimitPerUser,
+		PermissionOverwrites: data.PermissionOverwrites,
+		ParentID:             data.ParentID,
+		NSFW:                 data.NSFW,
+	})
+	writeJSON(w, http.StatusCreated, ch)
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	if g, ok := s.guild(w, r); ok {
+		writeJSON(w, http.StatusOK, g.Roles)
+	}
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	var params discordgo.RoleParams
+	if !decode(w, r, &params) {
+		return
+	}
+	if params.Name == "" {
+		params.Name = "new role"
+	}
+	writeJSON(w, http.StatusOK, s.addRole(r.PathValue("guild"), &params))
+}
+
+func (s *Server) editRole(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	role, _ := s.findRole(r.PathValue("guild"), r.PathValue("role"))
+	if role == nil {
+		writeError(w, http.StatusNotFound, codeUnknownRole, "Unknown Role")
+		return
+	}
+	var params discordgo.RoleParams
+	if !decode(w, r, &params) {
+		return
+	}
+	applyRoleParams(role, &params)
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+	role, idx := s.findRole(g.ID, r.PathValue("role"))
+	if role == nil {
+		writeError(w, http.StatusNotFound, codeUnknownRole, "Unknown Role")
+		return
+	}
+	g.Roles = append(g.Roles[:idx], g.Roles[idx+1:]...)
+	for _, m := range s.members[g.ID] {
+		m.Roles = removeString(m.Roles, role.ID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listMembers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	after := r.URL.Query().Get("after")
+	limit := queryInt(r, "limit", 1)
+
+	var members []*discordgo.Member
+	for _, m := range s.members[r.PathValue("guild")] {
+		if after == "" || snowflake.Less(after, m.User.ID) {
+			members = append(members, m)
+		}
+	}
+	sortByID(members, func(m *discordgo.Member) string { return m.User.ID })
+	if len(members) > limit {
+		members = members[:limit]
+	}
+	if members == nil {
+		members = []*discordgo.Member{}
+	}
+	writeJSON(w, http.StatusOK, members)
+}
+
+// searchMembers matches the start of usernames and nicknames, ignoring case
+func (s *Server) searchMembers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	limit := queryInt(r, "limit", 1)
+
+	members := []*discordgo.Member{}
+	for _, m := range s.members[r.PathValue("guild")] {
+		if strings.HasPrefix(strings.ToLower(m.User.Username), query) || strings.HasPrefix(strings.ToLower(m.Nick), query) {
+			members = append(members, m)
+		}
+	}
+	sortByID(members, func(m *discordgo.Member) string { return m.User.ID })
+	if len(members) > limit {
+		members = members[:limit]
+	}
+	writeJSON(w, http.StatusOK, members)
+}
+
+func (s *Server) getMember(w http.ResponseWriter, r *http.Request) {
+	if m, ok := s.member(w, r); ok {
+		writeJSON(w, http.StatusOK, m)
+	}
+}
+
+func (s *Server) editMember(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.member(w, r)
+	if !ok {
+		return
+	}
+	var fields map[string]json.RawMessage
+	if !decode(w, r, &fields) {
+		return
+	}
+	if raw, ok := fields["nick"]; ok {
+		json.Unmarshal(raw, &m.Nick)
+	}
+	if raw, ok := fields["communication_disabled_until"]; ok {
+		var until *time.Time
+		json.Unmarshal(raw, &until)
+		m.CommunicationDisabledUntil = until
+	}
+	if raw, ok := fields["roles"]; ok {
+		json.Unmarshal(raw, &m.Roles)
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) kickMember(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.member(w, r)
+	if !ok {
+		return
+	}
+	delete(s.members[r.PathValue("guild")], m.User.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addMemberRole(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.member(w, r)
+	if !ok {
+		return
+	}
+	role, _ := s.findRole(r.PathValue("guild"), r.PathValue("role"))
+	if role == nil {
+		writeError(w, http.StatusNotFound, codeUnknownRole, "Unknown Role")
+		return
+	}
+	m.Roles = append(removeString(m.Roles, role.ID), role.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeMemberRole(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.member(w, r)
+	if !ok {
+		return
+	}
+	m.Roles = removeString(m.Roles, r.PathValue("role"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listBans(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	before, after := r.URL.Query().Get("before"), r.URL.Query().Get("after")
+	limit := queryInt(r, "limit", 1000)
+
+	bans := []*discordgo.GuildBan{}
+	for _, b := range s.bans[r.PathValue("guild")] {
+		if before != "" && !snowflake.Less(b.User.ID, before) {
+			continue
+		}
+		if after != "" && !snowflake.Less(after, b.User.ID) {
+			continue
+		}
+		bans = append(bans, b)
+	}
+	sortByID(bans, func(b *discordgo.GuildBan) string { return b.User.ID })
+	if before != "" && after == "" && len(bans) > limit {
+		bans = bans[len(bans)-limit:]
+	} else if len(bans) > limit {
+		bans = bans[:limit]
+	}
+	writeJSON(w, http.StatusOK, bans)
+}
+
+func (s *Server) createBan(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+	guildID, userID := r.PathValue("guild"), r.PathValue("user")
+	user, ok := s.users[userID]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownUser, "Unknown User")
+		return
+	}
+	if userID == g.OwnerID {
+		writeError(w, http.StatusForbidden, codeMissingPermissions, "Missing Permissions")
+		return
+	}
+	reason := r.URL.Query().Get("reason")
+	if reason == "" {
+		reason = r.Header.Get("X-Audit-Log-Reason")
+	}
+	s.bans[guildID][userID] = &discordgo.GuildBan{Reason: reason, User: user}
+	delete(s.members[guildID], userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteBan(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	guildID, userID := r.PathValue("guild"), r.PathValue("user")
+	if _, ok := s.bans[guildID][userID]; !ok {
+		writeError(w, http.StatusNotFound, codeUnknownBan, "Unknown Ban")
+		return
+	}
+	delete(s.bans[guildID], userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listGuildInvites(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	s.writeInvites(w, func(inv *discordgo.Invite) bool { return inv.Guild != nil && inv.Guild.ID == r.PathValue("guild") })
+}
+
+func (s *Server) listGuildWebhooks(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	s.writeWebhooks(w, func(wh *discordgo.Webhook) bool { return wh.GuildID == r.PathValue("guild") })
+}
+
+func (s *Server) listEmojis(w http.ResponseWriter, r *http.Request) {
+	if g, ok := s.guild(w, r); ok {
+		emojis := g.Emojis
+		if emojis == nil {
+			emojis = []*discordgo.Emoji{}
+		}
+		writeJSON(w, http.StatusOK, emojis)
+	}
+}
+
+func (s *Server) findEmoji(w http.ResponseWriter, r *http.Request) (*discordgo.Guild, int) {
+	g, ok := s.guild(w, r)
+	if !ok {
+		return nil, -1
+	}
+	for i, e := range g.Emojis {
+		if e.ID == r.PathValue("emoji") {
+			return g, i
+		}
+	}
+	writeError(w, http.StatusNotFound, codeUnknownEmoji, "Unknown Emoji")
+	return nil, -1
+}
+
+func (s *Server) createEmoji(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+	var params discordgo.EmojiParams
+	if !decode(w, r, &params) {
+		return
+	}
+	emoji := &discordgo.Emoji{
+		ID:        s.nextID(),
+		Name:      params.Name,
+		Roles:     params.Roles,
+		Animated:  strings.HasPrefix(params.Image, "data:image/gif"),
+		Available: true,
+		User:      s.bot,
+	}
+	g.Emojis = append(g.Emojis, emoji)
+	writeJSON(w, http.StatusCreated, emoji)
+}
+
+func (s *Server) getEmoji(w http.ResponseWriter, r *http.Request) {
+	if g, idx := s.findEmoji(w, r); g != nil {
+		writeJSON(w, http.StatusOK, g.Emojis[idx])
+	}
+}
+
+func (s *Server) editEmoji(w http.ResponseWriter, r *http.Request) {
+	g, idx := s.findEmoji(w, r)
+	if g == nil {
+		return
+	}
+	var params discordgo.EmojiParams
+	if !decode(w, r, &params) {
+		return
+	}
+	emoji := g.Emojis[idx]
+	if params.Name != "" {
+		emoji.Name = params.Name
+	}
+	if params.Roles != nil {
+		emoji.Roles = params.Roles
+	}
+	writeJSON(w, http.StatusOK, emoji)
+}
+
+func (s *Server) deleteEmoji(w http.ResponseWriter, r *http.Request) {
+	if g, idx := s.findEmoji(w, r); g != nil {
+		g.Emojis = append(g.Emojis[:idx], g.Emojis[idx+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getAuditLog returns entries newest first, filtered like Discord does
+func (s *Server) getAuditLog(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.guild(w, r); !ok {
+		return
+	}
+	q := r.URL.Query()
+	userID, before := q.Get("user_id"), q.Get("before")
+	actionType := queryInt(r, "action_type", 0)
+	limit := queryInt(r, "limit", 50)
+	if limit < 1 || limit > 100 {
+		limit = 100
+	}
+
+	log := &discordgo.GuildAuditLog{
+		AuditLogEntries: []*discordgo.AuditLogEntry{},
+		Users:           []*discordgo.User{},
+		Webhooks:        []*discordgo.Webhook{},
+		Integrations:    []*discordgo.Integration{},
+	}
+	seen := make(map[string]bool)
+	entries := s.auditLog[r.PathValue("guild")]
+	for i := len(entries) - 1; i >= 0 && len(log.AuditLogEntries) < limit; i-- {
+		e := entries[i]
+		if before != "" && !snowflake.Less(e.ID, before) {
+			continue
+		}
+		if userID != "" && e.UserID != userID {
+			continue
+		}
+		if actionType != 0 && e.ActionType != nil && int(*e.ActionType) != actionType {
+			continue
+		}
+		log.AuditLogEntries = append(log.AuditLogEntries, e)
+		for _, id := range []string{e.UserID, e.TargetID} {
+			if u, ok := s.users[id]; ok && !seen[id] {
+				seen[id] = true
+				log.Users = append(log.Users, u)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, log)
+}
+
+// Channels
+
+func (s *Server) getChannel(w http.ResponseWriter, r *http.Request) {
+	if ch, ok := s.channel(w, r); ok {
+		writeJSON(w, http.StatusOK, ch)
+	}
+}
+
+func (s *Server) editChannel(w http.ResponseWriter, r *http.Request) {
+	ch, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	var data discordgo.ChannelEdit
+	if !decode(w, r, &data) {
+		return
+	}
+	if data.Name != "" {
+		ch.Name = data.Name
+	}
+	if data.Topic != "" {
+		ch.Topic = data.Topic
+	}
+	if data.NSFW != nil {
+		ch.NSFW = *data.NSFW
+	}
+	if data.Position != nil {
+		ch.Position = *data.Position
+	}
+	if data.Bitrate != 0 {
+		ch.Bitrate = data.Bitrate
+	}
+	if data.UserLimit != 0 {
+		ch.UserLimit = data.UserLimit
+	}
+	if data.ParentID != "" {
+		ch.ParentID = data.ParentID
+	}
+	if data.RateLimitPerUser != nil {
+		ch.RateLimitPerUser = *data.RateLimitPerUser
+	}
+	if data.PermissionOverwrites != nil {
+		ch.PermissionOverwrites = data.PermissionOverwrites
+	}
+	writeJSON(w, http.StatusOK, ch)
+}
+
+func (s *Server) deleteChannel(w http.ResponseWriter, r *http.Request) {
+	ch, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	delete(s.channels, ch.ID)
+	delete(s.messages, ch.ID)
+	if g, ok := s.guilds[ch.GuildID]; ok {
+		for i, c := range g.Channels {
+			if c.ID == ch.ID {
+				g.Channels = append(g.Channels[:i], g.Channels[i+1:]...)
+				break
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, ch)
+}
+
+// Messages
+
+// messagePayload is the subset of a message create/execute body the fake understands
+type messagePayload struct {
+	Content          string                      `json:"content"`
+	Embeds           []*discordgo.MessageEmbed   `json:"embeds"`
+	TTS              bool                        `json:"tts"`
+	MessageReference *discordgo.MessageReference `json:"message_reference"`
+	Username         string                      `json:"username"`
+
+	attachments []*discordgo.MessageAttachment
+}
+
+// readMessagePayload decodes a JSON or multipart message body
+func (s *Server) readMessagePayload(w http.ResponseWriter, r *http.Request) (*messagePayload, bool) {
+	var p messagePayload
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if !decode(w, r, &p) {
+			return nil, false
+		}
+		return &p, true
+	}
+
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidBody, "Invalid Form Body")
+			return nil, false
+		}
+		data, _ := io.ReadAll(part)
+		if part.FormName() == "payload_json" {
+			if err := json.Unmarshal(data, &p); err != nil {
+				writeError(w, http.StatusBadRequest, codeInvalidBody, "Invalid Form Body")
+				return nil, false
+			}
+			continue
+		}
+		id := s.nextID()
+		s.files[id] = data
+		p.attachments = append(p.attachments, &discordgo.MessageAttachment{
+			ID:          id,
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Size:        len(data),
+			URL:         s.URL() + "/attachments/" + r.PathValue("channel") + "/" + id + "/" + part.FileName(),
+		})
+	}
+	return &p, true
+}
+
+func (s *Server) listMessages(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.channel(w, r); !ok {
+		return
+	}
+	q := r.URL.Query()
+	before, after, around := q.Get("before"), q.Get("after"), q.Get("around")
+	limit := queryInt(r, "limit", 50)
+
+	// Work oldest-first, then return newest-first like Discord does
+	all := s.messages[r.PathValue("channel")]
+	var page []*discordgo.Message
+	switch {
+	case after != "":
+		for _, m := range all {
+			if snowflake.Less(after, m.ID) && len(page) < limit {
+				page = append(page, m)
+			}
+		}
+	case before != "":
+		for _, m := range all {
+			if snowflake.Less(m.ID, before) {
+				page = append(page, m)
+			}
+		}
+		if len(page) > limit {
+			page = page[len(page)-limit:]
+		}
+	case around != "":
+		idx := 0
+		for i, m := range all {
+			if !snowflake.Less(m.ID, around) {
+				idx = i
+				break
+			}
+		}
+		start := idx - limit/2
+		if start < 0 {
+			start = 0
+		}
+		end := start + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		page = all[start:end]
+	default:
+		page = all
+		if len(page) > limit {
+			page = page[len(page)-limit:]
+		}
+	}
+
+	result := make([]*discordgo.Message, 0, len(page))
+	for i := len(page) - 1; i >= 0; i-- {
+		result = append(result, page[i])
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) createMessage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.channel(w, r); !ok {
+		return
+	}
+	p, ok := s.readMessagePayload(w, r)
+	if !ok {
+		return
+	}
+	if p.Content == "" && len(p.Embeds) == 0 && len(p.attachments) == 0 {
+		writeError(w, http.StatusBadRequest, 50006, "Cannot send an empty message")
+		return
+	}
+	msg := s.addMessage(r.PathValue("channel"), s.bot, p)
+	writeJSON(w, http.StatusOK, msg)
+	s.gateway.dispatch("MESSAGE_CREATE", msg)
+}
+
+func (s *Server) getMessage(w http.ResponseWriter, r *http.Request) {
+	if m, ok := s.message(w, r); ok {
+		writeJSON(w, http.StatusOK, m)
+	}
+}
+
+func (s *Server) editMessage(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	var edit discordgo.MessageEdit
+	if !decode(w, r, &edit) {
+		return
+	}
+	if edit.Content != nil {
+		m.Content = *edit.Content
+	}
+	if edit.Embeds != nil {
+		m.Embeds = *edit.Embeds
+	}
+	now := time.Now().UTC()
+	m.EditedTimestamp = &now
+	writeJSON(w, http.StatusOK, m)
+	s.gateway.dispatch("MESSAGE_UPDATE", m)
+}
+
+func (s *Server) deleteMessage(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	s.removeMessages(m.ChannelID, []string{m.ID})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) bulkDeleteMessages(w http.ResponseWriter, r *http.Request) {
+	ch, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Messages []string `json:"messages"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if len(body.Messages) < 2 || len(body.Messages) > 100 {
+		writeFieldError(w, "messages", "BASE_TYPE_BAD_LENGTH", "Must be between 2 and 100 in length.")
+		return
+	}
+	for _, id := range body.Messages {
+		if created, err := snowflake.Time(id); err == nil && time.Since(created) > discord.BulkDeleteMaxAge {
+			writeError(w, http.StatusBadRequest, codeBulkDeleteTooOld, "You can only bulk delete messages that are under 14 days old.")
+			return
+		}
+	}
+	s.removeMessages(ch.ID, body.Messages)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeMessages(channelID string, ids []string) {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	kept := s.messages[channelID][:0]
+	for _, m := range s.messages[channelID] {
+		if drop[m.ID] {
+			s.gateway.dispatch("MESSAGE_DELETE", &discordgo.Message{ID: m.ID, ChannelID: channelID, GuildID: m.GuildID})
+			continue
+		}
+		kept = append(kept, m)
+	}
+	s.messages[channelID] = kept
+}
+
+func (s *Server) addReaction(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	emoji := r.PathValue("emoji")
+	key := m.ID + "/" + emoji
+	if s.reacted[key] == nil {
+		s.reacted[key] = make(map[string]bool)
+	}
+	if s.reacted[key][s.bot.ID] {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.reacted[key][s.bot.ID] = true
+
+	for _, reaction := range m.Reactions {
+		if reactionKey(reaction.Emoji) == emoji {
+			reaction.Count++
+			reaction.Me = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	name, id, _ := strings.Cut(emoji, ":")
+	m.Reactions = append(m.Reactions, &discordgo.MessageReactions{
+		Count: 1,
+		Me:    true,
+		Emoji: &discordgo.Emoji{Name: name, ID: id},
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeReaction(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	emoji, userID := r.PathValue("emoji"), r.PathValue("user")
+	if userID == "@me" {
+		userID = s.bot.ID
+	}
+	key := m.ID + "/" + emoji
+	if !s.reacted[key][userID] {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	delete(s.reacted[key], userID)
+
+	for i, reaction := range m.Reactions {
+		if reactionKey(reaction.Emoji) != emoji {
+			continue
+		}
+		reaction.Count--
+		if userID == s.bot.ID {
+			reaction.Me = false
+		}
+		if reaction.Count <= 0 {
+			m.Reactions = append(m.Reactions[:i], m.Reactions[i+1:]...)
+		}
+		break
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reactionKey returns the emoji in the form used in reaction routes
+func reactionKey(e *discordgo.Emoji) string {
+	if e.ID == "" {
+		return e.Name
+	}
+	return e.Name + ":" + e.ID
+}
+
+// Webhooks
+
+func (s *Server) writeWebhooks(w http.ResponseWriter, keep func(*discordgo.Webhook) bool) {
+	result := []*discordgo.Webhook{}
+	for _, wh := range s.webhooks {
+		if keep(wh) {
+			result = append(result, wh)
+		}
+	}
+	sortByID(result, func(wh *discordgo.Webhook) string { return wh.ID })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) webhook(w http.ResponseWriter, r *http.Request) (*discordgo.Webhook, bool) {
+	wh, ok := s.webhooks[r.PathValue("webhook")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownWebhook, "Unknown Webhook")
+	}
+	return wh, ok
+}
+
+func (s *Server) listChannelWebhooks(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.channel(w, r); !ok {
+		return
+	}
+	s.writeWebhooks(w, func(wh *discordgo.Webhook) bool { return wh.ChannelID == r.PathValue("channel") })
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	ch, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name   string `json:"name"`
+		Avatar string `json:"avatar"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	id := s.nextID()
+	wh := &discordgo.Webhook{
+		ID:        id,
+		Type:      discordgo.WebhookTypeIncoming,
+		GuildID:   ch.GuildID,
+		ChannelID: ch.ID,
+		User:      s.bot,
+		Name:      body.Name,
+		Avatar:    body.Avatar,
+		Token:     "token-" + id,
+	}
+	s.webhooks[id] = wh
+	writeJSON(w, http.StatusOK, wh)
+}
+
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request) {
+	if wh, ok := s.webhook(w, r); ok {
+		writeJSON(w, http.StatusOK, wh)
+	}
+}
+
+func (s *Server) editWebhook(w http.ResponseWriter, r *http.Request) {
+	wh, ok := s.webhook(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name      string `json:"name"`
+		Avatar    string `json:"avatar"`
+		ChannelID string `json:"channel_id"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.Name != "" {
+		wh.Name = body.Name
+	}
+	if body.Avatar != "" {
+		wh.Avatar = body.Avatar
+	}
+	if body.ChannelID != "" {
+		wh.ChannelID = body.ChannelID
+	}
+	writeJSON(w, http.StatusOK, wh)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if wh, ok := s.webhook(w, r); ok {
+		delete(s.webhooks, wh.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) executeWebhook(w http.ResponseWriter, r *http.Request) {
+	wh, ok := s.webhook(w, r)
+	if !ok {
+		return
+	}
+	if r.PathValue("token") != wh.Token {
+		writeError(w, http.StatusUnauthorized, 50027, "Invalid Webhook Token")
+		return
+	}
+	p, ok := s.readMessagePayload(w, r)
+	if !ok {
+		return
+	}
+	author := &discordgo.User{ID: wh.ID, Username: wh.Name, Bot: true}
+	if p.Username != "" {
+		author.Username = p.Username
+	}
+	msg := s.addMessage(wh.ChannelID, author, p)
+	msg.WebhookID = wh.ID
+	s.gateway.dispatch("MESSAGE_CREATE", msg)
+	if r.URL.Query().Get("wait") == "true" {
+		writeJSON(w, http.StatusOK, msg)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Invites
+
+func (s *Server) writeInvites(w http.ResponseWriter, keep func(*discordgo.Invite) bool) {
+	result := []*discordgo.Invite{}
+	for _, inv := range s.invites {
+		if keep(inv) {
+			result = append(result, inv)
+		}
+	}
+	sortByID(result, func(inv *discordgo.Invite) string { return inv.Code })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) listChannelInvites(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.channel(w, r); !ok {
+		return
+	}
+	s.writeInvites(w, func(inv *discordgo.Invite) bool {
+		return inv.Channel != nil && inv.Channel.ID == r.PathValue("channel")
+	})
+}
+
+func (s *Server) createInvite(w http.ResponseWriter, r *http.Request) {
+	ch, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	var inv discordgo.Invite
+	if !decode(w, r, &inv) {
+		return
+	}
+	id, _ := strconv.ParseUint(s.nextID(), 10, 64)
+	inv.Code = strconv.FormatUint(id, 36)
+	inv.Channel = ch
+	inv.Guild = &discordgo.Guild{ID: ch.GuildID}
+	if g, ok := s.guilds[ch.GuildID]; ok {
+		inv.Guild.Name = g.Name
+	}
+	inv.Inviter = s.bot
+	inv.CreatedAt = time.Now().UTC()
+	s.invites[inv.Code] = &inv
+	writeJSON(w, http.StatusOK, &inv)
+}
+
+func (s *Server) getInvite(w http.ResponseWriter, r *http.Request) {
+	inv, ok := s.invites[r.PathValue("code")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownInvite, "Unknown Invite")
+		return
+	}
+	writeJSON(w, http.StatusOK, inv)
+}
+
+func (s *Server) deleteInvite(w http.ResponseWriter, r *http.Request) {
+	inv, ok := s.invites[r.PathValue("code")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownInvite, "Unknown Invite")
+		return
+	}
+	delete(s.invites, inv.Code)
+	writeJSON(w, http.StatusOK, inv)
+}
+
+// Application commands
+
+func (s *Server) listCommands(w http.ResponseWriter, r *http.Request) {
+	cmds := s.commandsFor(r.PathValue("app"), r.PathValue("guild"))
+	if cmds == nil {
+		cmds = []*discordgo.ApplicationCommand{}
+	}
+	writeJSON(w, http.StatusOK, cmds)
+}
+
+func (s *Server) storeCommand(appID, guildID string, cmd *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	if cmd.ID == "" {
+		// Discord upserts commands by name
+		for _, existing := range s.commandsFor(appID, guildID) {
+			if existing.Name == cmd.Name {
+				cmd.ID = existing.ID
+				break
+			}
+		}
+	}
+	if cmd.ID == "" {
+		cmd.ID = s.nextID()
+	}
+	cmd.ApplicationID = appID
+	cmd.GuildID = guildID
+	cmd.Version = s.nextID()
+	s.commands[commandKey(appID, guildID, cmd.ID)] = cmd
+	return cmd
+}
+
+func (s *Server) createCommand(w http.ResponseWriter, r *http.Request) {
+	var cmd discordgo.ApplicationCommand
+	if !decode(w, r, &cmd) {
+		return
+	}
+	cmd.ID = ""
+	writeJSON(w, http.StatusCreated, s.storeCommand(r.PathValue("app"), r.PathValue("guild"), &cmd))
+}
+
+func (s *Server) overwriteCommands(w http.ResponseWriter, r *http.Request) {
+	var cmds []*discordgo.ApplicationCommand
+	if !decode(w, r, &cmds) {
+		return
+	}
+	appID, guildID := r.PathValue("app"), r.PathValue("guild")
+	for _, existing := range s.commandsFor(appID, guildID) {
+		delete(s.commands, commandKey(appID, guildID, existing.ID))
+	}
+	result := []*discordgo.ApplicationCommand{}
+	for _, cmd := range cmds {
+		cmd.ID = ""
+		result = append(result, s.storeCommand(appID, guildID, cmd))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) command(w http.ResponseWriter, r *http.Request) (*discordgo.ApplicationCommand, bool) {
+	cmd, ok := s.commands[commandKey(r.PathValue("app"), r.PathValue("guild"), r.PathValue("command"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownCommand, "Unknown application command")
+	}
+	return cmd, ok
+}
+
+func (s *Server) getCommand(w http.ResponseWriter, r *http.Request) {
+	if cmd, ok := s.command(w, r); ok {
+		writeJSON(w, http.StatusOK, cmd)
+	}
+}
+
+func (s *Server) editCommand(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := s.command(w, r)
+	if !ok {
+		return
+	}
+	var edit discordgo.ApplicationCommand
+	if !decode(w, r, &edit) {
+		return
+	}
+	if edit.Name != "" {
+		cmd.Name = edit.Name
+	}
+	if edit.Description != "" {
+		cmd.Description = edit.Description
+	}
+	if edit.Options != nil {
+		cmd.Options = edit.Options
+	}
+	cmd.Version = s.nextID()
+	writeJSON(w, http.StatusOK, cmd)
+}
+
+func (s *Server) deleteCommand(w http.ResponseWriter, r *http.Request) {
+	if cmd, ok := s.command(w, r); ok {
+		delete(s.commands, commandKey(cmd.ApplicationID, cmd.GuildID, cmd.ID))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) getCommandPermissions(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := s.command(w, r)
+	if !ok {
+		return
+	}
+	perms, ok := s.perms[commandKey(cmd.ApplicationID, cmd.GuildID, cmd.ID)]
+	if !ok {
+		perms = &discordgo.GuildApplicationCommandPermissions{
+			ID:            cmd.ID,
+			ApplicationID: cmd.ApplicationID,
+			GuildID:       cmd.GuildID,
+			Permissions:   []*discordgo.ApplicationCommandPermissions{},
+		}
+	}
+	writeJSON(w, http.StatusOK, perms)
+}
+
+func (s *Server) editCommandPermissions(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := s.command(w, r)
+	if !ok {
+		return
+	}
+	var list discordgo.ApplicationCommandPermissionsList
+	if !decode(w, r, &list) {
+		return
+	}
+	perms := &discordgo.GuildApplicationCommandPermissions{
+		ID:            cmd.ID,
+		ApplicationID: cmd.ApplicationID,
+		GuildID:       cmd.GuildID,
+		Permissions:   list.Permissions,
+	}
+	s.perms[commandKey(cmd.ApplicationID, cmd.GuildID, cmd.ID)] = perms
+	writeJSON(w, http.StatusOK, perms)
+}
+
+func removeString(items []string, value string) []string {
+	result := items[:0]
+	for _, item := range items {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,576 @@
+// Package fake provides an in-memory stand-in for the Discord REST API and
+// gateway. It is served with httptest and is meant for exercising dccli
+// commands, or scripts wrapping them, without a network connection.
+//
+//	srv := fake.NewServer()
+//	defer srv.Close()
+//	guild := srv.AddGuild("Test Guild")
+//	client, _ := srv.NewClient()
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// DefaultToken is the bot token accepted by a server created with NewServer
+const DefaultToken = "fake.bot.token"
+
+// Request is a REST call recorded by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// Server is an in-memory Discord REST API and gateway
+type Server struct {
+	srv   *httptest.Server
+	mux   *http.ServeMux
+	token string
+
+	mu       sync.Mutex
+	lastID   uint64
+	bot      *discordgo.User
+	users    map[string]*discordgo.User
+	guilds   map[string]*discordgo.Guild
+	channels map[string]*discordgo.Channel
+	members  map[string]map[string]*discordgo.Member
+	bans     map[string]map[string]*discordgo.GuildBan
+	messages map[string][]*discordgo.Message
+	files    map[string][]byte // Attachment contents by attachment ID
+	reacted  map[string]map[string]bool
+	webhooks map[string]*discordgo.Webhook
+	invites  map[string]*discordgo.Invite
+	commands map[string]*discordgo.ApplicationCommand
+	perms    map[string]*discordgo.GuildApplicationCommandPermissions
+	auditLog map[string][]*discordgo.AuditLogEntry
+	requests []Request
+
+	gateway *gateway
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithToken sets the bot token the server accepts
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithBotUser sets the username of the bot user
+func WithBotUser(username string) Option {
+	return func(s *Server) {
+		s.bot.Username = username
+	}
+}
+
+// NewServer starts a fake Discord server on a local port
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		token:    DefaultToken,
+		users:    make(map[string]*discordgo.User),
+		guilds:   make(map[string]*discordgo.Guild),
+		channels: make(map[string]*discordgo.Channel),
+		members:  make(map[string]map[string]*discordgo.Member),
+		bans:     make(map[string]map[string]*discordgo.GuildBan),
+		messages: make(map[string][]*discordgo.Message),
+		files:    make(map[string][]byte),
+		reacted:  make(map[string]map[string]bool),
+		webhooks: make(map[string]*discordgo.Webhook),
+		invites:  make(map[string]*discordgo.Invite),
+		commands: make(map[string]*discordgo.ApplicationCommand),
+		perms:    make(map[string]*discordgo.GuildApplicationCommandPermissions),
+		auditLog: make(map[string][]*discordgo.AuditLogEntry),
+	}
+	s.bot = &discordgo.User{
+		ID:            s.nextID(),
+		Username:      "fake-bot",
+		Discriminator: "0",
+		Bot:           true,
+	}
+	s.users[s.bot.ID] = s.bot
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.gateway = newGateway(s)
+	s.mux = http.NewServeMux()
+	s.routes()
+	s.srv = httptest.NewServer(s)
+	return s
+}
+
+// Close shuts down the server and drops gateway connections
+func (s *Server) Close() {
+	s.gateway.close()
+	s.srv.Close()
+}
+
+// URL returns the root URL of the server
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// APIBase returns the REST API base to pass to discord.WithAPIBase
+func (s *Server) APIBase() string {
+	return s.srv.URL + "/api/v" + discordgo.APIVersion
+}
+
+// GatewayURL returns the websocket URL to pass to discord.WithGatewayURL
+func (s *Server) GatewayURL() string {
+	return "ws" + strings.TrimPrefix(s.srv.URL, "http") + "/gateway"
+}
+
+// Token returns the bot token the server accepts
+func (s *Server) Token() string {
+	return s.token
+}
+
+// Bot returns the bot user
+func (s *Server) Bot() *discordgo.User {
+	return s.bot
+}
+
+// Args returns the global dccli flags that point a command at this server
+func (s *Server) Args() []string {
+	return []string{
+		"--token", s.token,
+		"--api-base", s.APIBase(),
+		"--gateway-url", s.GatewayURL(),
+	}
+}
+
+// NewClient creates a DiscordClient connected to this server
+func (s *Server) NewClient() (*discord.DiscordClient, error) {
+	return discord.NewClient(s.token, discord.WithAPIBase(s.APIBase()), discord.WithGatewayURL(s.GatewayURL()))
+}
+
+// Requests returns every REST call received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// discordgo builds some routes with a double slash (e.g. voice regions)
+	if strings.Contains(r.URL.Path, "//") {
+		r.URL.Path = path.Clean(r.URL.Path)
+		r.URL.RawPath = ""
+	}
+
+	if strings.TrimSuffix(r.URL.Path, "/") == "/gateway" {
+		s.gateway.serve(w, r)
+		return
+	}
+
+	// Attachment URLs stand in for the CDN, which needs no token
+	if strings.HasPrefix(r.URL.Path, "/attachments/") {
+		s.serveAttachment(w, r)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bot "+s.token {
+		writeError(w, http.StatusUnauthorized, 0, "401: Unauthorized")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// Seeding helpers
+
+// AddUser registers a user that can later join guilds or author messages
+func (s *Server) AddUser(username string) *discordgo.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := &discordgo.User{
+		ID:            s.nextID(),
+		Username:      username,
+		Discriminator: "0",
+	}
+	s.users[user.ID] = user
+	return user
+}
+
+// AddGuild creates a guild owned by the bot with an @everyone role.
+// The bot is added as a member.
+func (s *Server) AddGuild(name string) *discordgo.Guild {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	guild := &discordgo.Guild{
+		ID:      s.nextID(),
+		Name:    name,
+		OwnerID: s.bot.ID,
+	}
+	guild.Roles = []*discordgo.Role{{
+		ID:          guild.ID,
+		Name:        "@everyone",
+		Permissions: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages,
+	}}
+	s.guilds[guild.ID] = guild
+	s.members[guild.ID] = make(map[string]*discordgo.Member)
+	s.bans[guild.ID] = make(map[string]*discordgo.GuildBan)
+	s.addMember(guild.ID, s.bot)
+	return guild
+}
+
+// AddChannel creates a channel in a guild
+func (s *Server) AddChannel(guildID, name string, channelType discordgo.ChannelType) *discordgo.Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addChannel(guildID, &discordgo.Channel{
+		Name: name,
+		Type: channelType,
+	})
+}
+
+// AddRole creates a role in a guild
+func (s *Server) AddRole(guildID, name string) *discordgo.Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addRole(guildID, &discordgo.RoleParams{Name: name})
+}
+
+// AddMember adds a user to a guild
+func (s *Server) AddMember(guildID string, user *discordgo.User) *discordgo.Member {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addMember(guildID, user)
+}
+
+// AddMessage posts a message as the given author and dispatches MESSAGE_CREATE
+// to connected gateway sessions
+func (s *Server) AddMessage(channelID string, author *discordgo.User, content string) *discordgo.Message {
+	s.mu.Lock()
+	msg := s.addMessage(channelID, author, &messagePayload{Content: content})
+	s.mu.Unlock()
+
+	s.gateway.dispatch("MESSAGE_CREATE", msg)
+	return msg
+}
+
+// AddMessageAt posts a message as if it had been sent at the given time,
+// e.g. to fill a channel with history older than the bulk delete limit. No
+// event is dispatched.
+func (s *Server) AddMessageAt(channelID string, author *discordgo.User, content string, at time.Time) *discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.addMessage(channelID, author, &messagePayload{Content: content})
+	// Keep the low bits of the ID so backdated messages stay distinct
+	id, _ := strconv.ParseUint(msg.ID, 10, 64)
+	msg.ID = strconv.FormatUint(uint64(at.UnixMilli()-snowflake.Epoch)<<22|id&(1<<22-1), 10)
+	msg.Timestamp = at.UTC()
+	messages := s.messages[channelID]
+	sort.Slice(messages, func(i, j int) bool { return snowflake.Less(messages[i].ID, messages[j].ID) })
+	if ch := s.channels[channelID]; ch != nil {
+		ch.LastMessageID = messages[len(messages)-1].ID
+	}
+	return msg
+}
+
+// AddAuditLogEntry appends an entry to a guild audit log. The entry ID is
+// assigned by the server; the acting user must have been added with AddUser.
+func (s *Server) AddAuditLogEntry(guildID string, entry *discordgo.AuditLogEntry) *discordgo.AuditLogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.ID = s.nextID()
+	s.auditLog[guildID] = append(s.auditLog[guildID], entry)
+	return entry
+}
+
+// State accessors
+
+// Guild returns a guild by ID
+func (s *Server) Guild(id string) (*discordgo.Guild, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.guilds[id]
+	return g, ok
+}
+
+// Channel returns a channel by ID
+func (s *Server) Channel(id string) (*discordgo.Channel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.channels[id]
+	return c, ok
+}
+
+// Member returns a guild member
+func (s *Server) Member(guildID, userID string) (*discordgo.Member, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.members[guildID][userID]
+	return m, ok
+}
+
+// Messages returns the messages of a channel, oldest first
+func (s *Server) Messages(channelID string) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.Message(nil), s.messages[channelID]...)
+}
+
+// Webhooks returns all webhooks
+func (s *Server) Webhooks() []*discordgo.Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*discordgo.Webhook
+	for _, w := range s.webhooks {
+		result = append(result, w)
+	}
+	sortByID(result, func(w *discordgo.Webhook) string { return w.ID })
+	return result
+}
+
+// Commands returns application commands registered for a guild ("" for global)
+func (s *Server) Commands(guildID string) []*discordgo.ApplicationCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commandsFor(s.bot.ID, guildID)
+}
+
+// Internal state helpers. Callers must hold s.mu.
+
+// nextID returns a new snowflake based on the current time
+func (s *Server) nextID() string {
+	id := uint64(time.Now().UnixMilli()-snowflake.Epoch) << 22
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	s.lastID = id
+	return strconv.FormatUint(id, 10)
+}
+
+func (s *Server) addChannel(guildID string, ch *discordgo.Channel) *discordgo.Channel {
+	ch.ID = s.nextID()
+	ch.GuildID = guildID
+	if guild, ok := s.guilds[guildID]; ok {
+		ch.Position = len(guild.Channels)
+		guild.Channels = append(guild.Channels, ch)
+	}
+	s.channels[ch.ID] = ch
+	return ch
+}
+
+func (s *Server) addRole(guildID string, params *discordgo.RoleParams) *discordgo.Role {
+	guild := s.guilds[guildID]
+	role := &discordgo.Role{
+		ID:       s.nextID(),
+		Position: len(guild.Roles),
+	}
+	applyRoleParams(role, params)
+	guild.Roles = append(guild.Roles, role)
+	return role
+}
+
+func (s *Server) addMember(guildID string, user *discordgo.User) *discordgo.Member {
+	s.users[user.ID] = user
+	member := &discordgo.Member{
+		GuildID:  guildID,
+		User:     user,
+		JoinedAt: time.Now().UTC(),
+		Roles:    []string{},
+	}
+	s.members[guildID][user.ID] = member
+	if guild, ok := s.guilds[guildID]; ok {
+		guild.MemberCount = len(s.members[guildID])
+	}
+	return member
+}
+
+func (s *Server) addMessage(channelID string, author *discordgo.User, p *messagePayload) *discordgo.Message {
+	ch := s.channels[channelID]
+	msg := &discordgo.Message{
+		ID:          s.nextID(),
+		ChannelID:   channelID,
+		Content:     p.Content,
+		Embeds:      p.Embeds,
+		TTS:         p.TTS,
+		Author:      author,
+		Timestamp:   time.Now().UTC(),
+		Attachments: p.attachments,
+		Mentions:    s.mentions(p.Content),
+	}
+	if msg.Embeds == nil {
+		msg.Embeds = []*discordgo.MessageEmbed{}
+	}
+	if ch != nil {
+		msg.GuildID = ch.GuildID
+		ch.LastMessageID = msg.ID
+	}
+	if p.MessageReference != nil {
+		msg.MessageReference = p.MessageReference
+		msg.Type = discordgo.MessageTypeReply
+		msg.ReferencedMessage = s.findMessage(channelID, p.MessageReference.MessageID)
+	}
+	s.messages[channelID] = append(s.messages[channelID], msg)
+	return msg
+}
+
+// mentions resolves <@id> tokens in content to known users
+func (s *Server) mentions(content string) []*discordgo.User {
+	var users []*discordgo.User
+	for _, field := range strings.Fields(content) {
+		id := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(field, "<@"), "!"), ">")
+		if id == field {
+			continue
+		}
+		if user, ok := s.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+func (s *Server) findMessage(channelID, messageID string) *discordgo.Message {
+	for _, m := range s.messages[channelID] {
+		if m.ID == messageID {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *Server) findRole(guildID, roleID string) (*discordgo.Role, int) {
+	guild, ok := s.guilds[guildID]
+	if !ok {
+		return nil, -1
+	}
+	for i, role := range guild.Roles {
+		if role.ID == roleID {
+			return role, i
+		}
+	}
+	return nil, -1
+}
+
+func commandKey(appID, guildID, commandID string) string {
+	return appID + "/" + guildID + "/" + commandID
+}
+
+func (s *Server) commandsFor(appID, guildID string) []*discordgo.ApplicationCommand {
+	prefix := commandKey(appID, guildID, "")
+	var result []*discordgo.ApplicationCommand
+	for key, cmd := range s.commands {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, cmd)
+		}
+	}
+	sortByID(result, func(c *discordgo.ApplicationCommand) string { return c.ID })
+	return result
+}
+
+func applyRoleParams(role *discordgo.Role, params *discordgo.RoleParams) {
+	if params.Name != "" {
+		role.Name = params.Name
+	}
+	if params.Color != nil {
+		role.Color = *params.Color
+	}
+	if params.Hoist != nil {
+		role.Hoist = *params.Hoist
+	}
+	if params.Permissions != nil {
+		role.Permissions = *params.Permissions
+	}
+	if params.Mentionable != nil {
+		role.Mentionable = *params.Mentionable
+	}
+}
+
+func sortByID[T any](items []T, id func(T) string) {
+	sort.Slice(items, func(i, j int) bool {
+		return snowflake.Less(id(items[i]), id(items[j]))
+	})
+}
+
+// writeJSON writes v with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+// writeError writes a Discord-style JSON error
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"code":    code,
+		"message": message,
+	})
+}
+
+// serveAttachment serves the content of an uploaded attachment at
+// /attachments/{channel}/{attachment}/{filename}
+func (s *Server) serveAttachment(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/attachments/"), "/")
+	s.mu.Lock()
+	var data []byte
+	var ok bool
+	if len(parts) == 3 {
+		data, ok = s.files[parts[1]]
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(data)
+}
+
+// writeFieldError writes a Discord-style 400 for one invalid body field
+func writeFieldError(w http.ResponseWriter, field, code, message string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"code":    codeInvalidBody,
+		"message": "Invalid Form Body",
+		"errors": map[string]interface{}{
+			field: map[string]interface{}{
+				"_errors": []map[string]string{{"code": code, "message": message}},
+			},
+		},
+	})
+}
@@ -0,0 +1,242 @@
+package discord
+
+import (
+	"iter"
+	"sort"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// Largest page each list endpoint accepts
+const (
+	guildsPageSize     = 200
+	membersPageSize    = 1000
+	bansPageSize       = 1000
+	messagesPageSize   = 100
+	eventUsersPageSize = 100
+)
+
+// paginate yields items page by page until Discord returns a short page.
+// fetch receives the cursor for the page and returns the page together with
+// the cursor of the next one. Requests go through the session, which waits
+// out rate limits before sending and retries on 429.
+func paginate[T any](cursor string, pageSize int, fetch func(cursor string) ([]T, string, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			page, next, err := fetch(cursor)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(page) < pageSize || next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// forward reports whether a before/after pair should be walked towards newer
+// IDs. Only before walks backwards.
+func forward(before, after string) bool {
+	return after != "" || before == ""
+}
+
+// stopAt ends a forward walk at the first item whose ID is not below before,
+// since Discord ignores before when after is given. An empty before walks
+// to the end.
+func stopAt[T any](items iter.Seq2[T, error], before string, id func(T) string) iter.Seq2[T, error] {
+	if before == "" {
+		return items
+	}
+	return func(yield func(T, error) bool) {
+		for item, err := range items {
+			if err == nil && !snowflake.Less(id(item), before) {
+				return
+			}
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// IterGuilds walks the guilds of the current user in ID order, starting
+// after the given guild ID and stopping at before or, when only before is
+// set, walking backwards from it, newest first.
+func IterGuilds(api DiscordAPI, before, after string) iter.Seq2[DiscordGuild, error] {
+	fwd := forward(before, after)
+	cursor := after
+	if !fwd {
+		cursor = before
+	}
+	guilds := paginate(cursor, guildsPageSize, func(cursor string) ([]DiscordGuild, string, error) {
+		var guilds []DiscordGuild
+		var err error
+		if fwd {
+			guilds, err = api.GuildList(guildsPageSize, "", cursor)
+		} else {
+			guilds, err = api.GuildList(guildsPageSize, cursor, "")
+		}
+		if err != nil || len(guilds) == 0 {
+			return guilds, "", err
+		}
+		sort.Slice(guilds, func(i, j int) bool {
+			if fwd {
+				return snowflake.Less(guilds[i].ID, guilds[j].ID)
+			}
+			return snowflake.Less(guilds[j].ID, guilds[i].ID)
+		})
+		return guilds, guilds[len(guilds)-1].ID, nil
+	})
+	if fwd {
+		return stopAt(guilds, before, func(g DiscordGuild) string { return g.ID })
+	}
+	return guilds
+}
+
+// IterGuildMembers walks every member of a guild in user ID order, starting
+// after the given user ID. Requires the server members intent.
+func IterGuildMembers(api DiscordAPI, guildID, after string) iter.Seq2[*discordgo.Member, error] {
+	return paginate(after, membersPageSize, func(cursor string) ([]*discordgo.Member, string, error) {
+		members, err := api.GetGuildMembers(guildID, membersPageSize, cursor)
+		if err != nil || len(members) == 0 {
+			return members, "", err
+		}
+		return members, members[len(members)-1].User.ID, nil
+	})
+}
+
+// IterGuildBans walks the bans of a guild in user ID order, starting after
+// the given user ID and stopping at before or, when only before is set,
+// walking backwards from it, highest ID first.
+func IterGuildBans(api DiscordAPI, guildID, before, after string) iter.Seq2[*discordgo.GuildBan, error] {
+	fwd := forward(before, after)
+	cursor := after
+	if !fwd {
+		cursor = before
+	}
+	bans := paginate(cursor, bansPageSize, func(cursor string) ([]*discordgo.GuildBan, string, error) {
+		var bans []*discordgo.GuildBan
+		var err error
+		if fwd {
+			bans, err = api.GetGuildBans(guildID, bansPageSize, "", cursor)
+		} else {
+			bans, err = api.GetGuildBans(guildID, bansPageSize, cursor, "")
+		}
+		if err != nil || len(bans) == 0 {
+			return bans, "", err
+		}
+		sort.Slice(bans, func(i, j int) bool {
+			if fwd {
+				return snowflake.Less(bans[i].User.ID, bans[j].User.ID)
+			}
+			return snowflake.Less(bans[j].User.ID, bans[i].User.ID)
+		})
+		return bans, bans[len(bans)-1].User.ID, nil
+	})
+	if fwd {
+		return stopAt(bans, before, func(b *discordgo.GuildBan) string { return b.User.ID })
+	}
+	return bans
+}
+
+// IterChannelMessages walks the messages of a channel. By default it goes
+// backwards from the newest message (or from before), newest first; with
+// after it goes forwards from that message, oldest first, up to before.
+func IterChannelMessages(api DiscordAPI, channelID, before, after string) iter.Seq2[*discordgo.Message, error] {
+	fwd := after != ""
+	cursor := after
+	if !fwd {
+		cursor = before
+	}
+	messages := paginate(cursor, messagesPageSize, func(cursor string) ([]*discordgo.Message, string, error) {
+		var messages []*discordgo.Message
+		var err error
+		if fwd {
+			messages, err = api.GetChannelMessages(channelID, messagesPageSize, "", cursor, "")
+		} else {
+			messages, err = api.GetChannelMessages(channelID, messagesPageSize, cursor, "", "")
+		}
+		if err != nil || len(messages) == 0 {
+			return messages, "", err
+		}
+		// Discord returns newest first even when paging with after
+		sort.Slice(messages, func(i, j int) bool {
+			if fwd {
+				return snowflake.Less(messages[i].ID, messages[j].ID)
+			}
+			return snowflake.Less(messages[j].ID, messages[i].ID)
+		})
+		return messages, messages[len(messages)-1].ID, nil
+	})
+	if fwd {
+		return stopAt(messages, before, func(m *discordgo.Message) string { return m.ID })
+	}
+	return messages
+}
+
+// IterChannelHistory walks the messages of a channel oldest first, from the
+// first message created at or after since up to the last one before until.
+// A zero since starts at the beginning of the channel, a zero until runs up
+// to the newest message.
+func IterChannelHistory(api DiscordAPI, channelID string, since, until time.Time) iter.Seq2[*discordgo.Message, error] {
+	after := "0"
+	if !since.IsZero() {
+		// after is exclusive, so start just below the first snowflake of since
+		after = snowflake.Previous(snowflake.FromTime(since))
+	}
+	return func(yield func(*discordgo.Message, error) bool) {
+		for message, err := range IterChannelMessages(api, channelID, "", after) {
+			if err == nil && !until.IsZero() && !message.Timestamp.Before(until) {
+				return
+			}
+			if !yield(message, err) {
+				return
+			}
+		}
+	}
+}
+
+// IterGuildEventUsers walks the users subscribed to a scheduled event in
+// user ID order, starting after the given user ID and stopping at before
+// or, when only before is set, walking backwards from it, highest ID first.
+func IterGuildEventUsers(api DiscordAPI, guildID, eventID, before, after string) iter.Seq2[*discordgo.GuildScheduledEventUser, error] {
+	fwd := forward(before, after)
+	cursor := after
+	if !fwd {
+		cursor = before
+	}
+	users := paginate(cursor, eventUsersPageSize, func(cursor string) ([]*discordgo.GuildScheduledEventUser, string, error) {
+		var users []*discordgo.GuildScheduledEventUser
+		var err error
+		if fwd {
+			users, err = api.GetGuildEventUsers(guildID, eventID, eventUsersPageSize, "", cursor)
+		} else {
+			users, err = api.GetGuildEventUsers(guildID, eventID, eventUsersPageSize, cursor, "")
+		}
+		if err != nil || len(users) == 0 {
+			return users, "", err
+		}
+		sort.Slice(users, func(i, j int) bool {
+			if fwd {
+				return snowflake.Less(users[i].User.ID, users[j].User.ID)
+			}
+			return snowflake.Less(users[j].User.ID, users[i].User.ID)
+		})
+		return users, users[len(users)-1].User.ID, nil
+	})
+	if fwd {
+		return stopAt(users, before, func(u *discordgo.GuildScheduledEventUser) string { return u.User.ID })
+	}
+	return users
+}
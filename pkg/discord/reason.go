@@ -0,0 +1,34 @@
+package discord
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// auditLogReasonHeader is recorded by Discord in the guild audit log
+const auditLogReasonHeader = "X-Audit-Log-Reason"
+
+// WithAuditLogReason attaches reason to every create/edit/delete request so
+// the action shows up with a trace in the guild audit log
+func WithAuditLogReason(reason string) ClientOption {
+	return func(o *clientOptions) {
+		o.reason = reason
+	}
+}
+
+// reasonTransport sets the audit log reason header on mutating requests
+type reasonTransport struct {
+	base   http.RoundTripper
+	reason string
+}
+
+func (t *reasonTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || req.Header.Get(auditLogReasonHeader) != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	// Discord expects the header value to be URL-encoded so it survives non-ASCII text
+	req.Header.Set(auditLogReasonHeader, url.PathEscape(t.reason))
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,249 @@
+package dprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatCustomColumns prints a table with columns picked from the JSON form
+// of the data. The full format carries the column spec, e.g.
+// custom-columns=NAME:.name,ID:.id
+const FormatCustomColumns OutputFormat = "custom-columns"
+
+// noValue is shown for paths missing from an item
+const noValue = "<none>"
+
+// Column is a custom column: a header and the JSON path of its value
+type Column struct {
+	Header string
+	Path   string
+}
+
+// ParseColumns parses a custom-columns spec of comma separated HEADER:PATH
+// pairs. Paths are field names from the JSON output, separated by dots and
+// optionally indexed, e.g. .user.username or .roles[0].
+func ParseColumns(spec string) ([]Column, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns needs at least one HEADER:PATH column")
+	}
+	var columns []Column
+	for _, part := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(part, ":")
+		if !ok || header == "" {
+			return nil, fmt.Errorf("invalid custom column %q (expected HEADER:PATH)", part)
+		}
+		path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+		if _, err := parsePath(path); err != nil {
+			return nil, fmt.Errorf("invalid custom column %q: %w", part, err)
+		}
+		columns = append(columns, Column{Header: header, Path: path})
+	}
+	return columns, nil
+}
+
+// ColumnsFormat returns a format showing the named fields, each under its
+// name in upper case. kind is FormatCustomColumns, FormatCSV or FormatTSV.
+func ColumnsFormat(kind OutputFormat, names []string) (OutputFormat, error) {
+	var parts []string
+	for _, name := range names {
+		name = strings.TrimPrefix(strings.TrimSpace(name), ".")
+		if name == "" {
+			continue
+		}
+		parts = append(parts, strings.ToUpper(name)+":."+name)
+	}
+	spec := strings.Join(parts, ",")
+	if _, err := ParseColumns(spec); err != nil {
+		return "", err
+	}
+	return OutputFormat(string(kind) + "=" + spec), nil
+}
+
+// Columns returns the columns of a custom-columns, csv or tsv format, nil
+// when none were given
+func (f OutputFormat) Columns() []Column {
+	_, spec, _ := strings.Cut(string(f), "=")
+	columns, _ := ParseColumns(spec)
+	return columns
+}
+
+// Kind returns the format without its parameters, e.g. custom-columns for
+// custom-columns=NAME:.name
+func (f OutputFormat) Kind() OutputFormat {
+	name, _, _ := strings.Cut(string(f), "=")
+	return OutputFormat(name)
+}
+
+// PrintCustomColumns outputs data as a table of the format's columns. A
+// slice gives one row per element, anything else a single row.
+func (o *OutputManager) PrintCustomColumns(data interface{}) error {
+	columns := o.format.Columns()
+	items, err := jsonItems(data)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, columnRow(columns, item))
+	}
+	if _, err := fmt.Fprintln(o.writer, renderTable(columnHeaders(columns), rows)); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	return nil
+}
+
+func columnHeaders(columns []Column) []string {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	return headers
+}
+
+// columnRow evaluates the columns against one item in its JSON form
+func columnRow(columns []Column, item interface{}) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		segments, _ := parsePath(column.Path)
+		value, ok := lookup(item, segments)
+		if !ok {
+			row[i] = noValue
+			continue
+		}
+		row[i] = formatValue(value)
+	}
+	return row
+}
+
+// toJSON converts data to its generic JSON form, the one paths refer to
+func toJSON(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return decodeJSON(raw)
+}
+
+// jsonItems returns the rows of data: the elements of a list, or data itself
+func jsonItems(data interface{}) ([]interface{}, error) {
+	value, err := toJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	default:
+		return []interface{}{v}, nil
+	}
+}
+
+// segment is one step of a path: a field name, or an index when field is ""
+type segment struct {
+	field string
+	index int
+}
+
+// parsePath splits .a.b[0].c into its segments
+func parsePath(path string) ([]segment, error) {
+	if !strings.HasPrefix(path, ".") {
+		return nil, fmt.Errorf("path %q must start with '.'", path)
+	}
+	var segments []segment
+	for _, part := range strings.Split(path[1:], ".") {
+		field, rest, _ := strings.Cut(part, "[")
+		if field == "" && rest == "" {
+			if path == "." {
+				break
+			}
+			return nil, fmt.Errorf("empty field in path %q", path)
+		}
+		if field != "" {
+			segments = append(segments, segment{field: field})
+		}
+		for rest != "" {
+			index, after, ok := strings.Cut(rest, "]")
+			n, err := strconv.Atoi(index)
+			if !ok || err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid index in path %q", path)
+			}
+			segments = append(segments, segment{index: n})
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("invalid index in path %q", path)
+			}
+			rest = after[1:]
+		}
+	}
+	return segments, nil
+}
+
+// lookup follows segments through a generic JSON value. Field names match
+// exactly first, then ignoring case, '_' and '-', so .id also finds "ID"
+// and .channel_id finds "ChannelID".
+func lookup(value interface{}, segments []segment) (interface{}, bool) {
+	for _, seg := range segments {
+		if seg.field == "" {
+			list, ok := value.([]interface{})
+			if !ok || seg.index >= len(list) {
+				return nil, false
+			}
+			value = list[seg.index]
+			continue
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		next, ok := object[seg.field]
+		if !ok {
+			key := normalizeField(seg.field)
+			for name, v := range object {
+				if normalizeField(name) == key {
+					next, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		value = next
+	}
+	return value, true
+}
+
+func normalizeField(name string) string {
+	name = strings.ReplaceAll(name, "_", "")
+	name = strings.ReplaceAll(name, "-", "")
+	return strings.ToLower(name)
+}
+
+// formatValue renders a JSON value for a table cell
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return noValue
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = formatValue(item)
+		}
+		return strings.Join(values, ",")
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	}
+}
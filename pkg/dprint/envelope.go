@@ -0,0 +1,159 @@
+package dprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// APIVersion identifies the layout of JSON and YAML output. It changes when
+// a field of the envelope or of a command's data is renamed or removed.
+const APIVersion = "dccli/v1"
+
+// Envelope wraps the JSON and YAML output of every command. Data holds the
+// command's result; on failure Error describes what went wrong and Data is
+// left out.
+type Envelope struct {
+	APIVersion string      `json:"api_version" yaml:"api_version"`
+	Command    string      `json:"command" yaml:"command"`
+	Success    bool        `json:"success" yaml:"success"`
+	Data       interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+	Error      *ErrorInfo  `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ErrorInfo is the error of a failed command. The HTTP status, Discord error
+// code, field errors and retry delay (in seconds) are only set for failed
+// Discord API requests.
+type ErrorInfo struct {
+	Type       string       `json:"type" yaml:"type"`
+	ExitCode   int          `json:"exit_code" yaml:"exit_code"`
+	Message    string       `json:"message" yaml:"message"`
+	HTTPStatus int          `json:"http_status,omitempty" yaml:"http_status,omitempty"`
+	Code       int          `json:"code,omitempty" yaml:"code,omitempty"`
+	Errors     []FieldError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	RetryAfter float64      `json:"retry_after,omitempty" yaml:"retry_after,omitempty"`
+}
+
+// FieldError is a field of a request body Discord rejected. Field is a
+// dotted path such as embeds.0.title.
+type FieldError struct {
+	Field   string `json:"field" yaml:"field"`
+	Code    string `json:"code" yaml:"code"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// command is the name of the running command, e.g. "roles list"
+var command string
+
+// SetCommand sets the command name reported in output envelopes
+func SetCommand(name string) {
+	command = name
+}
+
+// Enveloped reports whether the format wraps output in an Envelope. Only
+// JSON and YAML do; the other formats render the data alone.
+func (f OutputFormat) Enveloped() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// envelope wraps data in a successful Envelope. A nil list becomes an
+// empty one so list commands always have a data array.
+func envelope(data interface{}) Envelope {
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		data = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return Envelope{
+		APIVersion: APIVersion,
+		Command:    command,
+		Success:    true,
+		Data:       data,
+	}
+}
+
+// PrintError writes a failed Envelope to w in the given format, which must
+// be enveloped
+func PrintError(w io.Writer, format OutputFormat, info ErrorInfo) error {
+	o := NewOutputManager(WithFormat(format), WithWriter(w))
+	result := Envelope{
+		APIVersion: APIVersion,
+		Command:    command,
+		Error:      &info,
+	}
+	if format == FormatYAML {
+		return o.printYAMLEnvelope(result)
+	}
+	return o.PrintJSON(result)
+}
+
+// printYAMLEnvelope writes an envelope as YAML with the data in its JSON
+// form, so both formats follow the same schema
+func (o *OutputManager) printYAMLEnvelope(e Envelope) error {
+	data, err := yamlData(e.Data)
+	if err != nil {
+		return err
+	}
+	e.Data = data
+	return o.PrintYAML(e)
+}
+
+// yamlData converts data to the generic value of its JSON form
+func yamlData(data interface{}) (interface{}, error) {
+	value, err := toJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return yamlValue(value), nil
+}
+
+// yamlValue turns the numbers of a generic JSON value into Go numbers, which
+// YAML writes unquoted
+func yamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return n
+		}
+		if n, err := v.Float64(); err == nil {
+			return n
+		}
+		return string(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = yamlValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = yamlValue(item)
+		}
+	}
+	return value
+}
+
+// streamHeader writes the envelope fields that come before the data of a
+// streamed list. JSON output is left inside the open data array.
+func (o *OutputManager) streamHeader() error {
+	header := envelope(nil)
+	var err error
+	switch o.format {
+	case FormatJSON:
+		_, err = fmt.Fprintf(o.writer, "{\n  \"api_version\": %s,\n  \"command\": %s,\n  \"success\": true,\n  \"data\": [", quoteJSON(header.APIVersion), quoteJSON(header.Command))
+	case FormatYAML:
+		var data []byte
+		if data, err = yaml.Marshal(header); err == nil {
+			_, err = o.writer.Write(data)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+func quoteJSON(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
@@ -0,0 +1,65 @@
+package dprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// FormatGoTemplate executes a Go template against the JSON form of the data,
+// e.g. go-template={{range .items}}{{.id}}{{"\n"}}{{end}}
+const FormatGoTemplate OutputFormat = "go-template"
+
+// templateFuncs are available in go-template output besides the builtins
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+	"join": func(sep string, v interface{}) string {
+		list, ok := v.([]interface{})
+		if !ok {
+			return jsonPathText(v)
+		}
+		texts := make([]string, len(list))
+		for i, item := range list {
+			texts[i] = jsonPathText(item)
+		}
+		return strings.Join(texts, sep)
+	},
+}
+
+func parseGoTemplate(text string) (*template.Template, error) {
+	tpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %w", err)
+	}
+	return tpl, nil
+}
+
+// PrintGoTemplate outputs the format's Go template executed against data.
+// Fields are named as in JSON output and lists are exposed as .items.
+func (o *OutputManager) PrintGoTemplate(data interface{}) error {
+	_, text, _ := strings.Cut(string(o.format), "=")
+	tpl, err := parseGoTemplate(text)
+	if err != nil {
+		return err
+	}
+	root, err := templateData(data)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if err := tpl.Execute(&b, root); err != nil {
+		return fmt.Errorf("failed to execute go-template: %w", err)
+	}
+	out := b.String()
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	if _, err := fmt.Fprint(o.writer, out); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,595 @@
+package dprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormatJSONPath prints values picked from the JSON form of the data with a
+// JSONPath template, e.g. jsonpath={.items[*].id}
+const FormatJSONPath OutputFormat = "jsonpath"
+
+// jsonPathNode is a piece of a parsed JSONPath template
+type jsonPathNode struct {
+	text    string         // literal text, used when path is nil
+	path    []jsonPathStep // expression to print, or to range over
+	body    []jsonPathNode // nodes repeated for each result of a range
+	isRange bool
+}
+
+// jsonPathStep selects values from each input value
+type jsonPathStep struct {
+	kind   stepKind
+	field  string
+	index  int
+	start  *int
+	end    *int
+	filter *jsonPathFilter
+}
+
+type stepKind int
+
+const (
+	stepRoot stepKind = iota
+	stepField
+	stepRecursive
+	stepWildcard
+	stepIndex
+	stepSlice
+	stepFilter
+)
+
+// jsonPathFilter is [?(@.path op value)], or [?(@.path)] when op is ""
+type jsonPathFilter struct {
+	path  []jsonPathStep
+	op    string
+	value interface{}
+}
+
+// parseJSONPath parses a template of text and {expressions}. Supported are
+// .field, ['field'], ..field, * and [*], [n] (negative from the end),
+// [start:end], [?(@.field == value)] filters with == != < <= > >=,
+// {range expr}...{end} and quoted literals such as {"\n"}.
+func parseJSONPath(template string) ([]jsonPathNode, error) {
+	nodes, _, closed, err := parseJSONPathNodes(template)
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		return nil, fmt.Errorf("{end} without {range} in jsonpath template")
+	}
+	return nodes, nil
+}
+
+// parseJSONPathNodes parses until the end of input or the next {end}. It
+// returns the input left after {end} and whether one was found.
+func parseJSONPathNodes(template string) ([]jsonPathNode, string, bool, error) {
+	var nodes []jsonPathNode
+	for template != "" {
+		open := strings.Index(template, "{")
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: template})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: template[:open]})
+		}
+		close := closingBrace(template, open)
+		if close < 0 {
+			return nil, "", false, fmt.Errorf("unclosed '{' in jsonpath template")
+		}
+		expr := strings.TrimSpace(template[open+1 : close])
+		template = template[close+1:]
+
+		switch {
+		case expr == "end":
+			return nodes, template, true, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parseJSONPathExpr(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", false, err
+			}
+			body, rest, closed, err := parseJSONPathNodes(template)
+			if err != nil {
+				return nil, "", false, err
+			}
+			if !closed {
+				return nil, "", false, fmt.Errorf("{range} without {end} in jsonpath template")
+			}
+			template = rest
+			nodes = append(nodes, jsonPathNode{path: path, body: body, isRange: true})
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquote(expr)
+			if err != nil {
+				return nil, "", false, fmt.Errorf("invalid literal %s in jsonpath template", expr)
+			}
+			nodes = append(nodes, jsonPathNode{text: text})
+		default:
+			path, err := parseJSONPathExpr(expr)
+			if err != nil {
+				return nil, "", false, err
+			}
+			nodes = append(nodes, jsonPathNode{path: path})
+		}
+	}
+	return nodes, "", false, nil
+}
+
+// closingBrace returns the index of the '}' closing the '{' at open,
+// skipping quoted strings
+func closingBrace(s string, open int) int {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == '\\':
+			i++
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+// unquote decodes a single or double quoted string; \' escapes a quote in a
+// single quoted one
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		inner := strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`)
+		s = `"` + strings.ReplaceAll(inner, `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// parseJSONPathExpr parses one path expression, e.g. .items[*].id
+func parseJSONPathExpr(expr string) ([]jsonPathStep, error) {
+	var steps []jsonPathStep
+	s := expr
+	switch {
+	case strings.HasPrefix(s, "$"):
+		steps = append(steps, jsonPathStep{kind: stepRoot})
+		s = s[1:]
+	case strings.HasPrefix(s, "@"):
+		s = s[1:]
+	case !strings.HasPrefix(s, ".") && !strings.HasPrefix(s, "["):
+		return nil, fmt.Errorf("invalid jsonpath expression %q", expr)
+	}
+
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			name, rest := fieldName(s[2:])
+			if name == "" {
+				return nil, fmt.Errorf("missing field after '..' in %q", expr)
+			}
+			steps = append(steps, jsonPathStep{kind: stepRecursive, field: name})
+			s = rest
+		case strings.HasPrefix(s, ".*"):
+			steps = append(steps, jsonPathStep{kind: stepWildcard})
+			s = s[2:]
+		case strings.HasPrefix(s, "."):
+			name, rest := fieldName(s[1:])
+			if name != "" {
+				steps = append(steps, jsonPathStep{kind: stepField, field: name})
+			}
+			s = rest
+		case strings.HasPrefix(s, "["):
+			end := closingBracket(s)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in %q", expr)
+			}
+			step, err := parseBracket(strings.TrimSpace(s[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, expr)
+			}
+			steps = append(steps, step)
+			s = s[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", s, expr)
+		}
+	}
+	return steps, nil
+}
+
+// fieldName splits a field name off the start of s
+func fieldName(s string) (string, string) {
+	i := strings.IndexAny(s, ".[")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+// closingBracket returns the index of the ']' closing s[0], skipping quoted
+// strings and nested brackets
+func closingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == '\\':
+			i++
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '[':
+			depth++
+		case s[i] == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(inner string) (jsonPathStep, error) {
+	switch {
+	case inner == "*":
+		return jsonPathStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`):
+		name, err := unquote(inner)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("invalid field name %s", inner)
+		}
+		return jsonPathStep{kind: stepField, field: name}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		filter, err := parseFilter(strings.TrimSpace(inner[2 : len(inner)-1]))
+		if err != nil {
+			return jsonPathStep{}, err
+		}
+		return jsonPathStep{kind: stepFilter, filter: filter}, nil
+	case strings.Contains(inner, ":"):
+		from, to, _ := strings.Cut(inner, ":")
+		step := jsonPathStep{kind: stepSlice}
+		if from = strings.TrimSpace(from); from != "" {
+			n, err := strconv.Atoi(from)
+			if err != nil {
+				return jsonPathStep{}, fmt.Errorf("invalid slice [%s]", inner)
+			}
+			step.start = &n
+		}
+		if to = strings.TrimSpace(to); to != "" {
+			n, err := strconv.Atoi(to)
+			if err != nil {
+				return jsonPathStep{}, fmt.Errorf("invalid slice [%s]", inner)
+			}
+			step.end = &n
+		}
+		return step, nil
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("invalid index [%s]", inner)
+		}
+		return jsonPathStep{kind: stepIndex, index: n}, nil
+	}
+}
+
+// filterOps are checked longest first so <= is not read as <
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseFilter(expr string) (*jsonPathFilter, error) {
+	filter := &jsonPathFilter{}
+	left := expr
+	if i, op := filterOp(expr); op != "" {
+		left, filter.op = strings.TrimSpace(expr[:i]), op
+		value, err := parseFilterValue(strings.TrimSpace(expr[i+len(op):]))
+		if err != nil {
+			return nil, err
+		}
+		filter.value = value
+	}
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("filter %q must start with @", expr)
+	}
+	path, err := parseJSONPathExpr(left)
+	if err != nil {
+		return nil, err
+	}
+	filter.path = path
+	return filter, nil
+}
+
+// filterOp returns the index and text of the first operator in expr,
+// skipping quoted strings, or "" when there is none
+func filterOp(expr string) (int, string) {
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case quote != 0 && expr[i] == '\\':
+			i++
+		case quote != 0:
+			if expr[i] == quote {
+				quote = 0
+			}
+		case expr[i] == '"' || expr[i] == '\'':
+			quote = expr[i]
+		default:
+			for _, op := range filterOps {
+				if strings.HasPrefix(expr[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+func parseFilterValue(s string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		return unquote(s)
+	case s == "true" || s == "false":
+		return s == "true", nil
+	case s == "null":
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %s", s)
+	}
+	return n, nil
+}
+
+// evalJSONPath applies steps to current; root is the value $ refers to
+func evalJSONPath(steps []jsonPathStep, root, current interface{}) []interface{} {
+	values := []interface{}{current}
+	for _, step := range steps {
+		var next []interface{}
+		for _, value := range values {
+			next = append(next, applyStep(step, root, value)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func applyStep(step jsonPathStep, root, value interface{}) []interface{} {
+	switch step.kind {
+	case stepRoot:
+		return []interface{}{root}
+	case stepField:
+		if v, ok := lookup(value, []segment{{field: step.field}}); ok {
+			return []interface{}{v}
+		}
+	case stepRecursive:
+		return descend(value, step.field)
+	case stepWildcard:
+		return children(value)
+	case stepIndex:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		i := step.index
+		if i < 0 {
+			i += len(list)
+		}
+		if i >= 0 && i < len(list) {
+			return []interface{}{list[i]}
+		}
+	case stepSlice:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		start, end := 0, len(list)
+		if step.start != nil {
+			start = clampIndex(*step.start, len(list))
+		}
+		if step.end != nil {
+			end = clampIndex(*step.end, len(list))
+		}
+		if start < end {
+			return list[start:end]
+		}
+	case stepFilter:
+		var matched []interface{}
+		for _, child := range children(value) {
+			if step.filter.match(root, child) {
+				matched = append(matched, child)
+			}
+		}
+		return matched
+	}
+	return nil
+}
+
+func clampIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	return max(0, min(i, length))
+}
+
+// children returns the elements of a list or the values of an object
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := sortedKeys(v)
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			values[i] = v[key]
+		}
+		return values
+	}
+	return nil
+}
+
+// descend returns every value of field found at any depth below value
+func descend(value interface{}, field string) []interface{} {
+	var found []interface{}
+	if object, ok := value.(map[string]interface{}); ok {
+		if v, ok := lookup(object, []segment{{field: field}}); ok {
+			found = append(found, v)
+		}
+	}
+	for _, child := range children(value) {
+		found = append(found, descend(child, field)...)
+	}
+	return found
+}
+
+func (f *jsonPathFilter) match(root, value interface{}) bool {
+	results := evalJSONPath(f.path, root, value)
+	if f.op == "" {
+		return len(results) > 0 && results[0] != nil && results[0] != false
+	}
+	if len(results) == 0 {
+		return false
+	}
+	left := results[0]
+	if n, ok := left.(json.Number); ok {
+		if right, ok := f.value.(float64); ok {
+			l, err := n.Float64()
+			if err != nil {
+				return false
+			}
+			return compare(f.op, l < right, l == right)
+		}
+		left = n.String()
+	}
+	switch right := f.value.(type) {
+	case string:
+		l, ok := left.(string)
+		return ok && compare(f.op, l < right, l == right)
+	case float64:
+		// Snowflakes and other numbers sent as strings
+		l, ok := left.(string)
+		if !ok {
+			return false
+		}
+		n, err := strconv.ParseFloat(l, 64)
+		return err == nil && compare(f.op, n < right, n == right)
+	default:
+		equal := left == right
+		return (f.op == "==" && equal) || (f.op == "!=" && !equal)
+	}
+}
+
+func compare(op string, less, equal bool) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	}
+	return false
+}
+
+// PrintJSONPath outputs the format's JSONPath template evaluated against
+// data. Lists are exposed as .items, so {.items[*].id} prints every ID.
+func (o *OutputManager) PrintJSONPath(data interface{}) error {
+	_, template, _ := strings.Cut(string(o.format), "=")
+	nodes, err := parseJSONPath(template)
+	if err != nil {
+		return err
+	}
+	root, err := templateData(data)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	writeJSONPath(&b, nodes, root, root)
+	out := b.String()
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	if _, err := fmt.Fprint(o.writer, out); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+func writeJSONPath(b *strings.Builder, nodes []jsonPathNode, root, current interface{}) {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			for _, value := range evalJSONPath(node.path, root, current) {
+				// Ranging over a single list walks its elements
+				items := []interface{}{value}
+				if list, ok := value.([]interface{}); ok {
+					items = list
+				}
+				for _, item := range items {
+					writeJSONPath(b, node.body, root, item)
+				}
+			}
+		case node.path != nil:
+			values := evalJSONPath(node.path, root, current)
+			texts := make([]string, 0, len(values))
+			for _, value := range values {
+				texts = append(texts, jsonPathText(value))
+			}
+			b.WriteString(strings.Join(texts, " "))
+		default:
+			b.WriteString(node.text)
+		}
+	}
+}
+
+// jsonPathText renders a value: strings and numbers as is, the rest as JSON
+func jsonPathText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	}
+}
+
+// templateData converts data to its JSON form for templates. Lists are
+// wrapped as {"items": [...]}.
+func templateData(data interface{}) (interface{}, error) {
+	value, err := toJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := value.([]interface{}); ok {
+		return map[string]interface{}{"items": list}, nil
+	}
+	if value == nil {
+		return map[string]interface{}{"items": []interface{}{}}, nil
+	}
+	return value, nil
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
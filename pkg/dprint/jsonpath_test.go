@@ -0,0 +1,69 @@
+package dprint
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintJSONPath(t *testing.T) {
+	items := []map[string]interface{}{
+		{"id": "1", "name": "a", "size": 10, "tags": []string{"x", "y"}},
+		{"id": "2", "name": "b==c", "size": 20},
+		{"id": "3", "name": "it's", "size": 30, "nested": map[string]interface{}{"id": "n"}},
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"field", "{.items[0].name}", "a"},
+		{"bracket field", "{.items[0]['name']}", "a"},
+		{"wildcard", "{.items[*].id}", "1 2 3"},
+		{"star field", "{.items[0].tags.*}", "x y"},
+		{"recursive", "{..id}", "1 2 3 n"},
+		{"negative index", "{.items[-1].id}", "3"},
+		{"range", "{.items[1:].id}", "2 3"},
+		{"open range", "{.items[:2].id}", "1 2"},
+		{"filter equal", "{.items[?(@.name=='a')].id}", "1"},
+		{"filter not equal", "{.items[?(@.name!='a')].id}", "2 3"},
+		{"filter number", "{.items[?(@.size>=20)].id}", "2 3"},
+		{"filter less", "{.items[?(@.size < 20)].id}", "1"},
+		{"filter exists", "{.items[?(@.nested)].id}", "3"},
+		{"operator in quoted value", "{.items[?(@.name!='b==c')].name}", "a it's"},
+		{"equal to quoted operator", "{.items[?(@.name=='b==c')].id}", "2"},
+		{"double quoted value", `{.items[?(@.name=="it's")].id}`, "3"},
+		{"escaped quote", `{.items[?(@.name=='it\'s')].id}`, "3"},
+		{"range block", "{range .items[*]}{.id}:{.name}{\"\\n\"}{end}", "1:a\n2:b==c\n3:it's\n"},
+		{"text around", "ids: {.items[*].id}", "ids: 1 2 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			output := NewOutputManager(WithFormat(FormatJSONPath+"="+OutputFormat(tt.template)), WithWriter(&buf))
+			if err := output.PrintJSONPath(items); err != nil {
+				t.Fatalf("PrintJSONPath(%s): %v", tt.template, err)
+			}
+			want := tt.want
+			if want[len(want)-1] != '\n' {
+				want += "\n"
+			}
+			if got := buf.String(); got != want {
+				t.Errorf("PrintJSONPath(%s) = %q, want %q", tt.template, got, want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPathErrors(t *testing.T) {
+	for _, template := range []string{
+		"{.items[?(name=='a')]}",
+		"{.items[?(@.name=='a)]}",
+		"{.items[a:b]}",
+		"{.items[x]}",
+		"{end}",
+	} {
+		if _, err := parseJSONPath(template); err == nil {
+			t.Errorf("parseJSONPath(%s) succeeded, want an error", template)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -67,16 +68,26 @@ func (o *OutputManager) GetFormat() OutputFormat {
 }
 
 // Print outputs data based on the configured format
-// For table format, use PrintTable with headers and rows
-// For JSON/YAML, pass a struct, slice, or map
+// For JSON/YAML, data is wrapped in an Envelope
+// For table format, data is rendered with PrintTableFromStructs
 func (o *OutputManager) Print(data interface{}) error {
-	switch o.format {
+	switch o.format.Kind() {
 	case FormatJSON:
-		return o.PrintJSON(data)
+		return o.PrintJSON(envelope(data))
 	case FormatYAML:
-		return o.PrintYAML(data)
+		return o.printYAMLEnvelope(envelope(data))
+	case FormatCustomColumns:
+		return o.PrintCustomColumns(data)
+	case FormatJSONPath:
+		return o.PrintJSONPath(data)
+	case FormatGoTemplate:
+		return o.PrintGoTemplate(data)
+	case FormatCSV, FormatTSV:
+		return o.PrintCSV(data)
+	case FormatNDJSON:
+		return o.PrintNDJSON(data)
 	default:
-		return fmt.Errorf("use PrintTable for table format")
+		return o.PrintTableFromStructs(data)
 	}
 }
 
@@ -127,14 +138,6 @@ func (o *OutputManager) PrintTable(headers []string, rows [][]string) error {
 	return nil
 }
 
-// PrintTableFromStructs outputs a slice of structs as a table
-// Uses struct tags to determine column names
-func (o *OutputManager) PrintTableFromStructs(data interface{}) error {
-	// This is a placeholder for future implementation
-	// Would use reflection to convert structs to table rows
-	return fmt.Errorf("PrintTableFromStructs not yet implemented")
-}
-
 // DefaultOutputManager is the global default output manager
 var DefaultOutputManager = NewOutputManager()
 
@@ -161,16 +164,42 @@ func PrintYAML(data interface{}) error {
 // ParseFormat parses a string into an OutputFormat
 // Falls back to table format for unknown formats
 func ParseFormat(s string) (OutputFormat, error) {
-	switch s {
+	name, spec, _ := strings.Cut(s, "=")
+	switch name {
 	case "table":
 		return FormatTable, nil
 	case "json":
 		return FormatJSON, nil
 	case "yaml":
 		return FormatYAML, nil
+	case "ndjson":
+		return FormatNDJSON, nil
+	case "csv", "tsv":
+		if spec == "" || spec == allFields {
+			return OutputFormat(s), nil
+		}
+		if _, err := ParseColumns(spec); err != nil {
+			return FormatTable, err
+		}
+		return OutputFormat(s), nil
+	case "custom-columns":
+		if _, err := ParseColumns(spec); err != nil {
+			return FormatTable, err
+		}
+		return OutputFormat(s), nil
+	case "jsonpath":
+		if _, err := parseJSONPath(spec); err != nil {
+			return FormatTable, err
+		}
+		return OutputFormat(s), nil
+	case "go-template":
+		if _, err := parseGoTemplate(spec); err != nil {
+			return FormatTable, err
+		}
+		return OutputFormat(s), nil
 	default:
 		// Return table as default for invalid formats
-		return FormatTable, fmt.Errorf("invalid output format: %s (valid: table, json, yaml)", s)
+		return FormatTable, fmt.Errorf("invalid output format: %s (valid: table, json, yaml, ndjson, csv, tsv, custom-columns=..., jsonpath=..., go-template=...)", s)
 	}
 }
 
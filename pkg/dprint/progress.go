@@ -0,0 +1,50 @@
+package dprint
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// progressWidth is the number of cells in a progress bar
+const progressWidth = 30
+
+// ProgressBar redraws a single line such as
+//
+//	Assigning role [###########-------------------] 120/300 (2 failed)
+//
+// on a terminal. It is meant for stderr so it never mixes with output.
+type ProgressBar struct {
+	w     io.Writer
+	label string
+	drawn bool
+}
+
+// NewProgressBar returns a progress bar writing to w. A nil w gives a bar
+// that draws nothing, for when stderr is not a terminal or status messages
+// are suppressed.
+func NewProgressBar(w io.Writer, label string) *ProgressBar {
+	return &ProgressBar{w: w, label: label}
+}
+
+// Update redraws the bar
+func (p *ProgressBar) Update(done, failed, total int) {
+	if p.w == nil || total == 0 {
+		return
+	}
+	filled := progressWidth * done / total
+	line := fmt.Sprintf("%s [%s%s] %d/%d", p.label, strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), done, total)
+	if failed > 0 {
+		line += fmt.Sprintf(" (%d failed)", failed)
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s", line)
+	p.drawn = true
+}
+
+// Finish ends the bar's line so later messages start on a new one
+func (p *ProgressBar) Finish() {
+	if p.drawn {
+		fmt.Fprintln(p.w)
+		p.drawn = false
+	}
+}
@@ -0,0 +1,253 @@
+package dprint
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// FormatCSV outputs data as comma separated values with a header row.
+	// csv=HEADER:.path,... picks the columns like custom-columns and csv=*
+	// writes every field of the JSON output.
+	FormatCSV OutputFormat = "csv"
+	// FormatTSV outputs data as tab separated values with a header row
+	FormatTSV OutputFormat = "tsv"
+	// FormatNDJSON outputs data as newline delimited JSON, one compact
+	// object per list item
+	FormatNDJSON OutputFormat = "ndjson"
+)
+
+// allFields is the csv and tsv parameter selecting every JSON field
+const allFields = "*"
+
+// AllFields reports whether a csv or tsv format writes every field of the
+// JSON output rather than the table columns
+func (f OutputFormat) AllFields() bool {
+	_, spec, _ := strings.Cut(string(f), "=")
+	return spec == allFields && (f.Kind() == FormatCSV || f.Kind() == FormatTSV)
+}
+
+// PrintNDJSON outputs each element of a list, or data itself, as a line of
+// compact JSON
+func (o *OutputManager) PrintNDJSON(data interface{}) error {
+	items, err := rawItems(data)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := writeNDJSON(o.writer, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintCSV outputs data as CSV, or TSV in tsv format. The columns are those
+// of the table format, so fields a table leaves out, such as a user's token
+// or email, are not written either. Explicit columns pick fields of the JSON
+// output instead. With all fields, and for items that are not structs, every
+// field of the JSON output is a column: nested objects are flattened into
+// dotted names such as user.username and lists are joined with commas.
+func (o *OutputManager) PrintCSV(data interface{}) error {
+	var header []string
+	var rows [][]string
+	var err error
+	if columns := o.format.Columns(); columns != nil {
+		header = columnHeaders(columns)
+		items, err := rawItems(data)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			row, err := recordColumns(columns, item)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+	} else if o.format.AllFields() {
+		if header, rows, err = flattenTable(data); err != nil {
+			return err
+		}
+	} else if header, rows, err = structTable(data); err != nil {
+		// Not a struct, so there are no table columns to leave fields out of
+		if header, rows, err = flattenTable(data); err != nil {
+			return err
+		}
+	}
+
+	w := o.csvWriter()
+	if len(header) > 0 {
+		if err := w.Write(header); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// flattenTable returns a column for every field of the JSON of data and a
+// row per list element
+func flattenTable(data interface{}) ([]string, [][]string, error) {
+	items, err := rawItems(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	records := make([]record, len(items))
+	for i, item := range items {
+		if err := records[i].flatten(item, ""); err != nil {
+			return nil, nil, err
+		}
+	}
+	header := recordHeader(records)
+	rows := make([][]string, 0, len(records))
+	for _, r := range records {
+		rows = append(rows, r.row(header))
+	}
+	return header, rows, nil
+}
+
+// csvWriter returns a CSV writer using the separator of the format
+func (o *OutputManager) csvWriter() *csv.Writer {
+	w := csv.NewWriter(o.writer)
+	if o.format.Kind() == FormatTSV {
+		w.Comma = '\t'
+	}
+	return w
+}
+
+// rawItems returns the JSON of each element of a list, or of data itself
+func rawItems(data interface{}) ([]json.RawMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	switch raw[0] {
+	case 'n':
+		return nil, nil
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		return items, nil
+	default:
+		return []json.RawMessage{raw}, nil
+	}
+}
+
+func writeNDJSON(w io.Writer, item json.RawMessage) error {
+	var line bytes.Buffer
+	if err := json.Compact(&line, item); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	line.WriteByte('\n')
+	if _, err := w.Write(line.Bytes()); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// recordColumns evaluates custom columns against the JSON of one item
+func recordColumns(columns []Column, item json.RawMessage) ([]string, error) {
+	value, err := decodeJSON(item)
+	if err != nil {
+		return nil, err
+	}
+	return columnRow(columns, value), nil
+}
+
+// record is one flattened item: its field names in JSON order and their
+// cell values
+type record struct {
+	keys   []string
+	values map[string]string
+}
+
+// flatten adds the fields of a JSON value to the record. Objects recurse
+// with their key appended to prefix; anything else is a single cell.
+func (r *record) flatten(raw json.RawMessage, prefix string) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return fmt.Errorf("failed to decode JSON: %w", err)
+			}
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return fmt.Errorf("failed to decode JSON: %w", err)
+			}
+			if err := r.flatten(value, prefix+fmt.Sprint(key)+"."); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	value, err := decodeJSON(raw)
+	if err != nil {
+		return err
+	}
+	cell := ""
+	if value != nil {
+		cell = formatValue(value)
+	}
+	name := strings.TrimSuffix(prefix, ".")
+	if name == "" {
+		name = "value"
+	}
+	if r.values == nil {
+		r.values = make(map[string]string)
+	}
+	if _, ok := r.values[name]; !ok {
+		r.keys = append(r.keys, name)
+	}
+	r.values[name] = cell
+	return nil
+}
+
+// row returns the record's cells in header order, empty where it has no value
+func (r record) row(header []string) []string {
+	row := make([]string, len(header))
+	for i, name := range header {
+		row[i] = r.values[name]
+	}
+	return row
+}
+
+// recordHeader returns every field name of the records in order of first
+// appearance
+func recordHeader(records []record) []string {
+	var header []string
+	seen := make(map[string]bool)
+	for _, r := range records {
+		for _, key := range r.keys {
+			if !seen[key] {
+				seen[key] = true
+				header = append(header, key)
+			}
+		}
+	}
+	return header
+}
+
+func decodeJSON(raw json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,98 @@
+package dprint
+
+import (
+	"bytes"
+	"testing"
+)
+
+type csvUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Token    string `json:"token"`
+}
+
+type csvMember struct {
+	User  *csvUser `json:"user"`
+	Nick  string   `json:"nick"`
+	Roles []string `json:"roles"`
+
+	Username string `json:"-" table:"Username"`
+	NickName string `json:"-" table:"Nick"`
+}
+
+func csvMembers() []csvMember {
+	return []csvMember{
+		{User: &csvUser{ID: "1", Username: "a", Email: "a@example.com", Token: "secret"}, Nick: "x", Roles: []string{"r1", "r2"}, Username: "a", NickName: "x"},
+		{User: &csvUser{ID: "2", Username: "b"}, Username: "b"},
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   interface{}
+		want   string
+	}{
+		{"table columns", "csv", csvMembers(), "Username,Nick\na,x\nb,\n"},
+		{"tsv", "tsv", csvMembers(), "Username\tNick\na\tx\nb\t\n"},
+		{"single item", "csv", csvMembers()[0], "Username,Nick\na,x\n"},
+		{"empty list", "csv", []csvMember{}, "Username,Nick\n"},
+		{"explicit columns", "csv=ID:.user.id,NICK:.nick", csvMembers(), "ID,NICK\n1,x\n2,\n"},
+		{"all fields", "csv=*", csvMembers(),
+			"user.id,user.username,user.email,user.token,nick,roles\n" +
+				"1,a,a@example.com,secret,x,\"r1,r2\"\n" +
+				"2,b,,,,\n"},
+		{"not structs", "csv", []string{"1", "2"}, "value\n1\n2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := ParseFormat(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			o := NewOutputManager(WithFormat(format), WithWriter(&buf))
+			if err := o.Print(tt.data); err != nil {
+				t.Fatalf("Print: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"csv", "Username,Nick\na,x\nb,\n"},
+		{"csv=*", "user.id,user.username,user.email,user.token,nick,roles\n" +
+			"1,a,a@example.com,secret,x,\"r1,r2\"\n" +
+			"2,b,,,,\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			format, err := ParseFormat(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			s := NewOutputManager(WithFormat(format), WithWriter(&buf)).NewStream()
+			for _, member := range csvMembers() {
+				if err := s.Add(member); err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package dprint
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaDialect is the JSON Schema version of OutputSchema
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	numberType = reflect.TypeOf(json.Number(""))
+)
+
+// OutputSchema returns the JSON Schema of a command's enveloped output.
+// data is a value of the type the command puts in the data field, or nil
+// for commands without data.
+func OutputSchema(name string, data interface{}) map[string]interface{} {
+	g := &schemaGenerator{defs: map[string]interface{}{}}
+
+	dataSchema := map[string]interface{}{"type": "null"}
+	if data != nil {
+		t := reflect.TypeOf(data)
+		dataSchema = g.schema(t)
+		if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+			// Lists are never null, see envelope
+			dataSchema["type"] = "array"
+		}
+	}
+
+	schema := map[string]interface{}{
+		"$schema": SchemaDialect,
+		"title":   "dccli " + name,
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"api_version": map[string]interface{}{"const": APIVersion},
+			"command":     map[string]interface{}{"const": name},
+			"success":     map[string]interface{}{"type": "boolean"},
+			"data":        dataSchema,
+			"error":       g.schema(reflect.TypeOf(ErrorInfo{})),
+		},
+		"required": []string{"api_version", "command", "success"},
+	}
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	return schema
+}
+
+// ItemSchema returns the JSON Schema of one item of a list, as written on
+// each line of NDJSON output
+func ItemSchema(name string, item interface{}) map[string]interface{} {
+	g := &schemaGenerator{defs: map[string]interface{}{}}
+	schema := g.schema(reflect.TypeOf(item))
+	schema["$schema"] = SchemaDialect
+	schema["title"] = "dccli " + name
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	return schema
+}
+
+// schemaGenerator describes Go types as they are encoded by encoding/json.
+// Named structs go into $defs so recursive types terminate.
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == numberType:
+		return map[string]interface{}{"type": "number"}
+	case t.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()):
+		// Custom encodings can be anything
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schema(t.Elem()))
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are base64 strings
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		items := map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+		if t.Kind() == reflect.Slice {
+			return nullable(items)
+		}
+		return items
+	case reflect.Map:
+		return nullable(map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": g.schema(t.Elem()),
+		})
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.String()
+		if _, ok := g.defs[name]; !ok {
+			// Placeholder first, so fields referring back to t find it
+			g.defs[name] = map[string]interface{}{}
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	default:
+		// Interfaces hold any value
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes the JSON object of a struct. Fields without
+// omitempty are always present and so required.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	g.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the JSON fields of t, including those promoted from
+// embedded structs
+func (g *schemaGenerator) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				g.addFields(fieldType, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var schema map[string]interface{}
+		if hasOption(opts, "string") {
+			schema = map[string]interface{}{"type": "string"}
+		} else {
+			schema = g.schema(fieldType)
+		}
+		properties[name] = schema
+		if !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// nullable lets a schema also match null, as nil pointers, slices and maps
+// encode to it
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	if len(schema) == 0 {
+		return schema
+	}
+	return map[string]interface{}{
+		"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}},
+	}
+}
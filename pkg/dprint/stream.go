@@ -0,0 +1,202 @@
+package dprint
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Stream writes a list one item at a time so paginated results do not have
+// to be collected before printing. JSON and YAML output is still a single
+// Envelope with the items as its data. Tables need every row to size their
+// columns, so rows are kept and rendered on Close; like Print, they take
+// their columns from the table tags of the items. Templates see the whole list, so their
+// items are collected and printed on Close as well. NDJSON, CSV and TSV rows
+// are written as they come; CSV and TSV use the table columns, or with all
+// fields take their columns from the first item.
+type Stream struct {
+	o      *OutputManager
+	header []string
+	rows   [][]string
+	items  []interface{}
+	count  int
+	csv    *csv.Writer
+	fields []string
+}
+
+// NewStream starts a list
+func (o *OutputManager) NewStream() *Stream {
+	return &Stream{o: o}
+}
+
+// Add writes one item
+func (s *Stream) Add(item interface{}) error {
+	defer func() { s.count++ }()
+
+	switch s.o.format.Kind() {
+	case FormatJSON:
+		data, err := json.MarshalIndent(item, "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		sep := ",\n    "
+		if s.count == 0 {
+			if err := s.o.streamHeader(); err != nil {
+				return err
+			}
+			sep = "\n    "
+		}
+		if _, err := fmt.Fprintf(s.o.writer, "%s%s", sep, data); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+	case FormatYAML:
+		value, err := yamlData(item)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal([]interface{}{value})
+		if err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if s.count == 0 {
+			if err := s.o.streamHeader(); err != nil {
+				return err
+			}
+			data = append([]byte("data:\n"), data...)
+		}
+		if _, err := s.o.writer.Write(data); err != nil {
+			return fmt.Errorf("failed to write YAML: %w", err)
+		}
+	case FormatCustomColumns:
+		value, err := toJSON(item)
+		if err != nil {
+			return err
+		}
+		s.rows = append(s.rows, columnRow(s.o.format.Columns(), value))
+	case FormatJSONPath, FormatGoTemplate:
+		s.items = append(s.items, item)
+	case FormatNDJSON:
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return writeNDJSON(s.o.writer, data)
+	case FormatCSV, FormatTSV:
+		return s.addRecord(item)
+	default:
+		header, rows, err := structTable(item)
+		if err != nil {
+			return err
+		}
+		s.header = header
+		s.rows = append(s.rows, rows...)
+	}
+	return nil
+}
+
+// addRecord writes an item as a CSV row, preceded by the header for the
+// first one
+func (s *Stream) addRecord(item interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	var row []string
+	if columns := s.o.format.Columns(); columns != nil {
+		if row, err = recordColumns(columns, data); err != nil {
+			return err
+		}
+	} else if header, rows, err := structTable(item); err == nil && len(rows) == 1 && !s.o.format.AllFields() {
+		if s.count == 0 {
+			s.fields = header
+		}
+		row = rows[0]
+	} else {
+		var r record
+		if err := r.flatten(data, ""); err != nil {
+			return err
+		}
+		if s.count == 0 {
+			s.fields = r.keys
+		}
+		row = r.row(s.fields)
+	}
+
+	if s.count == 0 {
+		if err := s.writeHeader(); err != nil {
+			return err
+		}
+	}
+	if err := s.csv.Write(row); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	s.csv.Flush()
+	return s.csv.Error()
+}
+
+// writeHeader starts CSV output with the column names
+func (s *Stream) writeHeader() error {
+	s.csv = s.o.csvWriter()
+	header := s.fields
+	if columns := s.o.format.Columns(); columns != nil {
+		header = columnHeaders(columns)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	if err := s.csv.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// Count returns the number of items added so far
+func (s *Stream) Count() int {
+	return s.count
+}
+
+// Close finishes the list. It must be called even when no item was added.
+func (s *Stream) Close() error {
+	var err error
+	switch s.o.format.Kind() {
+	case FormatJSON:
+		if s.count == 0 {
+			if err := s.o.streamHeader(); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(s.o.writer, "]\n}")
+		} else {
+			_, err = fmt.Fprintln(s.o.writer, "\n  ]\n}")
+		}
+	case FormatYAML:
+		if s.count == 0 {
+			if err := s.o.streamHeader(); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(s.o.writer, "data: []")
+		}
+	case FormatCustomColumns:
+		_, err = fmt.Fprintln(s.o.writer, renderTable(columnHeaders(s.o.format.Columns()), s.rows))
+	case FormatJSONPath, FormatGoTemplate:
+		return s.o.Print(s.items)
+	case FormatCSV, FormatTSV:
+		if s.count == 0 {
+			if err := s.writeHeader(); err != nil {
+				return err
+			}
+			s.csv.Flush()
+			err = s.csv.Error()
+		}
+	case FormatNDJSON:
+		// Every line was written by Add
+	default:
+		_, err = fmt.Fprintln(s.o.writer, renderTable(s.header, s.rows))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
@@ -3,11 +3,20 @@ package dprint
 import (
 	"bytes"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 )
 
 func Table(header []string, data [][]string) {
+	fmt.Println(renderTable(header, data))
+}
+
+func renderTable(header []string, data [][]string) string {
 	var raw []byte
 	buff := bytes.NewBuffer(raw)
 	table := tablewriter.NewWriter(buff)
@@ -16,5 +25,172 @@ func Table(header []string, data [][]string) {
 	table.AppendBulk(data)
 	table.Render()
 
-	fmt.Println(buff.String())
+	return buff.String()
+}
+
+// PrintTableFromStructs outputs a struct or a slice of structs as a table.
+// Column headers come from `table:"Header"` field tags and fields tagged
+// `table:"-"` are left out. A struct without table tags shows every
+// exported field under its name. Maps give a single row of their values.
+func (o *OutputManager) PrintTableFromStructs(data interface{}) error {
+	header, rows, err := structTable(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(o.writer, renderTable(header, rows)); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	return nil
+}
+
+// tableField is a struct field shown as a table column
+type tableField struct {
+	header string
+	index  int
+}
+
+func structTable(data interface{}) ([]string, [][]string, error) {
+	v := indirect(reflect.ValueOf(data))
+	if !v.IsValid() {
+		return nil, nil, nil
+	}
+
+	var items []reflect.Value
+	elemType := v.Type()
+	switch v.Kind() {
+	case reflect.Map:
+		return mapTable(v)
+	case reflect.Slice, reflect.Array:
+		elemType = v.Type().Elem()
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, v.Index(i))
+		}
+	}
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("cannot print %T as a table", data)
+	}
+	if v.Kind() == reflect.Struct {
+		items = []reflect.Value{v}
+	}
+
+	fields := tableFields(elemType)
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.header
+	}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		item = indirect(item)
+		row := make([]string, len(fields))
+		if item.IsValid() {
+			for i, field := range fields {
+				row[i] = formatField(item.Field(field.index))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return header, rows, nil
+}
+
+// tableFields returns the columns of a struct type
+func tableFields(t reflect.Type) []tableField {
+	tagged := false
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("table"); ok {
+			tagged = true
+			break
+		}
+	}
+
+	var fields []tableField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		header, ok := field.Tag.Lookup("table")
+		switch {
+		case header == "-":
+			continue
+		case !ok && tagged:
+			continue
+		case header == "":
+			header = field.Name
+		}
+		fields = append(fields, tableField{header: header, index: i})
+	}
+	return fields
+}
+
+// mapTable renders a map as one row with a column per key
+func mapTable(v reflect.Value) ([]string, [][]string, error) {
+	keys := v.MapKeys()
+	header := make([]string, len(keys))
+	for i, key := range keys {
+		header[i] = fmt.Sprint(key.Interface())
+	}
+	sort.Strings(header)
+
+	row := make([]string, len(keys))
+	for i, name := range header {
+		for _, key := range keys {
+			if fmt.Sprint(key.Interface()) == name {
+				row[i] = formatField(v.MapIndex(key))
+				break
+			}
+		}
+	}
+	return header, [][]string{row}, nil
+}
+
+// indirect follows pointers and interfaces, returning the zero Value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// formatField renders a field value for a table cell
+func formatField(v reflect.Value) string {
+	v = indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+	switch value := v.Interface().(type) {
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.Format(time.RFC3339)
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice, reflect.Array:
+		values := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values[i] = formatField(v.Index(i))
+		}
+		return strings.Join(values, ", ")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
 }
@@ -0,0 +1,248 @@
+package transcript
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// htmlTemplates render the HTML transcript. The page carries its own styles
+// so it can be archived as a single file; only custom emoji and embed images
+// are loaded from Discord.
+var htmlTemplates = template.Must(template.New("header").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>#{{.Name}}{{if .Guild}} · {{.Guild}}{{end}}</title>
+<style>
+body { margin: 0; background: #313338; color: #dbdee1; font: 15px/1.4 "gg sans", "Helvetica Neue", Helvetica, Arial, sans-serif; }
+header { padding: 16px 24px; border-bottom: 1px solid #1e1f22; }
+header h1 { margin: 0; font-size: 20px; color: #f2f3f5; }
+header p, footer { margin: 4px 0 0; color: #949ba4; font-size: 13px; }
+main { padding: 8px 0; }
+footer { padding: 16px 24px; border-top: 1px solid #1e1f22; }
+a { color: #00a8fc; text-decoration: none; }
+.message { padding: 6px 24px; }
+.message:target { background: #3f4147; }
+.reply { color: #949ba4; font-size: 13px; margin-bottom: 2px; }
+.author { color: #f2f3f5; font-weight: 600; }
+.bot { background: #5865f2; color: #fff; border-radius: 3px; padding: 0 4px; font-size: 10px; vertical-align: middle; }
+time, .edited { color: #949ba4; font-size: 12px; margin-left: 4px; }
+.content { white-space: pre-wrap; word-wrap: break-word; }
+.mention { background: rgba(88, 101, 242, .3); color: #c9cdfb; border-radius: 3px; padding: 0 2px; }
+.emoji { width: 22px; height: 22px; vertical-align: bottom; }
+code, pre { background: #2b2d31; border-radius: 4px; font-family: Consolas, Menlo, monospace; font-size: 13px; }
+code { padding: 0 3px; }
+pre { padding: 8px; margin: 4px 0; white-space: pre-wrap; }
+.attachment { margin-top: 4px; }
+.attachment img, .embed img { max-width: 400px; max-height: 300px; border-radius: 4px; display: block; }
+.file { display: inline-block; background: #2b2d31; border: 1px solid #1e1f22; border-radius: 4px; padding: 8px 12px; }
+.size { color: #949ba4; font-size: 12px; margin-left: 6px; }
+.embed { max-width: 520px; margin-top: 4px; background: #2b2d31; border-left: 4px solid #1e1f22; border-radius: 4px; padding: 8px 12px; }
+.embed-author, .embed-footer { font-size: 12px; color: #dbdee1; }
+.embed-footer { margin-top: 6px; color: #949ba4; }
+.embed-title { font-weight: 600; color: #f2f3f5; margin: 2px 0; }
+.embed-field { margin-top: 6px; }
+.embed-field-name { font-weight: 600; font-size: 13px; }
+.reactions { margin-top: 4px; }
+.reaction { display: inline-block; background: #2b2d31; border-radius: 8px; padding: 1px 6px; margin-right: 4px; font-size: 13px; }
+</style>
+</head>
+<body>
+<header>
+<h1>#{{.Name}}</h1>
+<p>{{if .Guild}}{{.Guild}} · {{end}}Channel ID {{.ID}} · Exported {{.Exported}}</p>
+</header>
+<main>
+`))
+
+func init() {
+	template.Must(htmlTemplates.New("message").Parse(`<article class="message" id="m-{{.ID}}">
+{{- with .Reply}}
+<div class="reply">↪ {{if .Deleted}}<em>Original message was deleted</em>{{else}}<a href="#m-{{.ID}}"><span class="author">{{.Author}}</span></a> {{.Preview}}{{end}}</div>
+{{- end}}
+<div><span class="author">{{.Author}}</span>{{if .Bot}} <span class="bot">BOT</span>{{end}}<time datetime="{{.Time}}">{{.TimeText}}</time>{{if .Edited}}<span class="edited">(edited)</span>{{end}}</div>
+{{- if .Content}}
+<div class="content">{{.Content}}</div>
+{{- end}}
+{{- range .Attachments}}
+<div class="attachment">{{if .Image}}<a href="{{.URL}}"><img src="{{.URL}}" alt="{{.Name}}"></a>{{else}}<a class="file" href="{{.URL}}">📎 {{.Name}}</a><span class="size">{{.Size}}</span>{{end}}</div>
+{{- end}}
+{{- range .Embeds}}
+<div class="embed" style="{{.Style}}">
+{{- if .Author}}<div class="embed-author">{{.Author}}</div>{{end}}
+{{- if .Title}}<div class="embed-title">{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</div>{{end}}
+{{- if .Description}}<div class="content">{{.Description}}</div>{{end}}
+{{- range .Fields}}<div class="embed-field"><div class="embed-field-name">{{.Name}}</div><div class="content">{{.Value}}</div></div>{{end}}
+{{- if .Image}}<img src="{{.Image}}" alt="">{{end}}
+{{- if .Footer}}<div class="embed-footer">{{.Footer}}</div>{{end}}
+</div>
+{{- end}}
+{{- if .Reactions}}
+<div class="reactions">{{range .Reactions}}<span class="reaction">{{.}}</span>{{end}}</div>
+{{- end}}
+</article>
+`))
+	template.Must(htmlTemplates.New("footer").Parse(`</main>
+<footer>{{.}} messages</footer>
+</body>
+</html>
+`))
+}
+
+// htmlMessageView is a message prepared for the message template
+type htmlMessageView struct {
+	ID          string
+	Reply       *htmlReplyView
+	Author      string
+	Bot         bool
+	Time        string
+	TimeText    string
+	Edited      bool
+	Content     template.HTML
+	Attachments []htmlAttachmentView
+	Embeds      []htmlEmbedView
+	Reactions   []string
+}
+
+type htmlReplyView struct {
+	ID      string
+	Deleted bool
+	Author  string
+	Preview string
+}
+
+type htmlAttachmentView struct {
+	Name  string
+	URL   string
+	Size  string
+	Image bool
+}
+
+type htmlEmbedView struct {
+	Style       template.CSS
+	Author      string
+	Title       string
+	URL         string
+	Description template.HTML
+	Fields      []htmlFieldView
+	Image       string
+	Footer      string
+}
+
+type htmlFieldView struct {
+	Name  string
+	Value template.HTML
+}
+
+func (t *Writer) htmlHeader() error {
+	return htmlTemplates.ExecuteTemplate(t.w, "header", map[string]string{
+		"ID":       t.channel.ID,
+		"Name":     t.channel.Name,
+		"Guild":    t.channel.Guild,
+		"Exported": time.Now().UTC().Format(timeLayout),
+	})
+}
+
+func (t *Writer) htmlFooter() error {
+	return htmlTemplates.ExecuteTemplate(t.w, "footer", t.count)
+}
+
+func (t *Writer) htmlMessage(m *discordgo.Message) error {
+	view := htmlMessageView{
+		ID:       m.ID,
+		Author:   displayName(m.Author),
+		Bot:      m.Author != nil && m.Author.Bot,
+		Time:     m.Timestamp.UTC().Format(time.RFC3339),
+		TimeText: m.Timestamp.UTC().Format(timeLayout),
+		Edited:   m.EditedTimestamp != nil,
+		Content:  t.htmlContent(m, m.Content),
+	}
+
+	if m.MessageReference != nil && m.Type == discordgo.MessageTypeReply {
+		view.Reply = &htmlReplyView{Deleted: true}
+		if ref := m.ReferencedMessage; ref != nil {
+			view.Reply = &htmlReplyView{ID: ref.ID, Author: displayName(ref.Author), Preview: t.replyPreview(ref)}
+		}
+	}
+
+	for _, a := range m.Attachments {
+		view.Attachments = append(view.Attachments, htmlAttachmentView{
+			Name:  a.Filename,
+			URL:   t.attachmentURL(a),
+			Size:  formatSize(a.Size),
+			Image: isImage(a),
+		})
+	}
+
+	for _, e := range m.Embeds {
+		embed := htmlEmbedView{
+			Title:       e.Title,
+			URL:         e.URL,
+			Description: t.htmlContent(m, e.Description),
+		}
+		if e.Color != 0 {
+			embed.Style = template.CSS(fmt.Sprintf("border-left-color: #%06x", e.Color))
+		}
+		if e.Author != nil {
+			embed.Author = e.Author.Name
+		}
+		for _, f := range e.Fields {
+			embed.Fields = append(embed.Fields, htmlFieldView{Name: f.Name, Value: t.htmlContent(m, f.Value)})
+		}
+		if e.Image != nil {
+			embed.Image = e.Image.URL
+		}
+		if e.Footer != nil {
+			embed.Footer = e.Footer.Text
+		}
+		view.Embeds = append(view.Embeds, embed)
+	}
+
+	for _, r := range m.Reactions {
+		view.Reactions = append(view.Reactions, fmt.Sprintf("%s %d", reactionName(r), r.Count))
+	}
+
+	return htmlTemplates.ExecuteTemplate(t.w, "message", view)
+}
+
+// Discord markdown handled in HTML transcripts, applied to escaped text
+var htmlMarkdown = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	{regexp.MustCompile("```(?:[a-zA-Z0-9_+-]*\n)?((?s).*?)```"), "<pre>$1</pre>"},
+	{regexp.MustCompile("`([^`\n]+)`"), "<code>$1</code>"},
+	{regexp.MustCompile(`\*\*(.+?)\*\*`), "<strong>$1</strong>"},
+	{regexp.MustCompile(`__(.+?)__`), "<u>$1</u>"},
+	{regexp.MustCompile(`\*([^*\n]+)\*`), "<em>$1</em>"},
+	{regexp.MustCompile(`~~(.+?)~~`), "<s>$1</s>"},
+	{regexp.MustCompile(`https?://[^\s<]+`), `<a href="$0">$0</a>`},
+}
+
+// htmlContent renders message content with mentions resolved and basic
+// Discord markdown
+func (t *Writer) htmlContent(m *discordgo.Message, content string) template.HTML {
+	var b strings.Builder
+	for _, tok := range t.tokenize(m, content) {
+		switch {
+		case tok.mention != "":
+			fmt.Fprintf(&b, `<span class="mention">%s</span>`, html.EscapeString(tok.mention))
+		case tok.emoji != "":
+			fmt.Fprintf(&b, `<img class="emoji" src="%s" alt="%s">`, html.EscapeString(tok.emoji), html.EscapeString(tok.text))
+		default:
+			text := html.EscapeString(tok.text)
+			for _, md := range htmlMarkdown {
+				text = md.pattern.ReplaceAllString(text, md.replace)
+			}
+			b.WriteString(text)
+		}
+	}
+	return template.HTML(strings.TrimSpace(b.String()))
+}
@@ -0,0 +1,128 @@
+package transcript
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (t *Writer) markdownHeader() error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# #%s\n\n", t.channel.Name)
+	if t.channel.Guild != "" {
+		fmt.Fprintf(&b, "- Guild: %s\n", t.channel.Guild)
+	}
+	fmt.Fprintf(&b, "- Channel ID: %s\n", t.channel.ID)
+	fmt.Fprintf(&b, "- Exported: %s\n\n---\n\n", time.Now().UTC().Format(timeLayout))
+	_, err := fmt.Fprint(t.w, b.String())
+	return err
+}
+
+func (t *Writer) markdownFooter() error {
+	_, err := fmt.Fprintf(t.w, "---\n\n%d messages\n", t.count)
+	return err
+}
+
+func (t *Writer) markdownMessage(m *discordgo.Message) error {
+	var b strings.Builder
+
+	if m.MessageReference != nil && m.Type == discordgo.MessageTypeReply {
+		if ref := m.ReferencedMessage; ref != nil {
+			fmt.Fprintf(&b, "> ↪ **%s**: %s\n\n", displayName(ref.Author), t.replyPreview(ref))
+		} else {
+			b.WriteString("> ↪ *Original message was deleted*\n\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "**%s**", displayName(m.Author))
+	if m.Author != nil && m.Author.Bot {
+		b.WriteString(" `BOT`")
+	}
+	fmt.Fprintf(&b, " · %s", m.Timestamp.UTC().Format(timeLayout))
+	if m.EditedTimestamp != nil {
+		b.WriteString(" *(edited)*")
+	}
+	b.WriteString("\n\n")
+
+	if content := t.markdownContent(m, m.Content); content != "" {
+		b.WriteString(content + "\n\n")
+	}
+
+	for _, a := range m.Attachments {
+		if isImage(a) {
+			fmt.Fprintf(&b, "![%s](%s)\n\n", a.Filename, markdownURL(t.attachmentURL(a)))
+		} else {
+			fmt.Fprintf(&b, "📎 [%s](%s) (%s)\n\n", a.Filename, markdownURL(t.attachmentURL(a)), formatSize(a.Size))
+		}
+	}
+
+	for _, e := range m.Embeds {
+		t.markdownEmbed(&b, m, e)
+	}
+
+	if len(m.Reactions) > 0 {
+		reactions := make([]string, len(m.Reactions))
+		for i, r := range m.Reactions {
+			reactions[i] = fmt.Sprintf("%s %d", reactionName(r), r.Count)
+		}
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(reactions, " · "))
+	}
+
+	_, err := fmt.Fprint(t.w, b.String())
+	return err
+}
+
+// markdownContent resolves the mentions of content, which is already Markdown
+func (t *Writer) markdownContent(m *discordgo.Message, content string) string {
+	var b strings.Builder
+	for _, tok := range t.tokenize(m, content) {
+		switch {
+		case tok.mention != "":
+			b.WriteString("**" + tok.mention + "**")
+		default:
+			b.WriteString(tok.text)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// markdownEmbed writes an embed as a block quote
+func (t *Writer) markdownEmbed(b *strings.Builder, m *discordgo.Message, e *discordgo.MessageEmbed) {
+	var lines []string
+	if e.Author != nil && e.Author.Name != "" {
+		lines = append(lines, "*"+e.Author.Name+"*")
+	}
+	switch {
+	case e.Title != "" && e.URL != "":
+		lines = append(lines, fmt.Sprintf("**[%s](%s)**", e.Title, markdownURL(e.URL)))
+	case e.Title != "":
+		lines = append(lines, "**"+e.Title+"**")
+	}
+	if e.Description != "" {
+		lines = append(lines, strings.Split(t.markdownContent(m, e.Description), "\n")...)
+	}
+	for _, f := range e.Fields {
+		lines = append(lines, "**"+f.Name+"**")
+		lines = append(lines, strings.Split(t.markdownContent(m, f.Value), "\n")...)
+	}
+	if e.Image != nil && e.Image.URL != "" {
+		lines = append(lines, fmt.Sprintf("![](%s)", markdownURL(e.Image.URL)))
+	}
+	if e.Footer != nil && e.Footer.Text != "" {
+		lines = append(lines, "*"+e.Footer.Text+"*")
+	}
+	if len(lines) == 0 {
+		return
+	}
+	for _, line := range lines {
+		fmt.Fprintf(b, "> %s\n", line)
+	}
+	b.WriteString("\n")
+}
+
+// markdownURL escapes the characters that would end a Markdown link
+func markdownURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(url)
+}
@@ -0,0 +1,289 @@
+// Package transcript writes channel history as an NDJSON archive, a Markdown
+// transcript or a single-file HTML transcript, one message at a time.
+//
+//	w, _ := transcript.NewWriter(file, transcript.FormatHTML, transcript.Channel{Name: "ticket-42"})
+//	for _, m := range messages {
+//		w.WriteMessage(m)
+//	}
+//	w.Close()
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Transcript formats
+const (
+	FormatNDJSON   = "ndjson"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats lists the formats NewWriter accepts
+var Formats = []string{FormatNDJSON, FormatMarkdown, FormatHTML}
+
+// timeLayout is how message times are shown
+const timeLayout = "2006-01-02 15:04 MST"
+
+// Channel describes the exported channel for the transcript header. Roles and
+// Channels map IDs to names so role and channel mentions can be shown by
+// name; users are named from the messages that mention them or that they
+// wrote.
+type Channel struct {
+	ID       string
+	Name     string
+	Guild    string
+	Roles    map[string]string
+	Channels map[string]string
+}
+
+// Writer writes messages in one of the transcript formats. Messages are
+// expected oldest first.
+type Writer struct {
+	w       io.Writer
+	format  string
+	channel Channel
+	count   int
+	started bool
+	users   map[string]string // Names of the users seen so far, by ID
+
+	// Files maps attachment IDs to downloaded copies, as paths relative to
+	// the transcript. Attachments missing from it link to Discord's CDN.
+	Files map[string]string
+}
+
+// NewWriter returns a Writer for the given format
+func NewWriter(w io.Writer, format string, channel Channel) (*Writer, error) {
+	switch format {
+	case FormatNDJSON, FormatMarkdown, FormatHTML:
+	default:
+		return nil, fmt.Errorf("invalid transcript format: %s (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+	return &Writer{w: w, format: format, channel: channel, users: map[string]string{}, Files: map[string]string{}}, nil
+}
+
+// Count returns the number of messages written so far
+func (t *Writer) Count() int {
+	return t.count
+}
+
+// WriteMessage writes one message
+func (t *Writer) WriteMessage(m *discordgo.Message) error {
+	if err := t.start(); err != nil {
+		return err
+	}
+	t.rememberUsers(m)
+	var err error
+	switch t.format {
+	case FormatNDJSON:
+		err = json.NewEncoder(t.w).Encode(m)
+	case FormatMarkdown:
+		err = t.markdownMessage(m)
+	case FormatHTML:
+		err = t.htmlMessage(m)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write message %s: %w", m.ID, err)
+	}
+	t.count++
+	return nil
+}
+
+// Close finishes the transcript. It does not close the underlying writer.
+func (t *Writer) Close() error {
+	if err := t.start(); err != nil {
+		return err
+	}
+	switch t.format {
+	case FormatMarkdown:
+		return t.markdownFooter()
+	case FormatHTML:
+		return t.htmlFooter()
+	}
+	return nil
+}
+
+// start writes the header before the first message
+func (t *Writer) start() error {
+	if t.started {
+		return nil
+	}
+	t.started = true
+	switch t.format {
+	case FormatMarkdown:
+		return t.markdownHeader()
+	case FormatHTML:
+		return t.htmlHeader()
+	}
+	return nil
+}
+
+// attachmentURL returns the downloaded copy of an attachment, or its URL
+func (t *Writer) attachmentURL(a *discordgo.MessageAttachment) string {
+	if path, ok := t.Files[a.ID]; ok {
+		return path
+	}
+	return a.URL
+}
+
+// markupPattern matches the mention and timestamp markup of message content
+var markupPattern = regexp.MustCompile(`<(@!?|@&|#)(\d+)>|<(a?):(\w+):(\d+)>|<t:(-?\d+)(?::[tTdDfFR])?>`)
+
+// token is a piece of message content: plain text or resolved markup
+type token struct {
+	text    string
+	mention string // Resolved name of a user, role or channel mention
+	emoji   string // Custom emoji CDN URL, with text as its :name:
+}
+
+// tokenize splits content into text and mentions, with IDs resolved to
+// names. Unknown IDs are kept as they are.
+func (t *Writer) tokenize(m *discordgo.Message, content string) []token {
+	var tokens []token
+	last := 0
+	for _, match := range markupPattern.FindAllStringSubmatchIndex(content, -1) {
+		if match[0] > last {
+			tokens = append(tokens, token{text: content[last:match[0]]})
+		}
+		last = match[1]
+		group := func(i int) string {
+			if match[2*i] < 0 {
+				return ""
+			}
+			return content[match[2*i]:match[2*i+1]]
+		}
+
+		switch {
+		case group(2) != "":
+			tokens = append(tokens, token{text: group(0), mention: t.mentionName(m, group(1), group(2))})
+		case group(5) != "":
+			ext := "png"
+			if group(3) == "a" {
+				ext = "gif"
+			}
+			tokens = append(tokens, token{text: ":" + group(4) + ":", emoji: "https://cdn.discordapp.com/emojis/" + group(5) + "." + ext})
+		default:
+			seconds, _ := strconv.ParseInt(group(6), 10, 64)
+			tokens = append(tokens, token{text: time.Unix(seconds, 0).UTC().Format(timeLayout)})
+		}
+	}
+	if last < len(content) {
+		tokens = append(tokens, token{text: content[last:]})
+	}
+	return tokens
+}
+
+// rememberUsers records the names of the users a message shows, so mentions
+// of them are resolved in later messages too, e.g. in embeds
+func (t *Writer) rememberUsers(m *discordgo.Message) {
+	for _, user := range append([]*discordgo.User{m.Author}, m.Mentions...) {
+		if user != nil {
+			t.users[user.ID] = displayName(user)
+		}
+	}
+}
+
+// mentionName resolves a mention to "@name" or "#name"
+func (t *Writer) mentionName(m *discordgo.Message, kind, id string) string {
+	switch kind {
+	case "@&":
+		if name, ok := t.channel.Roles[id]; ok {
+			return "@" + name
+		}
+		return "@" + id
+	case "#":
+		if name, ok := t.channel.Channels[id]; ok {
+			return "#" + name
+		}
+		return "#" + id
+	}
+	for _, user := range m.Mentions {
+		if user.ID == id {
+			return "@" + displayName(user)
+		}
+	}
+	if name, ok := t.users[id]; ok {
+		return "@" + name
+	}
+	return "@" + id
+}
+
+// plainContent returns content with mentions resolved, for reply previews
+func (t *Writer) plainContent(m *discordgo.Message) string {
+	var b strings.Builder
+	for _, tok := range t.tokenize(m, m.Content) {
+		if tok.mention != "" {
+			b.WriteString(tok.mention)
+		} else {
+			b.WriteString(tok.text)
+		}
+	}
+	return b.String()
+}
+
+// replyPreview is the first line of a replied-to message, shortened
+func (t *Writer) replyPreview(m *discordgo.Message) string {
+	text, _, _ := strings.Cut(t.plainContent(m), "\n")
+	if runes := []rune(text); len(runes) > 80 {
+		text = string(runes[:80]) + "…"
+	}
+	if text == "" && len(m.Attachments)+len(m.Embeds) > 0 {
+		text = "(attachment)"
+	}
+	return text
+}
+
+// displayName returns a user's global name, or username if they have none
+func displayName(u *discordgo.User) string {
+	if u == nil {
+		return "unknown"
+	}
+	if u.GlobalName != "" {
+		return u.GlobalName
+	}
+	return u.Username
+}
+
+// reactionName shows a reaction emoji as itself, or :name: for custom ones
+func reactionName(r *discordgo.MessageReactions) string {
+	if r.Emoji == nil {
+		return "?"
+	}
+	if r.Emoji.ID != "" {
+		return ":" + r.Emoji.Name + ":"
+	}
+	return r.Emoji.Name
+}
+
+// isImage reports whether an attachment can be shown inline
+func isImage(a *discordgo.MessageAttachment) bool {
+	if strings.HasPrefix(a.ContentType, "image/") {
+		return true
+	}
+	name := strings.ToLower(a.Filename)
+	for _, ext := range []string{".png", ".jpg", ".jpeg", ".gif", ".webp"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSize shows a byte count in B, KB or MB
+func formatSize(size int) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -22,13 +23,24 @@ const (
 	tokenKey        contextKey = "token"
 )
 
+// ClientFactory creates the Discord client used by commands
+type ClientFactory func(token string, opts ...discord.ClientOption) (discord.DiscordAPI, error)
+
+// NewClient is the ClientFactory used by NewCLIContext. Tools embedding the
+// command tree can replace it to wrap or mock the client.
+var NewClient ClientFactory = func(token string, opts ...discord.ClientOption) (discord.DiscordAPI, error) {
+	return discord.NewClient(token, opts...)
+}
+
 // CLIContext holds all the shared context for CLI commands
 type CLIContext struct {
 	OutputFormat dprint.OutputFormat
 	BotConfig    *cfg.BotConfig
-	Client       *discord.DiscordClient
-	Token        string // Direct token override
-	Quiet        bool   // Suppress status messages
+	Client       discord.DiscordAPI
+	Resolver     *Resolver // Turns names and mentions into IDs
+	Token        string    // Direct token override
+	Reason       string    // Audit log reason attached to mutating requests
+	Quiet        bool      // Suppress status messages
 }
 
 // NewCLIContext creates a CLIContext from urfave/cli context
@@ -38,17 +50,13 @@ func NewCLIContext(c *cli.Command) (*CLIContext, error) {
 	// Parse quiet flag
 	ctx.Quiet = c.Bool("quiet")
 
-	// Parse output format
-	formatStr := c.String("output")
-	if formatStr == "" {
-		formatStr = "table"
-	}
-	format, err := dprint.ParseFormat(formatStr)
+	activeContext, err := ActiveContext(c)
 	if err != nil {
-		// Log the error but continue with default format
-		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil, err
 	}
-	ctx.OutputFormat = format
+
+	ctx.OutputFormat = outputFormat(c, activeContext)
+	SetErrorFormat(ctx.OutputFormat)
 
 	// Get token override
 	ctx.Token = c.String("token")
@@ -60,26 +68,118 @@ func NewCLIContext(c *cli.Command) (*CLIContext, error) {
 	}
 	ctx.BotConfig = botConfig
 
+	// Audit log reason: --reason flag, then the bot's configured default
+	ctx.Reason = c.String("reason")
+	if ctx.Reason == "" && botConfig != nil {
+		ctx.Reason = botConfig.DefaultReason
+	}
+
 	// Create Discord client if we have a token
-	if ctx.Token != "" {
-		client, err := discord.NewClient(ctx.Token)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Discord client: %w", err)
+	token := ctx.Token
+	if token == "" && botConfig != nil {
+		token = botConfig.Bot.Token
+	}
+	if token != "" {
+		opts := ClientOptions(c, botConfig)
+		if ctx.Reason != "" {
+			opts = append(opts, discord.WithAuditLogReason(ctx.Reason))
 		}
-		ctx.Client = client
-	} else if botConfig != nil {
-		client, err := discord.NewClient(botConfig.Bot.Token)
+		if c.Bool("dry-run") {
+			opts = append(opts, discord.WithDryRun(ctx.printDryRun))
+		}
+		client, err := NewClient(token, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Discord client: %w", err)
 		}
 		ctx.Client = client
+
+		// Flags like --guild and --channel also accept names and mentions
+		ctx.Resolver = NewResolver(client)
+		if err := ctx.Resolver.resolveFlags(c); err != nil {
+			return nil, err
+		}
 	}
 
 	return ctx, nil
 }
 
+// NewOutputManager creates an OutputManager for commands that work without a
+// Discord client, resolving the format as NewCLIContext does. A config that
+// cannot be read leaves the format to --output, so commands like config
+// validate still run; an unknown --context is reported before any command.
+func NewOutputManager(c *cli.Command) *dprint.OutputManager {
+	activeContext, _ := ActiveContext(c)
+	format := outputFormat(c, activeContext)
+	SetErrorFormat(format)
+	return dprint.NewOutputManager(dprint.WithFormat(format))
+}
+
+// outputFormat returns the --output flag, then the active context's default,
+// then table
+func outputFormat(c *cli.Command, activeContext *cfg.Context) dprint.OutputFormat {
+	formatStr := c.String("output")
+	if !c.IsSet("output") && activeContext != nil && activeContext.Output != "" {
+		formatStr = activeContext.Output
+	}
+	if formatStr == "" {
+		formatStr = "table"
+	}
+	format, err := dprint.ParseFormat(formatStr)
+	if err != nil {
+		// Log the error but continue with default format
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return format
+}
+
+// ClientOptions builds endpoint overrides for the Discord client
+// Priority: 1) --api-base/--gateway-url flags, 2) bot config
+func ClientOptions(c *cli.Command, botConfig *cfg.BotConfig) []discord.ClientOption {
+	var apiBase, gatewayURL string
+	if botConfig != nil {
+		apiBase = botConfig.APIBase
+		gatewayURL = botConfig.GatewayURL
+	}
+	if v := c.String("api-base"); v != "" {
+		apiBase = v
+	}
+	if v := c.String("gateway-url"); v != "" {
+		gatewayURL = v
+	}
+
+	var opts []discord.ClientOption
+	if apiBase != "" {
+		opts = append(opts, discord.WithAPIBase(apiBase))
+	}
+	if gatewayURL != "" {
+		opts = append(opts, discord.WithGatewayURL(gatewayURL))
+	}
+	return opts
+}
+
+// printDryRun reports a request that --dry-run kept from being sent
+func (ctx *CLIContext) printDryRun(req *discord.DryRunRequest) {
+	output := ctx.GetOutputManager()
+	if output.GetFormat() != dprint.FormatTable {
+		if err := output.Print(req); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return
+	}
+
+	fmt.Printf("DRY RUN: %s %s\n", req.Method, req.Route)
+	if req.Body != nil {
+		body, _ := json.MarshalIndent(req.Body, "", "  ")
+		fmt.Println(string(body))
+	}
+	for _, file := range req.Files {
+		fmt.Printf("File: %s\n", file)
+	}
+}
+
 // GetBotConfig determines which bot configuration to use
-// Priority: 1) --token flag, 2) --bot flag, 3) current from config
+// Priority: 1) --token flag, 2) --bot flag, 3) active context, 4) current from config
+// Encrypted tokens and token-command/file/env sources are resolved into Bot.Token.
 func GetBotConfig(c *cli.Command, tokenOverride string) (*cfg.BotConfig, error) {
 	// If token is provided directly, we don't need a config
 	if tokenOverride != "" {
@@ -93,21 +193,87 @@ func GetBotConfig(c *cli.Command, tokenOverride string) (*cfg.BotConfig, error)
 
 	config, err := cfg.LoadConfig()
 	if err != nil {
-		return nil, err
+		return nil, ConfigErrorf("failed to load config: %w", err)
 	}
 
-	// Check for --bot flag override
+	// Check for --bot flag override, then the bot bound to the active context
 	botName := c.String("bot")
+	if botName == "" {
+		activeContext, err := ActiveContext(c)
+		if err != nil {
+			return nil, err
+		}
+		if activeContext != nil {
+			botName = activeContext.Bot
+		}
+	}
+	var bot *cfg.BotConfig
 	if botName != "" {
-		bot, err := config.GetBotByName(botName)
+		bot, err = config.GetBotByName(botName)
 		if err != nil {
 			return nil, fmt.Errorf("bot '%s' not found in config: %w", botName, err)
 		}
-		return bot, nil
+	} else {
+		// Use current bot from config
+		bot, err = config.GetCurrent()
+		if err != nil {
+			return nil, ConfigErrorf("%w", err)
+		}
+	}
+
+	return NewTokenResolver(c, config).Resolve(bot)
+}
+
+// TokenResolver fills in the tokens of configured bots. The vault is
+// unlocked at most once, so resolving several encrypted bots prompts for the
+// passphrase only once.
+type TokenResolver struct {
+	c      *cli.Command
+	config *cfg.Config
+	key    *cfg.VaultKey
+	err    error
+}
+
+// NewTokenResolver creates a TokenResolver for the bots in config
+func NewTokenResolver(c *cli.Command, config *cfg.Config) *TokenResolver {
+	return &TokenResolver{c: c, config: config}
+}
+
+// Resolve returns bot with Bot.Token decrypted or read from its token source.
+// The bot is copied when the token changes so the plaintext never ends up in
+// a saved config.
+func (r *TokenResolver) Resolve(bot *cfg.BotConfig) (*cfg.BotConfig, error) {
+	if bot.Encrypted() {
+		if r.key == nil && r.err == nil {
+			r.key, r.err = UnlockVault(r.c, r.config)
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		decrypted := *bot
+		if err := decrypted.DecryptToken(r.key); err != nil {
+			return nil, ConfigErrorf("%w", err)
+		}
+		return &decrypted, nil
 	}
+	if bot.Bot.Token == "" && bot.Bot.HasTokenSource() {
+		token, err := bot.Bot.ResolveToken()
+		if err != nil {
+			return nil, ConfigErrorf("failed to get token of bot '%s': %w", bot.Name, err)
+		}
+		resolved := *bot
+		resolved.Bot.Token = token
+		return &resolved, nil
+	}
+	return bot, nil
+}
 
-	// Use current bot from config
-	return config.GetCurrent()
+// Statusf prints a progress message to stderr unless --quiet is given, so
+// stdout only carries the command's output
+func (ctx *CLIContext) Statusf(format string, args ...interface{}) {
+	if !ctx.Quiet {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
 }
 
 // GetOutputManager creates an OutputManager from CLI context
@@ -118,9 +284,8 @@ func (ctx *CLIContext) GetOutputManager() *dprint.OutputManager {
 // Close cleans up resources (like Discord client connection)
 func (ctx *CLIContext) Close() {
 	if ctx.Client != nil {
-		// Discord client session should be closed
-		// This would require adding a Close method to DiscordClient
-		// For now, we assume the session will be garbage collected
+		// Only closes the gateway if a command actually opened it
+		ctx.Client.Close()
 	}
 }
 
@@ -152,8 +317,8 @@ func GetBotConfigFromContext(ctx context.Context) *cfg.BotConfig {
 }
 
 // GetClientFromContext retrieves Discord client from context
-func GetClientFromContext(ctx context.Context) *discord.DiscordClient {
-	if client, ok := ctx.Value(clientKey).(*discord.DiscordClient); ok {
+func GetClientFromContext(ctx context.Context) discord.DiscordAPI {
+	if client, ok := ctx.Value(clientKey).(discord.DiscordAPI); ok {
 		return client
 	}
 	return nil
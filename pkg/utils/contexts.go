@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/cfg"
+)
+
+// ActiveContext returns the context selected with --context, or the current
+// context from config. It returns nil when neither is set.
+func ActiveContext(c *cli.Command) (*cfg.Context, error) {
+	name := c.String("context")
+	config, err := cfg.LoadConfig()
+	if err != nil {
+		if errors.Is(err, cfg.ErrNotConfigured) && name == "" {
+			return nil, nil
+		}
+		return nil, ConfigErrorf("failed to load config: %w", err)
+	}
+	if name == "" {
+		name = config.CurrentContext
+	}
+	if name == "" {
+		return nil, nil
+	}
+	ctx, err := config.GetContextByName(name)
+	if err != nil {
+		return nil, NotFoundErrorf("context '%s' not found in config", name)
+	}
+	return ctx, nil
+}
+
+// contextDefaults maps flag names to the context field they fall back to
+var contextDefaults = map[string]func(*cfg.Context) string{
+	"guild":   func(ctx *cfg.Context) string { return ctx.Guild },
+	"channel": func(ctx *cfg.Context) string { return ctx.Channel },
+}
+
+// contextSource is a flag value source reading a field of the active
+// context. It comes after the flag itself and its environment variable.
+type contextSource struct {
+	root  *cli.Command
+	name  string
+	field func(*cfg.Context) string
+}
+
+func (s *contextSource) Lookup() (string, bool) {
+	// Sources are read while the command's flags are parsed, before the
+	// root Before applies --config
+	cfg.SetPath(s.root.String("config"))
+	ctx, err := ActiveContext(s.root)
+	if err != nil || ctx == nil {
+		return "", false
+	}
+	value := s.field(ctx)
+	return value, value != ""
+}
+
+func (s *contextSource) String() string {
+	return fmt.Sprintf("context %s", s.name)
+}
+
+func (s *contextSource) GoString() string {
+	return fmt.Sprintf("&contextSource{name:%q}", s.name)
+}
+
+// contextDefaultsSkip lists commands whose --guild or --channel is a value to
+// write, not where to act, so a context must not fill it in
+var contextDefaultsSkip = map[string]bool{
+	"config context set": true,
+	"webhooks edit":      true,
+}
+
+// ApplyContextDefaults lets every --guild and --channel flag in the command
+// tree fall back to the active context, except in contextDefaultsSkip.
+func ApplyContextDefaults(root *cli.Command) {
+	var walk func(cmd *cli.Command, path string)
+	walk = func(cmd *cli.Command, path string) {
+		if !contextDefaultsSkip[path] {
+			for _, flag := range cmd.Flags {
+				f, ok := flag.(*cli.StringFlag)
+				if !ok {
+					continue
+				}
+				if field, ok := contextDefaults[f.Name]; ok {
+					f.Sources.Chain = append(f.Sources.Chain, &contextSource{root: root, name: f.Name, field: field})
+				}
+			}
+		}
+		for _, sub := range cmd.Commands {
+			if path == "" {
+				walk(sub, sub.Name)
+			} else {
+				walk(sub, path+" "+sub.Name)
+			}
+		}
+	}
+	walk(root, "")
+}
@@ -1,8 +1,13 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
 )
 
 // ExitCode represents the exit code for the application
@@ -21,13 +26,59 @@ const (
 	ExitValidationError ExitCode = 4
 	// ExitNotFound indicates a resource was not found
 	ExitNotFound ExitCode = 5
+	// ExitPermissionDenied indicates Discord refused the request because
+	// the bot lacks access or a permission
+	ExitPermissionDenied ExitCode = 6
 )
 
+// exitCodeTypes names the exit codes in the error envelope of JSON and YAML
+// output
+var exitCodeTypes = map[ExitCode]string{
+	ExitError:            "error",
+	ExitConfigError:      "config",
+	ExitDiscordError:     "discord",
+	ExitValidationError:  "validation",
+	ExitNotFound:         "not_found",
+	ExitPermissionDenied: "permission",
+}
+
+// Type returns the name of the exit code in error envelopes
+func (c ExitCode) Type() string {
+	if name, ok := exitCodeTypes[c]; ok {
+		return name
+	}
+	return exitCodeTypes[ExitError]
+}
+
+// ErrOutputSchema is returned by commands that printed their output schema
+// for --output-schema instead of running
+var ErrOutputSchema = errors.New("output schema printed")
+
+// errorFormat is the output format HandleError reports errors in
+var errorFormat dprint.OutputFormat
+
+// SetErrorFormat sets the output format of the running command. With JSON or
+// YAML, HandleError writes errors to stderr as an error envelope so scripts
+// can parse them the same way as the output.
+func SetErrorFormat(format dprint.OutputFormat) {
+	errorFormat = format
+}
+
 // CLIError is the base error type for CLI errors
 type CLIError struct {
 	Message string
 	Code    ExitCode
 	Cause   error
+
+	// Details of a failed Discord API request, see DiscordErrorf
+	HTTPStatus  int                 // HTTP status code of the response
+	DiscordCode int                 // Discord JSON error code, e.g. 10011 for Unknown Role
+	FieldErrors []dprint.FieldError // Rejected fields of the request body
+	RetryAfter  time.Duration       // How long to wait before retrying a rate limited request
+
+	// wrapped is the %w operand of a formatted error. Its text is already part
+	// of Message, so it is only exposed through Unwrap.
+	wrapped error
 }
 
 // Error implements the error interface
@@ -40,7 +91,38 @@ func (e *CLIError) Error() string {
 
 // Unwrap returns the underlying error
 func (e *CLIError) Unwrap() error {
-	return e.Cause
+	if e.Cause != nil {
+		return e.Cause
+	}
+	return e.wrapped
+}
+
+// Info returns the error as it appears in the error envelope of JSON and
+// YAML output
+func (e *CLIError) Info() dprint.ErrorInfo {
+	return dprint.ErrorInfo{
+		Type:       e.Code.Type(),
+		ExitCode:   int(e.Code),
+		Message:    e.Error(),
+		HTTPStatus: e.HTTPStatus,
+		Code:       e.DiscordCode,
+		Errors:     e.FieldErrors,
+		RetryAfter: e.RetryAfter.Seconds(),
+	}
+}
+
+// print writes the error to stderr as text
+func (e *CLIError) print() {
+	fmt.Fprintf(os.Stderr, "Error: %s\n", e.Message)
+	if e.Cause != nil {
+		fmt.Fprintf(os.Stderr, "  Cause: %v\n", e.Cause)
+	}
+	for _, field := range e.FieldErrors {
+		fmt.Fprintf(os.Stderr, "  %s: %s (%s)\n", field.Field, field.Message, field.Code)
+	}
+	if e.RetryAfter > 0 {
+		fmt.Fprintf(os.Stderr, "  Retry after: %s\n", e.RetryAfter)
+	}
 }
 
 // Exit exits the application with the appropriate exit code
@@ -65,6 +147,16 @@ func NewErrorWithCause(message string, code ExitCode, cause error) *CLIError {
 	}
 }
 
+// newErrorf formats a CLIError and keeps any %w operand reachable by errors.Is
+func newErrorf(code ExitCode, format string, args ...interface{}) *CLIError {
+	err := fmt.Errorf(format, args...)
+	return &CLIError{
+		Message: err.Error(),
+		Code:    code,
+		wrapped: errors.Unwrap(err),
+	}
+}
+
 // Common error constructors
 
 // ConfigError creates a configuration error
@@ -74,7 +166,7 @@ func ConfigError(message string) *CLIError {
 
 // ConfigErrorf creates a formatted configuration error
 func ConfigErrorf(format string, args ...interface{}) *CLIError {
-	return NewError(fmt.Errorf(format, args...).Error(), ExitConfigError)
+	return newErrorf(ExitConfigError, format, args...)
 }
 
 // DiscordError creates a Discord API error
@@ -82,9 +174,13 @@ func DiscordError(message string) *CLIError {
 	return NewError(message, ExitDiscordError)
 }
 
-// DiscordErrorf creates a formatted Discord API error
+// DiscordErrorf creates a formatted Discord API error. When the %w operand
+// is a REST error its status, code and field errors are kept, and 404s and
+// 403s exit with ExitNotFound and ExitPermissionDenied.
 func DiscordErrorf(format string, args ...interface{}) *CLIError {
-	return NewError(fmt.Errorf(format, args...).Error(), ExitDiscordError)
+	err := newErrorf(ExitDiscordError, format, args...)
+	err.addRESTDetails()
+	return err
 }
 
 // ValidationError creates a validation error
@@ -94,7 +190,7 @@ func ValidationError(message string) *CLIError {
 
 // ValidationErrorf creates a formatted validation error
 func ValidationErrorf(format string, args ...interface{}) *CLIError {
-	return NewError(fmt.Errorf(format, args...).Error(), ExitValidationError)
+	return newErrorf(ExitValidationError, format, args...)
 }
 
 // NotFoundError creates a not found error
@@ -104,7 +200,7 @@ func NotFoundError(resource string) *CLIError {
 
 // NotFoundErrorf creates a formatted not found error
 func NotFoundErrorf(format string, args ...interface{}) *CLIError {
-	return NewError(fmt.Errorf(format, args...).Error(), ExitNotFound)
+	return newErrorf(ExitNotFound, format, args...)
 }
 
 // WrapError wraps an error with a CLIError
@@ -121,11 +217,38 @@ func HandleError(err error) {
 		return
 	}
 
-	if cliErr, ok := err.(*CLIError); ok {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", cliErr.Message)
-		if cliErr.Cause != nil {
-			fmt.Fprintf(os.Stderr, "  Cause: %v\n", cliErr.Cause)
+	// A dry run aborts at the first mutating request after printing it,
+	// so this is a successful run
+	if errors.Is(err, discord.ErrDryRun) {
+		fmt.Fprintln(os.Stderr, "Dry run: nothing was sent. The command stopped at the last request shown; requests after it are not previewed.")
+		return
+	}
+	if errors.Is(err, ErrOutputSchema) {
+		return
+	}
+
+	// Commands returning a client error as is still get its details
+	cliErr, ok := err.(*CLIError)
+	if !ok && isRESTError(err) {
+		cliErr, ok = DiscordErrorf("%w", err), true
+	}
+
+	if errorFormat.Enveloped() {
+		info := dprint.ErrorInfo{
+			Type:     ExitError.Type(),
+			ExitCode: int(ExitError),
+			Message:  err.Error(),
 		}
+		if ok {
+			info = cliErr.Info()
+		}
+		if printErr := dprint.PrintError(os.Stderr, errorFormat, info); printErr == nil {
+			os.Exit(info.ExitCode)
+		}
+	}
+
+	if ok {
+		cliErr.print()
 		cliErr.Exit()
 	}
 
@@ -141,10 +264,7 @@ func PrintError(err error) {
 	}
 
 	if cliErr, ok := err.(*CLIError); ok {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", cliErr.Message)
-		if cliErr.Cause != nil {
-			fmt.Fprintf(os.Stderr, "  Cause: %v\n", cliErr.Cause)
-		}
+		cliErr.print()
 	} else {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	}
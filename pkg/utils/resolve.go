@@ -0,0 +1,396 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+)
+
+var (
+	channelMention = regexp.MustCompile(`^<#(\d+)>$`)
+	roleMention    = regexp.MustCompile(`^<@&(\d+)>$`)
+	userMention    = regexp.MustCompile(`^<@!?(\d+)>$`)
+	emojiMention   = regexp.MustCompile(`^<a?:(\w+):(\d+)>$`)
+)
+
+// memberSearchLimit is the most members Discord returns for a search
+const memberSearchLimit = 1000
+
+// Resolver turns names and pasted mentions into IDs so arguments can be
+// given as #general, @Moderators, username or :pepe: as well as snowflakes.
+// Plain IDs are returned without an API call. Lookups that need a guild
+// search every guild the bot is in when none is given.
+type Resolver struct {
+	client   discord.DiscordAPI
+	guilds   []discord.DiscordGuild
+	channels map[string][]*discordgo.Channel
+	roles    map[string][]*discordgo.Role
+	emojis   map[string][]*discordgo.Emoji
+}
+
+// NewResolver creates a Resolver that looks names up through client
+func NewResolver(client discord.DiscordAPI) *Resolver {
+	return &Resolver{
+		client:   client,
+		channels: make(map[string][]*discordgo.Channel),
+		roles:    make(map[string][]*discordgo.Role),
+		emojis:   make(map[string][]*discordgo.Emoji),
+	}
+}
+
+// IsID reports whether s is a snowflake ID
+func IsID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Guild resolves a guild ID or name
+func (r *Resolver) Guild(s string) (string, error) {
+	if s == "" || IsID(s) {
+		return s, nil
+	}
+	guilds, err := r.allGuilds()
+	if err != nil {
+		return "", err
+	}
+	var matches []match
+	for _, g := range guilds {
+		if strings.EqualFold(g.Name, s) {
+			matches = append(matches, match{g.Name, g.ID})
+		}
+	}
+	return pickMatch("guild", s, matches)
+}
+
+// Channel resolves a channel ID, <#id> mention or name (with or without #)
+func (r *Resolver) Channel(guildID, s string) (string, error) {
+	if s == "" || IsID(s) {
+		return s, nil
+	}
+	if m := channelMention.FindStringSubmatch(s); m != nil {
+		return m[1], nil
+	}
+	name := strings.TrimPrefix(s, "#")
+	var matches []match
+	err := r.eachGuild(guildID, func(guildID string) error {
+		channels, err := r.guildChannels(guildID)
+		if err != nil {
+			return err
+		}
+		for _, ch := range channels {
+			if strings.EqualFold(ch.Name, name) {
+				matches = append(matches, match{"#" + ch.Name, ch.ID})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return pickMatch("channel", s, matches)
+}
+
+// Role resolves a role ID, <@&id> mention or name (with or without @)
+func (r *Resolver) Role(guildID, s string) (string, error) {
+	if s == "" || IsID(s) {
+		return s, nil
+	}
+	if m := roleMention.FindStringSubmatch(s); m != nil {
+		return m[1], nil
+	}
+	name := strings.TrimPrefix(s, "@")
+	var matches []match
+	err := r.eachGuild(guildID, func(guildID string) error {
+		roles, err := r.guildRoles(guildID)
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			if strings.EqualFold(strings.TrimPrefix(role.Name, "@"), name) {
+				matches = append(matches, match{"@" + strings.TrimPrefix(role.Name, "@"), role.ID})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return pickMatch("role", s, matches)
+}
+
+// User resolves a user ID, <@id> mention, username, global name or
+// nickname (with or without @). Names are searched among guild members.
+func (r *Resolver) User(guildID, s string) (string, error) {
+	if s == "" || IsID(s) {
+		return s, nil
+	}
+	if m := userMention.FindStringSubmatch(s); m != nil {
+		return m[1], nil
+	}
+	name := strings.TrimPrefix(s, "@")
+	discriminator := ""
+	if i := strings.LastIndex(name, "#"); i > 0 {
+		name, discriminator = name[:i], name[i+1:]
+	}
+
+	seen := make(map[string]bool)
+	var matches []match
+	err := r.eachGuild(guildID, func(guildID string) error {
+		members, err := r.client.SearchGuildMembers(guildID, name, memberSearchLimit)
+		if err != nil {
+			return DiscordErrorf("failed to search members: %w", err)
+		}
+		for _, m := range members {
+			if seen[m.User.ID] {
+				continue
+			}
+			if discriminator != "" && m.User.Discriminator != discriminator {
+				continue
+			}
+			if strings.EqualFold(m.User.Username, name) || strings.EqualFold(m.User.GlobalName, name) || strings.EqualFold(m.Nick, name) {
+				seen[m.User.ID] = true
+				matches = append(matches, match{m.User.Username, m.User.ID})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return pickMatch("user", s, matches)
+}
+
+// Emoji resolves a custom emoji ID, <:name:id> mention or name (with or
+// without colons)
+func (r *Resolver) Emoji(guildID, s string) (string, error) {
+	if s == "" || IsID(s) {
+		return s, nil
+	}
+	if m := emojiMention.FindStringSubmatch(s); m != nil {
+		return m[2], nil
+	}
+	emoji, err := r.findEmoji(guildID, s)
+	if err != nil {
+		return "", err
+	}
+	return emoji.ID, nil
+}
+
+// Reaction turns an emoji argument into the form the reaction endpoints
+// expect: unicode emoji are kept, custom emoji become name:id
+func (r *Resolver) Reaction(guildID, s string) (string, error) {
+	if m := emojiMention.FindStringSubmatch(s); m != nil {
+		return m[1] + ":" + m[2], nil
+	}
+	if len(s) < 3 || !strings.HasPrefix(s, ":") || !strings.HasSuffix(s, ":") {
+		return s, nil
+	}
+	emoji, err := r.findEmoji(guildID, s)
+	if err != nil {
+		return "", err
+	}
+	return emoji.Name + ":" + emoji.ID, nil
+}
+
+// Roles resolves a list of role IDs, mentions or names
+func (r *Resolver) Roles(guildID string, values []string) ([]string, error) {
+	return resolveEach(values, func(s string) (string, error) { return r.Role(guildID, s) })
+}
+
+// Channels resolves a list of channel IDs, mentions or names
+func (r *Resolver) Channels(guildID string, values []string) ([]string, error) {
+	return resolveEach(values, func(s string) (string, error) { return r.Channel(guildID, s) })
+}
+
+// Users resolves a list of user IDs, mentions or names
+func (r *Resolver) Users(guildID string, values []string) ([]string, error) {
+	return resolveEach(values, func(s string) (string, error) { return r.User(guildID, s) })
+}
+
+// ChannelGuild returns the guild a channel belongs to, "" for DMs
+func (r *Resolver) ChannelGuild(channelID string) (string, error) {
+	ch, err := r.client.GetChannel(channelID)
+	if err != nil {
+		return "", DiscordErrorf("failed to get channel: %w", err)
+	}
+	return ch.GuildID, nil
+}
+
+// idFlags are the flags NewCLIContext resolves. guild comes first so the
+// others are looked up in it.
+var idFlags = []struct {
+	name    string
+	resolve func(r *Resolver, guildID, s string) (string, error)
+}{
+	{"guild", func(r *Resolver, _, s string) (string, error) { return r.Guild(s) }},
+	{"channel", (*Resolver).Channel},
+	{"parent", (*Resolver).Channel},
+	{"afk-channel", (*Resolver).Channel},
+	{"role", (*Resolver).Role},
+	{"user", (*Resolver).User},
+}
+
+// resolveFlags replaces names and mentions given to ID flags with the IDs
+// they refer to, so commands can keep reading c.String("guild") and friends
+func (r *Resolver) resolveFlags(c *cli.Command) error {
+	guildID := ""
+	for _, f := range idFlags {
+		if !c.IsSet(f.name) {
+			continue
+		}
+		value := c.String(f.name)
+		id, err := f.resolve(r, guildID, value)
+		if err != nil {
+			return err
+		}
+		if id != value {
+			if err := c.Set(f.name, id); err != nil {
+				return fmt.Errorf("failed to set --%s: %w", f.name, err)
+			}
+		}
+		if f.name == "guild" {
+			guildID = id
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) findEmoji(guildID, s string) (*discordgo.Emoji, error) {
+	name := strings.Trim(s, ":")
+	var found []*discordgo.Emoji
+	var matches []match
+	err := r.eachGuild(guildID, func(guildID string) error {
+		emojis, err := r.guildEmojis(guildID)
+		if err != nil {
+			return err
+		}
+		for _, e := range emojis {
+			if strings.EqualFold(e.Name, name) {
+				found = append(found, e)
+				matches = append(matches, match{":" + e.Name + ":", e.ID})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pickMatch("emoji", s, matches); err != nil {
+		return nil, err
+	}
+	return found[0], nil
+}
+
+// eachGuild calls fn for guildID, or for every guild of the bot when empty
+func (r *Resolver) eachGuild(guildID string, fn func(guildID string) error) error {
+	if guildID != "" {
+		return fn(guildID)
+	}
+	guilds, err := r.allGuilds()
+	if err != nil {
+		return err
+	}
+	for _, g := range guilds {
+		if err := fn(g.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) allGuilds() ([]discord.DiscordGuild, error) {
+	if r.guilds != nil {
+		return r.guilds, nil
+	}
+	guilds := []discord.DiscordGuild{}
+	for g, err := range discord.IterGuilds(r.client, "", "") {
+		if err != nil {
+			return nil, DiscordErrorf("failed to list guilds: %w", err)
+		}
+		guilds = append(guilds, g)
+	}
+	r.guilds = guilds
+	return guilds, nil
+}
+
+func (r *Resolver) guildChannels(guildID string) ([]*discordgo.Channel, error) {
+	if channels, ok := r.channels[guildID]; ok {
+		return channels, nil
+	}
+	channels, err := r.client.GetGuildChannels(guildID)
+	if err != nil {
+		return nil, DiscordErrorf("failed to list channels: %w", err)
+	}
+	r.channels[guildID] = channels
+	return channels, nil
+}
+
+func (r *Resolver) guildRoles(guildID string) ([]*discordgo.Role, error) {
+	if roles, ok := r.roles[guildID]; ok {
+		return roles, nil
+	}
+	roles, err := r.client.GetGuildRoles(guildID)
+	if err != nil {
+		return nil, DiscordErrorf("failed to list roles: %w", err)
+	}
+	r.roles[guildID] = roles
+	return roles, nil
+}
+
+func (r *Resolver) guildEmojis(guildID string) ([]*discordgo.Emoji, error) {
+	if emojis, ok := r.emojis[guildID]; ok {
+		return emojis, nil
+	}
+	emojis, err := r.client.GetGuildEmojis(guildID)
+	if err != nil {
+		return nil, DiscordErrorf("failed to list emojis: %w", err)
+	}
+	r.emojis[guildID] = emojis
+	return emojis, nil
+}
+
+func resolveEach(values []string, resolve func(string) (string, error)) ([]string, error) {
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		id, err := resolve(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// match is a named object a name resolved to
+type match struct {
+	name, id string
+}
+
+// pickMatch returns the ID of the single match, or an error naming every
+// candidate when the name is ambiguous
+func pickMatch(kind, s string, matches []match) (string, error) {
+	switch len(matches) {
+	case 0:
+		return "", NotFoundErrorf("%s %q not found", kind, s)
+	case 1:
+		return matches[0].id, nil
+	default:
+		candidates := make([]string, len(matches))
+		for i, m := range matches {
+			candidates[i] = fmt.Sprintf("%s (%s)", m.name, m.id)
+		}
+		return "", ValidationErrorf("%s %q is ambiguous, it matches %s; use an ID or mention instead", kind, s, strings.Join(candidates, ", "))
+	}
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/FlameInTheDark/dccli/pkg/discord"
+	"github.com/FlameInTheDark/dccli/pkg/dprint"
+)
+
+// restErrorBody is the JSON body of a failed Discord API request
+type restErrorBody struct {
+	Code       int             `json:"code"`
+	Message    string          `json:"message"`
+	Errors     json.RawMessage `json:"errors"`
+	RetryAfter float64         `json:"retry_after"`
+}
+
+func isRESTError(err error) bool {
+	var restErr *discordgo.RESTError
+	var rateErr *discordgo.RateLimitError
+	return errors.As(err, &restErr) || errors.As(err, &rateErr)
+}
+
+// addRESTDetails fills in the details of the REST error the CLIError wraps,
+// if any, and replaces its raw "HTTP 404 Not Found, {...}" text in Message
+// with Discord's message. Objects missing from a list are not found too.
+func (e *CLIError) addRESTDetails() {
+	var rateErr *discordgo.RateLimitError
+	if errors.As(e.wrapped, &rateErr) {
+		e.HTTPStatus = http.StatusTooManyRequests
+		e.RetryAfter = rateErr.RetryAfter
+		return
+	}
+
+	if errors.Is(e.wrapped, discord.ErrNotFound) {
+		e.Code = ExitNotFound
+		return
+	}
+
+	var restErr *discordgo.RESTError
+	if !errors.As(e.wrapped, &restErr) || restErr.Response == nil {
+		return
+	}
+	e.HTTPStatus = restErr.Response.StatusCode
+	switch e.HTTPStatus {
+	case http.StatusNotFound:
+		e.Code = ExitNotFound
+	case http.StatusForbidden:
+		e.Code = ExitPermissionDenied
+	}
+
+	var body restErrorBody
+	if err := json.Unmarshal(restErr.ResponseBody, &body); err != nil {
+		return
+	}
+	e.DiscordCode = body.Code
+	e.FieldErrors = fieldErrors(body.Errors, "")
+	e.RetryAfter = time.Duration(body.RetryAfter * float64(time.Second))
+	if e.RetryAfter == 0 {
+		if seconds, err := strconv.ParseFloat(restErr.Response.Header.Get("Retry-After"), 64); err == nil {
+			e.RetryAfter = time.Duration(seconds * float64(time.Second))
+		}
+	}
+
+	if body.Message != "" {
+		message := body.Message
+		if body.Code != 0 {
+			message += " (code " + strconv.Itoa(body.Code) + ")"
+		}
+		e.Message = strings.Replace(e.Message, restErr.Error(), message, 1)
+	}
+}
+
+// fieldErrors flattens Discord's nested errors object, where every rejected
+// field has an _errors list, e.g. {"embeds": {"0": {"title": {"_errors": [...]}}}}
+func fieldErrors(raw json.RawMessage, path string) []dprint.FieldError {
+	var fields map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &fields) != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result []dprint.FieldError
+	for _, key := range keys {
+		if key == "_errors" {
+			var list []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			json.Unmarshal(fields[key], &list)
+			for _, item := range list {
+				result = append(result, dprint.FieldError{Field: path, Code: item.Code, Message: item.Message})
+			}
+			continue
+		}
+		name := key
+		if path != "" {
+			name = path + "." + key
+		}
+		result = append(result, fieldErrors(fields[key], name)...)
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/FlameInTheDark/dccli/pkg/utils/snowflake"
+)
+
+// ParseTime accepts RFC3339, a date (YYYY-MM-DD) or a duration before now.
+// A duration is whole weeks (w) and days (d), in that order, followed by a
+// Go duration (h, m, s, ...); each part is optional but one is needed, e.g.
+// 90m, 24h, 7d, 2w or 1d12h. An empty value returns the zero time.
+func ParseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, ok := parseAgo(s); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a time or duration (e.g. 2025-01-01, 90m, 7d, 1d12h)", s)
+}
+
+// parseAgo parses the duration form of ParseTime. Weeks and days are
+// calendar days, so 1d is the same time of day yesterday across DST changes.
+func parseAgo(s string) (time.Time, bool) {
+	rest := s
+	days := 0
+	for _, unit := range []struct {
+		suffix byte
+		days   int
+	}{{'w', 7}, {'d', 1}} {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 0 || i == len(rest) || rest[i] != unit.suffix {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return time.Time{}, false
+		}
+		days += n * unit.days
+		rest = rest[i+1:]
+	}
+
+	var d time.Duration
+	if rest != "" {
+		// A sign only makes sense in front of the whole value
+		if rest != s && (rest[0] == '-' || rest[0] == '+') {
+			return time.Time{}, false
+		}
+		var err error
+		if d, err = time.ParseDuration(rest); err != nil {
+			return time.Time{}, false
+		}
+	}
+	return time.Now().AddDate(0, 0, -days).Add(-d), true
+}
+
+// SnowflakeBefore converts the value of a before cursor to a snowflake. An
+// ID is returned as is; a time or duration (see ParseTime) becomes the first
+// snowflake of that moment, so only IDs created earlier come before it.
+func SnowflakeBefore(s string) (string, error) {
+	if s == "" || IsID(s) {
+		return s, nil
+	}
+	t, err := ParseTime(s)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse %q as an ID, time or duration", s)
+	}
+	return snowflake.FromTime(t), nil
+}
+
+// SnowflakeAfter converts the value of an after cursor to a snowflake. An ID
+// is returned as is; a time or duration (see ParseTime) becomes the last
+// snowflake before that moment, so IDs created at it are included.
+func SnowflakeAfter(s string) (string, error) {
+	if s == "" || IsID(s) {
+		return s, nil
+	}
+	t, err := ParseTime(s)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse %q as an ID, time or duration", s)
+	}
+	return snowflake.Previous(snowflake.FromTime(t)), nil
+}
@@ -0,0 +1,76 @@
+// Package snowflake converts between Discord snowflake IDs and the times
+// they were created. It imports nothing from dccli, so every package can use
+// it.
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Epoch is the first second of 2015 in Unix milliseconds, where snowflake
+// time starts
+const Epoch = 1420070400000
+
+// Snowflake is a Discord ID taken apart. The top 42 bits count milliseconds
+// since Epoch, followed by 5 bits of worker, 5 bits of process and a 12 bit
+// increment.
+type Snowflake struct {
+	ID        string    `json:"id" table:"ID"`
+	Created   time.Time `json:"created" table:"Created"`
+	Worker    int       `json:"worker" table:"Worker"`
+	Process   int       `json:"process" table:"Process"`
+	Increment int       `json:"increment" table:"Increment"`
+}
+
+// Decode splits a snowflake ID into its parts
+func Decode(id string) (*Snowflake, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a snowflake ID", id)
+	}
+	return &Snowflake{
+		ID:        id,
+		Created:   created(n).UTC(),
+		Worker:    int(n >> 17 & 0x1f),
+		Process:   int(n >> 12 & 0x1f),
+		Increment: int(n & 0xfff),
+	}, nil
+}
+
+// Time returns when a snowflake was created
+func Time(id string) (time.Time, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a snowflake ID", id)
+	}
+	return created(n), nil
+}
+
+func created(n uint64) time.Time {
+	return time.UnixMilli(int64(n>>22) + Epoch)
+}
+
+// FromTime returns the smallest snowflake created at t
+func FromTime(t time.Time) string {
+	ms := t.UnixMilli() - Epoch
+	if ms < 0 {
+		ms = 0
+	}
+	return strconv.FormatUint(uint64(ms)<<22, 10)
+}
+
+// Previous returns id - 1, so a walk after it includes id
+func Previous(id string) string {
+	n, _ := strconv.ParseUint(id, 10, 64)
+	return strconv.FormatUint(max(n, 1)-1, 10)
+}
+
+// Less orders snowflake IDs numerically
+func Less(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	// The example from Discord's API reference
+	s, err := Decode("175928847299117063")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Snowflake{
+		ID:        "175928847299117063",
+		Created:   time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC),
+		Worker:    1,
+		Process:   0,
+		Increment: 7,
+	}
+	if *s != want {
+		t.Errorf("Decode = %+v, want %+v", *s, want)
+	}
+	if _, err := Decode("general"); err == nil {
+		t.Error("Decode accepted a name")
+	}
+}
+
+func TestFromTime(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	id := FromTime(at)
+	created, err := Time(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Equal(at) {
+		t.Errorf("Time(FromTime(%s)) = %s", at, created)
+	}
+	if earlier, _ := Time(Previous(id)); !earlier.Before(at) {
+		t.Errorf("Previous(%s) was created at %s, want before %s", id, earlier, at)
+	}
+	if got := FromTime(time.Unix(0, 0)); got != "0" {
+		t.Errorf("FromTime before the epoch = %s, want 0", got)
+	}
+}
+
+func TestLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"9", "10", true},
+		{"10", "9", false},
+		{"175928847299117063", "175928847299117064", true},
+		{"175928847299117063", "175928847299117063", false},
+	}
+	for _, tt := range tests {
+		if got := Less(tt.a, tt.b); got != tt.want {
+			t.Errorf("Less(%s, %s) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Now()
+	days := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2025-01-02T03:04:05Z", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2025-01-02", time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"24h", now.Add(-24 * time.Hour)},
+		{"1h30m", now.Add(-90 * time.Minute)},
+		{"7d", days(7)},
+		{"2w", days(14)},
+		{"1d12h", days(1).Add(-12 * time.Hour)},
+		{"2w3d", days(17)},
+		{"1w2d3h4m", days(9).Add(-3*time.Hour - 4*time.Minute)},
+		{"0d", now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTime(tt.value)
+			if err != nil {
+				t.Fatalf("ParseTime: %v", err)
+			}
+			// Durations are relative to the time of the call
+			if diff := got.Sub(tt.want).Abs(); diff > time.Minute {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeEmpty(t *testing.T) {
+	got, err := ParseTime("")
+	if err != nil || !got.IsZero() {
+		t.Errorf("ParseTime(\"\") = %v, %v, want the zero time", got, err)
+	}
+}
+
+func TestParseTimeInvalid(t *testing.T) {
+	for _, value := range []string{"d", "w", "7", "1.5d", "3d2w", "1d1d", "1d-2h", "7 d", "yesterday", "2025-13-01"} {
+		if got, err := ParseTime(value); err == nil {
+			t.Errorf("ParseTime(%q) = %v, want an error", value, got)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+
+	"github.com/FlameInTheDark/dccli/pkg/cfg"
+)
+
+// Where the vault passphrase is read from, besides the terminal
+const (
+	PassphraseFDFlag    = "passphrase-fd"
+	PassphraseEnv       = "DCLI_VAULT_PASSPHRASE"
+	NewPassphraseFDFlag = "new-passphrase-fd"
+	NewPassphraseEnv    = "DCLI_VAULT_NEW_PASSPHRASE"
+)
+
+// UnlockVault reads the vault passphrase and returns the vault key.
+// The passphrase comes from --passphrase-fd, DCLI_VAULT_PASSPHRASE or a
+// terminal prompt, in that order.
+func UnlockVault(c *cli.Command, config *cfg.Config) (*cfg.VaultKey, error) {
+	if config.Vault == nil {
+		return nil, ConfigError("no vault configured, add a bot with --encrypt first")
+	}
+	passphrase, err := ReadPassphrase(c, PassphraseFDFlag, PassphraseEnv, "Vault passphrase: ", false)
+	if err != nil {
+		return nil, err
+	}
+	key, err := config.Vault.Unlock(passphrase)
+	if err != nil {
+		if errors.Is(err, cfg.ErrWrongPassphrase) {
+			return nil, ConfigError("wrong vault passphrase")
+		}
+		return nil, ConfigErrorf("failed to unlock vault: %w", err)
+	}
+	return key, nil
+}
+
+// ReadPassphrase reads a passphrase from the file descriptor given in
+// fdFlag, the envVar environment variable or, when stdin is a terminal, a
+// prompt. confirm asks twice when prompting, for passphrases being set.
+func ReadPassphrase(c *cli.Command, fdFlag, envVar, prompt string, confirm bool) ([]byte, error) {
+	var passphrase []byte
+	switch {
+	case c.IsSet(fdFlag):
+		fd := c.Int(fdFlag)
+		file := os.NewFile(uintptr(fd), fdFlag)
+		if file == nil {
+			return nil, ValidationErrorf("invalid --%s %d", fdFlag, fd)
+		}
+		line, err := bufio.NewReader(file).ReadBytes('\n')
+		if err != nil && len(line) == 0 {
+			return nil, ConfigErrorf("failed to read passphrase from fd %d: %w", fd, err)
+		}
+		passphrase = bytes.TrimRight(line, "\r\n")
+	case os.Getenv(envVar) != "":
+		passphrase = []byte(os.Getenv(envVar))
+	default:
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, ConfigErrorf("vault passphrase required: set %s, pass --%s or run in a terminal", envVar, fdFlag)
+		}
+		var err error
+		passphrase, err = promptPassphrase(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if confirm {
+			again, err := promptPassphrase("Repeat passphrase: ")
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(passphrase, again) {
+				return nil, ValidationError("passphrases do not match")
+			}
+		}
+	}
+	if len(passphrase) == 0 {
+		return nil, ValidationError("vault passphrase must not be empty")
+	}
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, ConfigErrorf("failed to read passphrase: %w", err)
+	}
+	return passphrase, nil
+}
+
+// vaultKey unlocks the vault, or creates it when the config has none yet
+func vaultKey(c *cli.Command, config *cfg.Config) (*cfg.VaultKey, error) {
+	if config.Vault != nil {
+		return UnlockVault(c, config)
+	}
+	passphrase, err := ReadPassphrase(c, PassphraseFDFlag, PassphraseEnv, "New vault passphrase: ", true)
+	if err != nil {
+		return nil, err
+	}
+	vault, key, err := cfg.NewVault(passphrase)
+	if err != nil {
+		return nil, ConfigErrorf("failed to create vault: %w", err)
+	}
+	config.Vault = vault
+	return key, nil
+}
+
+// SealedToken is a bot token encrypted before the config lock is taken, so
+// the passphrase prompt and the key derivation do not hold the lock
+type SealedToken struct {
+	vault     *cfg.Vault
+	created   bool
+	encrypted string
+}
+
+// SealBotToken encrypts token with the vault of config, the config as read
+// before taking the lock. The vault is created when config has none yet.
+func SealBotToken(c *cli.Command, config *cfg.Config, token string) (*SealedToken, error) {
+	created := config.Vault == nil
+	key, err := vaultKey(c, config)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := key.Encrypt(token)
+	if err != nil {
+		return nil, ConfigErrorf("failed to encrypt token: %w", err)
+	}
+	return &SealedToken{vault: config.Vault, created: created, encrypted: encrypted}, nil
+}
+
+// Apply stores the sealed token in bot. config is the config read under the
+// lock, whose vault must still be the one the token was sealed with.
+func (s *SealedToken) Apply(config *cfg.Config, bot *cfg.BotConfig) error {
+	switch {
+	case config.Vault == nil && s.created:
+		config.Vault = s.vault
+	case config.Vault == nil || config.Vault.Salt != s.vault.Salt:
+		return ConfigError("the vault changed while the token was being encrypted, run the command again")
+	}
+	bot.Bot = cfg.Bot{EncryptedToken: s.encrypted}
+	return nil
+}
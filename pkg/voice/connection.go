@@ -14,6 +14,8 @@ type Connection struct {
 	voice      *discordgo.VoiceConnection
 	guildID    string
 	channelID  string
+	mute       bool
+	deaf       bool
 	mu         sync.RWMutex
 	connected  bool
 	connecting chan struct{}
@@ -39,7 +41,7 @@ func (c *Connection) Join() error {
 		return nil
 	}
 
-	voice, err := c.session.ChannelVoiceJoin(c.guildID, c.channelID, false, false)
+	voice, err := c.session.ChannelVoiceJoin(c.guildID, c.channelID, c.mute, c.deaf)
 	if err != nil {
 		return fmt.Errorf("failed to join voice channel: %w", err)
 	}
@@ -54,6 +56,9 @@ func (c *Connection) Join() error {
 	for {
 		select {
 		case <-timeout:
+			voice.Disconnect()
+			c.voice = nil
+			c.connected = false
 			return fmt.Errorf("voice connection timeout")
 		case <-ticker.C:
 			if voice.Ready {
@@ -121,13 +126,14 @@ func NewConnectionManager(session *discordgo.Session) *ConnectionManager {
 	}
 }
 
-// Join connects to a voice channel in a guild
-func (m *ConnectionManager) Join(guildID, channelID string) (*Connection, error) {
+// Join connects to a voice channel in a guild. A mute connection can only
+// receive audio; a deaf connection can only send it.
+func (m *ConnectionManager) Join(guildID, channelID string, mute, deaf bool) (*Connection, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if conn, exists := m.connections[guildID]; exists {
-		if conn.IsConnected() && conn.GetChannelID() == channelID {
+		if conn.IsConnected() && conn.GetChannelID() == channelID && conn.mute == mute && conn.deaf == deaf {
 			return conn, nil
 		}
 		conn.Leave()
@@ -139,6 +145,8 @@ func (m *ConnectionManager) Join(guildID, channelID string) (*Connection, error)
 		ChannelID: channelID,
 	})
 	conn.session = m.session
+	conn.mute = mute
+	conn.deaf = deaf
 	if err := conn.Join(); err != nil {
 		return nil, err
 	}
@@ -60,7 +60,7 @@ func (p *Player) PlayFile(filePath string) error {
 	go func() {
 		select {
 		case <-sigChan:
-			fmt.Println("\nReceived interrupt signal, stopping playback...")
+			fmt.Fprintln(os.Stderr, "\nReceived interrupt signal, stopping playback...")
 			cancel()
 			p.Stop()
 		case <-p.stopChan:
@@ -68,7 +68,7 @@ func (p *Player) PlayFile(filePath string) error {
 		}
 	}()
 
-	fmt.Printf("Playing %s... Press Ctrl+C to stop\n", filePath)
+	fmt.Fprintf(os.Stderr, "Playing %s... Press Ctrl+C to stop\n", filePath)
 
 	err := p.play(ctx, filePath)
 
@@ -287,7 +287,7 @@ func (p *Player) Stop() error {
 	select {
 	case <-done:
 	case <-time.After(5 * time.Second):
-		fmt.Println("Warning: timeout waiting for playback to stop")
+		fmt.Fprintln(os.Stderr, "Warning: timeout waiting for playback to stop")
 	}
 
 	return nil
@@ -80,9 +80,9 @@ func (r *Recorder) RecordToFile(outputPath string) error {
 		return fmt.Errorf("voice connection is nil")
 	}
 
-	fmt.Printf("Recording... Press Ctrl+C to stop\n")
+	fmt.Fprintf(os.Stderr, "Recording... Press Ctrl+C to stop\n")
 	startTime := time.Now().Unix()
-	fmt.Printf("Files will be saved to: %s with pattern %d_<SSRC>_%s.ogg\n", absDir, startTime, baseName)
+	fmt.Fprintf(os.Stderr, "Files will be saved to: %s with pattern %d_<SSRC>_%s.ogg\n", absDir, startTime, baseName)
 
 	files := make(map[uint32]media.Writer)
 	var filesMu sync.Mutex
@@ -110,27 +110,27 @@ func (r *Recorder) RecordToFile(outputPath string) error {
 					var err error
 					oggFile, err := os.Create(filename)
 					if err != nil {
-						fmt.Printf("failed to create file %s for SSRC %d: %v\n", filename, packet.SSRC, err)
+						fmt.Fprintf(os.Stderr, "failed to create file %s for SSRC %d: %v\n", filename, packet.SSRC, err)
 						filesMu.Unlock()
 						continue
 					}
 					file, err = oggwriter.NewWith(oggFile, 48000, 2)
 					if err != nil {
-						fmt.Printf("failed to create OGG writer for %s (SSRC %d): %v\n", filename, packet.SSRC, err)
+						fmt.Fprintf(os.Stderr, "failed to create OGG writer for %s (SSRC %d): %v\n", filename, packet.SSRC, err)
 						oggFile.Close()
 						filesMu.Unlock()
 						continue
 					}
-					fmt.Printf("Created file: %s\n", filename)
+					fmt.Fprintf(os.Stderr, "Created file: %s\n", filename)
 					files[packet.SSRC] = file
-					fmt.Printf("Started recording user with SSRC %d\n", packet.SSRC)
+					fmt.Fprintf(os.Stderr, "Started recording user with SSRC %d\n", packet.SSRC)
 				}
 				filesMu.Unlock()
 
 				rtpPacket := createRTPPacket(packet)
 				err := file.WriteRTP(rtpPacket)
 				if err != nil {
-					fmt.Printf("failed to write RTP packet for SSRC %d: %v\n", packet.SSRC, err)
+					fmt.Fprintf(os.Stderr, "failed to write RTP packet for SSRC %d: %v\n", packet.SSRC, err)
 				}
 			}
 		}
@@ -138,7 +138,7 @@ func (r *Recorder) RecordToFile(outputPath string) error {
 
 	select {
 	case <-sigChan:
-		fmt.Println("\nReceived interrupt signal, stopping recording...")
+		fmt.Fprintln(os.Stderr, "\nReceived interrupt signal, stopping recording...")
 	case <-r.stopChan:
 	}
 
@@ -148,18 +148,18 @@ func (r *Recorder) RecordToFile(outputPath string) error {
 	select {
 	case <-done:
 	case <-time.After(5 * time.Second):
-		fmt.Println("Warning: timeout waiting for recording to stop")
+		fmt.Fprintln(os.Stderr, "Warning: timeout waiting for recording to stop")
 	}
 
 	filesMu.Lock()
 	for ssrc, f := range files {
 		if err := f.Close(); err != nil {
-			fmt.Printf("failed to close file for SSRC %d: %v\n", ssrc, err)
+			fmt.Fprintf(os.Stderr, "failed to close file for SSRC %d: %v\n", ssrc, err)
 		}
 	}
 	filesMu.Unlock()
 
-	fmt.Println("Recording saved successfully")
+	fmt.Fprintln(os.Stderr, "Recording saved successfully")
 	return nil
 }
 